@@ -6,7 +6,7 @@
 - Метки (name, dependency, type, host, port, critical + status/detail)
 - HELP-строки
 - Бакеты histogram
-- Status enum полноту (8 серий)
+- Status enum полноту (9 серий)
 - Формат Prometheus text format
 """
 
@@ -28,7 +28,7 @@ ALL_METRICS = [HEALTH_METRIC, LATENCY_METRIC, STATUS_METRIC, DETAIL_METRIC]
 REQUIRED_LABELS = {"name", "group", "dependency", "type", "host", "port", "critical"}
 EXPECTED_BUCKETS = {0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1.0, 5.0}
 
-VALID_STATUSES = {"ok", "timeout", "connection_error", "dns_error",
+VALID_STATUSES = {"ok", "degraded", "timeout", "connection_error", "dns_error",
                   "auth_error", "tls_error", "unhealthy", "error"}
 
 EXPECTED_HELP = {
@@ -427,7 +427,7 @@ def main():
         print("  [+] Типы метрик совпадают")
         print("  [+] Обязательные метки присутствуют")
         print("  [+] Бакеты histogram совпадают")
-        print("  [+] Status enum полнота (8 серий)")
+        print("  [+] Status enum полнота (9 серий)")
         print("  [+] Зависимости совпадают")
         print("  [+] Типы зависимостей совпадают")
         print("  [+] HealthDetails JSON-структура идентична")
@@ -29,39 +29,100 @@ HEALTH_METRIC = "app_dependency_health"
 LATENCY_METRIC = "app_dependency_latency_seconds"
 REQUIRED_LABELS = {"name", "group", "dependency", "type", "host", "port", "critical"}
 EXPECTED_BUCKETS = [0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1.0, 5.0]
-VALID_TYPES = {"http", "grpc", "tcp", "postgres", "mysql", "redis", "amqp", "kafka", "ldap"}
+VALID_TYPES = {"http", "grpc", "tcp", "postgres", "mysql", "redis", "amqp", "kafka", "ldap",
+               "etcd", "consul", "clickhouse", "cassandra", "tls", "dns"}
 
 HELP_HEALTH = "Health status of a dependency (1 = healthy, 0 = unhealthy)"
 HELP_LATENCY = "Latency of dependency health check in seconds"
 
 STATUS_METRIC = "app_dependency_status"
 DETAIL_METRIC = "app_dependency_status_detail"
-VALID_STATUSES = {"ok", "timeout", "connection_error", "dns_error",
+VALID_STATUSES = {"ok", "degraded", "timeout", "connection_error", "dns_error",
                   "auth_error", "tls_error", "unhealthy", "error"}
+HEALTHY_STATUSES = {"ok", "degraded"}
 HELP_STATUS = "Category of the last check result"
 HELP_DETAIL = "Detailed reason of the last check result"
 
+# Optional per-endpoint metrics (spec §11): exported only by checkers that
+# measure the value.
+TLS_EXPIRY_METRIC = "app_dependency_tls_cert_expiry_timestamp_seconds"
+CONSUMER_LAG_METRIC = "app_dependency_consumer_lag"
+REPLICATION_LAG_METRIC = "app_dependency_replication_lag_seconds"
+CONNECT_LATENCY_METRIC = "app_dependency_connect_latency_seconds"
+HELP_TLS_EXPIRY = "Expiry time of the dependency TLS leaf certificate as a Unix timestamp"
+HELP_CONSUMER_LAG = "Total lag in messages of the Kafka consumer group checked by the dependency"
+HELP_REPLICATION_LAG = "Replication lag of the database replica in seconds"
+HELP_CONNECT_LATENCY = "Latency of establishing a new connection to the dependency in seconds"
+OPTIONAL_METRICS = {
+    TLS_EXPIRY_METRIC: HELP_TLS_EXPIRY,
+    CONSUMER_LAG_METRIC: HELP_CONSUMER_LAG,
+    REPLICATION_LAG_METRIC: HELP_REPLICATION_LAG,
+    CONNECT_LATENCY_METRIC: HELP_CONNECT_LATENCY,
+}
+
 DETAIL_TO_STATUS = {
-    "ok": "ok", "timeout": "timeout",
+    "ok": "ok", "latency_slo_exceeded": "degraded", "tls_expiring": "ok", "health_warn": "ok",
+    "timeout": "timeout",
     "connection_refused": "connection_error", "network_unreachable": "connection_error",
-    "host_unreachable": "connection_error", "dns_error": "dns_error",
-    "auth_error": "auth_error", "tls_error": "tls_error",
+    "host_unreachable": "connection_error", "connection_reset": "connection_error",
+    "connection_closed": "connection_error", "too_many_connections": "connection_error",
+    "dns_error": "dns_error", "dns_nxdomain": "dns_error", "dns_servfail": "dns_error",
+    "dns_refused": "dns_error",
+    "auth_error": "auth_error", "tls_error": "tls_error", "tls_expired": "tls_error",
+    "tls_unknown_authority": "tls_error", "tls_hostname_mismatch": "tls_error",
+    "tls_pin_mismatch": "tls_error",
     "unhealthy": "unhealthy", "no_brokers": "unhealthy",
     "grpc_not_serving": "unhealthy", "grpc_unknown": "unhealthy",
+    "http_4xx": "unhealthy", "http_5xx": "unhealthy",
+    "body_mismatch": "unhealthy", "health_down": "unhealthy",
+    "health_out_of_service": "unhealthy", "health_unknown": "unhealthy",
+    "wrong_role": "unhealthy", "replication_lag": "unhealthy", "replication_stopped": "unhealthy",
+    "replica_delay": "unhealthy", "no_master": "unhealthy", "cluster_fail": "unhealthy",
+    "queue_missing": "unhealthy", "queue_depth": "unhealthy", "queue_consumers": "unhealthy",
+    "topic_missing": "unhealthy", "no_leader": "unhealthy", "under_replicated": "unhealthy",
+    "consumer_group_missing": "unhealthy", "consumer_lag": "unhealthy",
+    "dns_no_records": "unhealthy", "dns_mismatch": "unhealthy",
     "error": "error", "pool_exhausted": "error", "query_error": "error",
 }
 
 VALID_DETAILS_BY_TYPE = {
-    "http": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error", "error"},
+    "http": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error",
+             "body_mismatch", "health_down", "health_out_of_service", "health_unknown", "health_warn",
+             "error"},
     "grpc": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error",
              "grpc_not_serving", "grpc_unknown", "error"},
     "tcp": {"ok", "timeout", "connection_refused", "dns_error", "error"},
-    "postgres": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error", "error"},
-    "mysql": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error", "error"},
-    "redis": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "unhealthy", "error"},
-    "amqp": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error", "unhealthy", "error"},
-    "kafka": {"ok", "timeout", "connection_refused", "dns_error", "no_brokers", "error"},
+    "postgres": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error",
+                 "wrong_role", "replication_lag", "error"},
+    "mysql": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error",
+              "wrong_role", "replication_lag", "replication_stopped", "error"},
+    "redis": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "unhealthy",
+              "no_master", "cluster_fail", "error"},
+    "amqp": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error", "unhealthy",
+             "queue_missing", "queue_depth", "queue_consumers", "error"},
+    "kafka": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "no_brokers",
+              "topic_missing", "no_leader", "under_replicated", "consumer_group_missing", "consumer_lag",
+              "error"},
     "ldap": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error", "unhealthy", "error"},
+    "etcd": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error", "no_leader",
+             "unhealthy", "error"},
+    "consul": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error", "no_leader",
+               "http_4xx", "http_5xx", "unhealthy", "error"},
+    "clickhouse": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error",
+                   "replica_delay", "error"},
+    "cassandra": {"ok", "timeout", "connection_refused", "dns_error", "auth_error", "tls_error",
+                  "unhealthy", "error"},
+    "tls": {"ok", "timeout", "connection_refused", "dns_error", "tls_error", "tls_pin_mismatch", "error"},
+    "dns": {"ok", "timeout", "dns_error", "dns_refused", "dns_no_records", "dns_mismatch", "error"},
+}
+
+# Details valid for every checker type: finer-grained platform errors, the
+# latency SLO and the TLS expiry warning (spec §9.3).
+CORE_DETAILS = {
+    "latency_slo_exceeded", "tls_expiring",
+    "connection_reset", "host_unreachable", "network_unreachable", "connection_closed",
+    "too_many_connections", "dns_nxdomain", "dns_servfail",
+    "tls_expired", "tls_unknown_authority", "tls_hostname_mismatch",
 }
 
 
@@ -311,15 +372,15 @@ def check_health_values(metrics: dict) -> list[CheckResult]:
     return results
 
 
-def check_histogram_buckets(metrics: dict) -> list[CheckResult]:
+def check_histogram_buckets(metrics: dict, name: str = LATENCY_METRIC) -> list[CheckResult]:
     """Проверить наличие histogram бакетов."""
     results = []
-    if LATENCY_METRIC not in metrics:
-        return [CheckResult("histogram_buckets", False, f"метрика {LATENCY_METRIC} не найдена")]
+    if name not in metrics:
+        return [CheckResult("histogram_buckets", False, f"метрика {name} не найдена")]
 
-    bucket_name = f"{LATENCY_METRIC}_bucket"
+    bucket_name = f"{name}_bucket"
     le_values = set()
-    for sample in metrics[LATENCY_METRIC]["samples"]:
+    for sample in metrics[name]["samples"]:
         if sample["name"] == bucket_name and "le" in sample["labels"]:
             le_val = sample["labels"]["le"]
             if le_val != "+Inf":
@@ -335,6 +396,47 @@ def check_histogram_buckets(metrics: dict) -> list[CheckResult]:
     return results
 
 
+def check_optional_metrics(metrics: dict) -> list[CheckResult]:
+    """Check the optional per-endpoint metrics (spec §11).
+
+    A metric that is absent passes: it is exported only when a checker
+    measures the value. A present metric must have the spec HELP text, the
+    required labels, non-negative values, and belong to an endpoint that
+    exports app_dependency_health.
+    """
+    results = []
+    endpoints = set()
+    for sample in metrics.get(HEALTH_METRIC, {}).get("samples", []):
+        endpoints.add(_endpoint_key(sample["labels"]))
+
+    for name, expected_help in OPTIONAL_METRICS.items():
+        if name not in metrics:
+            continue
+        results.append(check_help_text(metrics, name, expected_help))
+        results.extend(check_required_labels(metrics, name))
+        for sample in metrics[name]["samples"]:
+            if sample["name"] in (f"{name}_bucket", f"{name}_sum"):
+                continue
+            dep = sample["labels"].get("dependency", "?")
+            if sample["value"] < 0:
+                results.append(CheckResult(
+                    f"optional_{name}_{dep}", False,
+                    f"{name} = {sample['value']}, ожидалось >= 0",
+                ))
+            if _endpoint_key(sample["labels"]) not in endpoints:
+                results.append(CheckResult(
+                    f"optional_{name}_{dep}", False,
+                    f"{name} экспортирована для endpoint без {HEALTH_METRIC}",
+                ))
+        if name == CONNECT_LATENCY_METRIC:
+            results.extend(check_histogram_buckets(metrics, name))
+
+    if not results:
+        results.append(CheckResult("optional_metrics", True,
+                                   "опциональные метрики не экспортируются"))
+    return results
+
+
 def check_expected_dependencies(
     metrics: dict, expected: list[dict]
 ) -> list[CheckResult]:
@@ -425,7 +527,7 @@ def _endpoint_key(labels: dict) -> tuple:
 
 
 def check_status_enum_completeness(metrics: dict) -> list[CheckResult]:
-    """Check that each endpoint has exactly 8 status series, with exactly one = 1."""
+    """Check that each endpoint has one series per status value, with exactly one = 1."""
     results = []
     if STATUS_METRIC not in metrics:
         return [CheckResult("status_enum_completeness", False,
@@ -459,17 +561,17 @@ def check_status_enum_completeness(metrics: dict) -> list[CheckResult]:
         else:
             results.append(CheckResult(
                 f"status_enum_{dep}", True,
-                f"8 серий, активный: {active[0]}",
+                f"{len(VALID_STATUSES)} серий, активный: {active[0]}",
             ))
 
     if not results:
         results.append(CheckResult("status_enum_completeness", True,
-                                   "все endpoint-ы имеют 8 серий status"))
+                                   f"все endpoint-ы имеют {len(VALID_STATUSES)} серий status"))
     return results
 
 
 def check_status_health_consistency(metrics: dict) -> list[CheckResult]:
-    """Check that health=1 ↔ status{ok}=1 and health=0 ↔ status{ok}=0."""
+    """Check that health=1 ↔ status{ok|degraded}=1 and health=0 ↔ status{ok|degraded}=0."""
     results = []
     if HEALTH_METRIC not in metrics or STATUS_METRIC not in metrics:
         return [CheckResult("status_health_consistency", True,
@@ -483,12 +585,12 @@ def check_status_health_consistency(metrics: dict) -> list[CheckResult]:
         key = _endpoint_key(sample["labels"])
         health_map[key] = sample["value"]
 
-    # Build status{ok} lookup
+    # Build status{ok|degraded} lookup: a degraded dependency is healthy
     status_ok_map: dict[tuple, float] = {}
     for sample in metrics[STATUS_METRIC]["samples"]:
-        if sample["labels"].get("status") == "ok":
+        if sample["labels"].get("status") in HEALTHY_STATUSES:
             key = _endpoint_key(sample["labels"])
-            status_ok_map[key] = sample["value"]
+            status_ok_map[key] = status_ok_map.get(key, 0.0) + sample["value"]
 
     for key in health_map:
         dep = key[0]
@@ -498,24 +600,24 @@ def check_status_health_consistency(metrics: dict) -> list[CheckResult]:
         if status_ok_val is None:
             results.append(CheckResult(
                 f"consistency_{dep}", False,
-                f"status{{ok}} не найден для {dep}",
+                f"status{{ok|degraded}} не найден для {dep}",
             ))
             continue
 
         if health_val == 1.0 and status_ok_val != 1.0:
             results.append(CheckResult(
                 f"consistency_{dep}", False,
-                f"health=1 но status{{ok}}={status_ok_val}",
+                f"health=1 но status{{ok|degraded}}={status_ok_val}",
             ))
         elif health_val == 0.0 and status_ok_val != 0.0:
             results.append(CheckResult(
                 f"consistency_{dep}", False,
-                f"health=0 но status{{ok}}={status_ok_val}",
+                f"health=0 но status{{ok|degraded}}={status_ok_val}",
             ))
         else:
             results.append(CheckResult(
                 f"consistency_{dep}", True,
-                f"health={health_val} ↔ status{{ok}}={status_ok_val}",
+                f"health={health_val} ↔ status{{ok|degraded}}={status_ok_val}",
             ))
 
     if not results:
@@ -569,7 +671,7 @@ def check_detail_valid_values(metrics: dict) -> list[CheckResult]:
             ))
             continue
 
-        if detail not in valid_set:
+        if detail not in valid_set and detail not in CORE_DETAILS:
             results.append(CheckResult(
                 f"detail_valid_{dep}", False,
                 f"detail='{detail}' невалидно для типа '{dep_type}'",
@@ -613,9 +715,9 @@ def check_detail_status_mapping(metrics: dict) -> list[CheckResult]:
             ))
             continue
 
-        # http_NNN maps to "error"
+        # http_NNN maps to "unhealthy"
         if re.fullmatch(r"http_\d{3}", detail):
-            expected_status = "error"
+            expected_status = "unhealthy"
         else:
             expected_status = DETAIL_TO_STATUS.get(detail)
 
@@ -731,7 +833,7 @@ HEALTH_DETAILS_REQUIRED_FIELDS = {
     "healthy", "status", "detail", "latency_ms", "type",
     "name", "host", "port", "critical", "last_checked_at", "labels",
 }
-VALID_STATUS_CATEGORIES = {"ok", "timeout", "connection_error", "dns_error",
+VALID_STATUS_CATEGORIES = {"ok", "degraded", "timeout", "connection_error", "dns_error",
                            "auth_error", "tls_error", "unhealthy", "error", "unknown"}
 
 
@@ -1165,7 +1267,10 @@ def run_scenario(
             results.extend(check_health_values(metrics))
 
         elif check_type == "histogram_buckets":
-            results.extend(check_histogram_buckets(metrics))
+            results.extend(check_histogram_buckets(metrics, check.get("metric", LATENCY_METRIC)))
+
+        elif check_type == "optional_metrics":
+            results.extend(check_optional_metrics(metrics))
 
         elif check_type == "expected_dependencies":
             results.extend(check_expected_dependencies(metrics, check["dependencies"]))
@@ -8,6 +8,9 @@ type StatusCategory string
 const (
 	// StatusOK means the health check succeeded.
 	StatusOK StatusCategory = "ok"
+	// StatusDegraded means the health check succeeded but the dependency is
+	// slower than its latency SLO; it still counts as healthy.
+	StatusDegraded StatusCategory = "degraded"
 	// StatusTimeout means the health check exceeded its deadline.
 	StatusTimeout StatusCategory = "timeout"
 	// StatusConnectionError means the connection to the dependency failed.
@@ -26,11 +29,12 @@ const (
 	StatusUnknown StatusCategory = "unknown"
 )
 
-// AllStatusCategories contains the 8 status categories used for the
+// AllStatusCategories contains the 9 status categories used for the
 // app_dependency_status enum-pattern gauge. StatusUnknown is excluded
 // as it is only used for the HealthDetails API.
 var AllStatusCategories = []StatusCategory{
 	StatusOK,
+	StatusDegraded,
 	StatusTimeout,
 	StatusConnectionError,
 	StatusDNSError,
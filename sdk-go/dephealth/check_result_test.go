@@ -6,14 +6,14 @@ import (
 )
 
 func TestAllStatusCategories_Count(t *testing.T) {
-	if len(AllStatusCategories) != 8 {
-		t.Errorf("expected 8 status categories, got %d", len(AllStatusCategories))
+	if len(AllStatusCategories) != 9 {
+		t.Errorf("expected 9 status categories, got %d", len(AllStatusCategories))
 	}
 }
 
 func TestAllStatusCategories_Values(t *testing.T) {
 	expected := map[StatusCategory]bool{
-		StatusOK: true, StatusTimeout: true, StatusConnectionError: true,
+		StatusOK: true, StatusDegraded: true, StatusTimeout: true, StatusConnectionError: true,
 		StatusDNSError: true, StatusAuthError: true, StatusTLSError: true,
 		StatusUnhealthy: true, StatusError: true,
 	}
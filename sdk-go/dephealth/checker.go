@@ -3,6 +3,8 @@ package dephealth
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 )
 
 var (
@@ -12,8 +14,23 @@ var (
 	ErrConnectionRefused = errors.New("connection refused")
 	// ErrUnhealthy indicates that the dependency reported an unhealthy status.
 	ErrUnhealthy = errors.New("dependency unhealthy")
+	// ErrConnectionClosed indicates that the dependency closed the connection
+	// during the dial or handshake, before answering.
+	ErrConnectionClosed = errors.New("connection closed")
 )
 
+// WrapHandshakeEOF marks an EOF read during the dial or handshake with
+// ErrConnectionClosed, so it is classified as connection_closed even when
+// the driver wrapped it. Other errors, including nil, are returned
+// unchanged. EOFs from decoding a response are not connection errors and
+// must not be passed here.
+func WrapHandshakeEOF(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %w", ErrConnectionClosed, err)
+	}
+	return err
+}
+
 // HealthChecker is the interface for dependency health checks.
 // Each dependency type (HTTP, gRPC, TCP, Postgres, etc.) implements this interface.
 type HealthChecker interface {
@@ -25,3 +42,25 @@ type HealthChecker interface {
 	// Type returns the dependency type this checker handles (e.g. "http", "postgres").
 	Type() string
 }
+
+// EndpointCloser is an optional interface for checkers that keep resources
+// per endpoint, such as persistent connections. The scheduler calls
+// CloseEndpoint after the last check of an endpoint: when it is removed or
+// replaced and when the scheduler stops. The call is skipped while another
+// endpoint checks the same address with the same checker instance.
+type EndpointCloser interface {
+	CloseEndpoint(endpoint Endpoint)
+}
+
+// Initializer is an optional interface for checkers that need setup before
+// their first check, such as opening a pool or starting a background
+// goroutine. The scheduler calls Init once per checker instance, before the
+// first check of any of its endpoints, with the check timeout applied. A
+// failed Init counts as a failed check and is retried on the next one.
+//
+// Checkers that also implement io.Closer are closed by the scheduler once
+// the last endpoint using the instance has been removed or replaced, or when
+// the scheduler stops. Close is called at most once per instance.
+type Initializer interface {
+	Init(ctx context.Context) error
+}
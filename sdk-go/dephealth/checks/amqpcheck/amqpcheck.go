@@ -7,20 +7,28 @@ package amqpcheck
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 
 	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
 )
 
 // defaultDialTimeout is used for AMQP connections to ensure the dial goroutine
 // finishes within a bounded time when the remote server is unreachable.
 const defaultDialTimeout = 5 * time.Second
 
+var tlsSkipVerifyWarnOnce sync.Once
+
 var _ dephealth.HealthChecker = (*Checker)(nil)
 
 func init() {
@@ -33,8 +41,25 @@ type Option func(*Checker)
 // Checker performs health checks by establishing an AMQP connection.
 // Only standalone mode is supported: creates a new connection per check.
 // The check succeeds if the AMQP connection can be established.
+//
+// Without WithURL, the URL is built per endpoint from its host/port and the
+// configured credentials, virtual host and TLS settings. With WithChannel or
+// WithQueues, the check also opens a channel and passively declares each
+// queue (details queue_missing, queue_depth, queue_consumers).
 type Checker struct {
-	url string // full AMQP URL (overrides endpoint-based URL)
+	url        string // full AMQP URL (overrides endpoint-based URL)
+	username   string
+	password   string
+	vhost      string
+	tlsEnabled bool
+	tls        tlsutil.Options
+	tlsConfig  *tls.Config // base config from WithTLSConfig
+	tlsSource  *tlsutil.Source
+
+	channel      bool
+	queues       []string
+	maxDepth     int // 0 = no limit
+	minConsumers int // 0 = no requirement
 }
 
 // WithURL sets a custom AMQP URL for connections.
@@ -45,34 +70,254 @@ func WithURL(url string) Option {
 	}
 }
 
+// WithCredentials sets the username and password (default guest/guest).
+func WithCredentials(username, password string) Option {
+	return func(c *Checker) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithVHost sets the virtual host (default "/").
+func WithVHost(vhost string) Option {
+	return func(c *Checker) {
+		c.vhost = vhost
+	}
+}
+
+// WithTLS enables TLS (amqps). Enabled automatically for amqps:// URLs.
+func WithTLS(enabled bool) Option {
+	return func(c *Checker) {
+		c.tlsEnabled = enabled
+	}
+}
+
+// WithTLSCA sets the PEM CA bundle used to verify the server certificate.
+// Implies TLS.
+func WithTLSCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tls.CAFile = caFile
+	}
+}
+
+// WithTLSClientCert sets the client certificate and key for mTLS.
+// Implies TLS.
+func WithTLSClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tls.CertFile = certFile
+		c.tls.KeyFile = keyFile
+	}
+}
+
+// WithTLSConfig sets the base TLS configuration and enables TLS. The files
+// of WithTLSCA and WithTLSClientCert are loaded on top of a copy and read
+// again when they change.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithTLSSkipVerify skips TLS certificate verification.
+func WithTLSSkipVerify(skip bool) Option {
+	return func(c *Checker) {
+		c.tls.SkipVerify = skip
+	}
+}
+
+// WithChannel opens and closes a channel after connecting, which catches
+// brokers that accept connections but refuse channels (e.g. channel_max
+// reached or a blocked node).
+func WithChannel() Option {
+	return func(c *Checker) {
+		c.channel = true
+	}
+}
+
+// WithQueues requires the queues to exist in the virtual host. Each queue is
+// declared passively; a missing queue fails the check with detail
+// queue_missing. Implies WithChannel.
+func WithQueues(queues ...string) Option {
+	return func(c *Checker) {
+		c.queues = append(c.queues, queues...)
+	}
+}
+
+// WithMaxQueueDepth fails the check with detail queue_depth when a queue set
+// by WithQueues holds more than n ready messages.
+func WithMaxQueueDepth(n int) Option {
+	return func(c *Checker) {
+		c.maxDepth = n
+	}
+}
+
+// WithMinConsumers fails the check with detail queue_consumers when a queue
+// set by WithQueues has fewer than n consumers.
+func WithMinConsumers(n int) Option {
+	return func(c *Checker) {
+		c.minConsumers = n
+	}
+}
+
 // New creates a new AMQP health checker with the given options.
 func New(opts ...Option) *Checker {
 	c := &Checker{}
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.tlsSource = tlsutil.NewSource(c.tlsConfig, c.tls)
 	return c
 }
 
 // NewFromConfig creates an AMQP checker from DependencyConfig.
+// An explicit AMQPURL is used verbatim; otherwise the credentials, virtual
+// host and scheme of the dependency URL are applied to every endpoint.
 func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
 	var opts []Option
 	if dc.AMQPURL != "" {
 		opts = append(opts, WithURL(dc.AMQPURL))
 	} else if dc.URL != "" {
-		opts = append(opts, WithURL(dc.URL))
+		opts = append(opts, withBaseURL(dc.URL))
+	}
+	if dc.AMQPUsername != "" || dc.AMQPPassword != "" {
+		opts = append(opts, withCredentialOverrides(dc.AMQPUsername, dc.AMQPPassword))
+	}
+	if dc.AMQPVHost != "" {
+		opts = append(opts, WithVHost(dc.AMQPVHost))
+	}
+	if dc.AMQPTLS != nil {
+		opts = append(opts, WithTLS(*dc.AMQPTLS))
+	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
+	if dc.AMQPTLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.AMQPTLSCAFile))
+	}
+	if dc.AMQPTLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.AMQPTLSCertFile, dc.AMQPTLSKeyFile))
+	}
+	if dc.TLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.TLSSkipVerify))
+	}
+	if dc.AMQPTLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.AMQPTLSSkipVerify))
+	}
+	if dc.AMQPChannel {
+		opts = append(opts, WithChannel())
+	}
+	if len(dc.AMQPQueues) > 0 {
+		opts = append(opts, WithQueues(dc.AMQPQueues...))
+	}
+	if dc.AMQPMaxQueueDepth > 0 {
+		opts = append(opts, WithMaxQueueDepth(dc.AMQPMaxQueueDepth))
+	}
+	if dc.AMQPMinConsumers > 0 {
+		opts = append(opts, WithMinConsumers(dc.AMQPMinConsumers))
 	}
 	return New(opts...)
 }
 
-// Check establishes an AMQP connection and immediately closes it.
+// withBaseURL takes the credentials, virtual host and scheme of an amqp://
+// or amqps:// URL as defaults for the per-endpoint URL; its host is ignored.
+// A URL that cannot be parsed is used verbatim.
+func withBaseURL(rawURL string) Option {
+	return func(c *Checker) {
+		uri, err := amqp.ParseURI(rawURL)
+		if err != nil {
+			c.url = rawURL
+			return
+		}
+		c.username = uri.Username
+		c.password = uri.Password
+		c.vhost = uri.Vhost
+		if uri.Scheme == "amqps" {
+			c.tlsEnabled = true
+		}
+	}
+}
+
+// withCredentialOverrides replaces the username and keeps the URL password
+// unless a password is given.
+func withCredentialOverrides(username, password string) Option {
+	return func(c *Checker) {
+		if username != "" {
+			c.username = username
+		}
+		if password != "" {
+			c.password = password
+		}
+	}
+}
+
+// urlFor returns the AMQP URL used to check the endpoint.
+func (c *Checker) urlFor(endpoint dephealth.Endpoint) (string, error) {
+	if c.url != "" {
+		return c.url, nil
+	}
+	port, err := strconv.Atoi(endpoint.Port)
+	if err != nil {
+		return "", fmt.Errorf("amqp %s: invalid port %q", endpoint.Host, endpoint.Port)
+	}
+
+	uri := amqp.URI{
+		Scheme:   "amqp",
+		Host:     endpoint.Host,
+		Port:     port,
+		Username: "guest",
+		Password: "guest",
+		Vhost:    "/",
+	}
+	if c.useTLS() {
+		uri.Scheme = "amqps"
+	}
+	if c.username != "" {
+		uri.Username = c.username
+		uri.Password = c.password
+	}
+	if c.vhost != "" {
+		uri.Vhost = c.vhost
+	}
+	return uri.String(), nil
+}
+
+func (c *Checker) useTLS() bool {
+	return c.tlsEnabled || c.tlsConfig != nil || c.tls.CAFile != "" || c.tls.CertFile != ""
+}
+
+// Check establishes an AMQP connection, runs the configured channel and
+// queue checks and closes it.
 // Uses amqp.DialConfig with a bounded dial timeout to prevent goroutine leaks
 // when the remote server is unreachable.
-// A URL must be provided via WithURL option or DependencyConfig; otherwise an error is returned.
 func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
-	url := c.url
-	if url == "" {
-		return fmt.Errorf("amqp %s: URL is required (use WithURL option or set URL/AMQPURL in config)", endpoint.Host)
+	return c.check(ctx, endpoint)
+}
+
+// check performs the AMQP check.
+func (c *Checker) check(ctx context.Context, endpoint dephealth.Endpoint) error {
+	url, err := c.urlFor(endpoint)
+	if err != nil {
+		return err
+	}
+
+	cfg := amqp.Config{}
+	if c.useTLS() {
+		if c.tls.SkipVerify {
+			tlsSkipVerifyWarnOnce.Do(func() {
+				slog.Warn("dephealth: AMQP checker has TLS certificate verification disabled (InsecureSkipVerify=true)")
+			})
+		}
+		var tlsCfg *tls.Config
+		if tlsCfg, err = c.tlsSource.Config(); err != nil {
+			return fmt.Errorf("amqp %s: %w", endpoint.Host, err)
+		}
+		cfg.TLSClientConfig = tlsCfg
 	}
 
 	// Derive dial timeout from context deadline to prevent goroutine leaks.
@@ -82,6 +327,9 @@ func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error
 			dialTimeout = remaining
 		}
 	}
+	cfg.Dial = func(network, addr string) (net.Conn, error) {
+		return net.DialTimeout(network, addr, dialTimeout)
+	}
 
 	type dialResult struct {
 		conn *amqp.Connection
@@ -90,11 +338,7 @@ func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error
 
 	ch := make(chan dialResult, 1)
 	go func() {
-		conn, err := amqp.DialConfig(url, amqp.Config{
-			Dial: func(network, addr string) (net.Conn, error) {
-				return net.DialTimeout(network, addr, dialTimeout)
-			},
-		})
+		conn, err := amqp.DialConfig(url, cfg)
 		ch <- dialResult{conn: conn, err: err}
 	}()
 
@@ -106,16 +350,76 @@ func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error
 		if res.err != nil {
 			return classifyError(res.err, endpoint.Host)
 		}
-		_ = res.conn.Close()
+		defer func() { _ = res.conn.Close() }()
+		if state := res.conn.ConnectionState(); state.HandshakeComplete {
+			dephealth.ReportTLSConnectionState(ctx, &state)
+		}
+		if c.channel || len(c.queues) > 0 {
+			return c.checkQueues(res.conn, endpoint.Host)
+		}
 		return nil
 	}
 }
 
+// checkQueues opens a channel and passively declares every configured queue.
+// A failed passive declare closes the channel, so the first failure ends the check.
+func (c *Checker) checkQueues(conn *amqp.Connection, host string) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("amqp channel %s: %w", host, err)
+	}
+	defer func() { _ = ch.Close() }()
+
+	for _, name := range c.queues {
+		q, err := ch.QueueDeclarePassive(name, false, false, false, false, nil)
+		if err != nil {
+			return classifyQueueError(err, name, host)
+		}
+		if err := c.evaluateQueue(q, host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluateQueue applies the depth and consumer thresholds to a declared queue.
+func (c *Checker) evaluateQueue(q amqp.Queue, host string) error {
+	if c.maxDepth > 0 && q.Messages > c.maxDepth {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "queue_depth",
+			Cause:    fmt.Errorf("amqp %s: queue %q has %d messages, max %d", host, q.Name, q.Messages, c.maxDepth),
+		}
+	}
+	if c.minConsumers > 0 && q.Consumers < c.minConsumers {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "queue_consumers",
+			Cause:    fmt.Errorf("amqp %s: queue %q has %d consumers, min %d", host, q.Name, q.Consumers, c.minConsumers),
+		}
+	}
+	return nil
+}
+
 // Type returns the dependency type for this checker.
 func (c *Checker) Type() string {
 	return string(dephealth.TypeAMQP)
 }
 
+// classifyQueueError maps a failed passive declare: 404 NOT_FOUND is
+// queue_missing, 403 ACCESS_REFUSED is an auth error.
+func classifyQueueError(err error, queue, host string) error {
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "queue_missing",
+			Cause:    fmt.Errorf("amqp %s: queue %q: %w", host, queue, err),
+		}
+	}
+	return classifyError(err, host)
+}
+
 // classifyError wraps AMQP errors with appropriate classification.
 func classifyError(err error, host string) error {
 	msg := err.Error()
@@ -127,5 +431,5 @@ func classifyError(err error, host string) error {
 			Cause:    fmt.Errorf("amqp dial %s: %w", host, err),
 		}
 	}
-	return fmt.Errorf("amqp dial %s: %w", host, err)
+	return fmt.Errorf("amqp dial %s: %w", host, dephealth.WrapHandshakeEOF(err))
 }
@@ -0,0 +1,296 @@
+// Package cassandracheck provides a Cassandra (CQL) health checker for dephealth.
+//
+// Import this package to register the Cassandra checker factory:
+//
+//	import _ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/cassandracheck"
+package cassandracheck
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
+)
+
+// defaultDialTimeout is used for standalone Cassandra sessions to ensure
+// errors are classifiable before the check scheduler's context timeout fires.
+const defaultDialTimeout = 3 * time.Second
+
+// healthQuery reads the node version from the local system table.
+const healthQuery = "SELECT release_version FROM system.local"
+
+var tlsSkipVerifyWarnOnce sync.Once
+
+var _ dephealth.HealthChecker = (*Checker)(nil)
+
+func init() {
+	dephealth.RegisterCheckerFactory(dephealth.TypeCassandra, NewFromConfig)
+}
+
+// Option configures the Checker.
+type Option func(*Checker)
+
+// Checker performs health checks against a Cassandra node by reading
+// release_version from system.local.
+// Supports two modes:
+//   - Standalone: creates a new session per check, connected only to the endpoint
+//   - Pool: uses an existing *gocql.Session
+type Checker struct {
+	session       *gocql.Session // nil = standalone, non-nil = pool mode
+	username      string
+	password      string
+	consistency   gocql.Consistency
+	tlsEnabled    bool
+	tlsSkipVerify bool
+	tlsConfig     *tls.Config     // base config from WithTLSConfig
+	tls           tlsutil.Options // CA and client certificate files
+	tlsSource     *tlsutil.Source
+}
+
+// WithSession sets an existing session for pool mode.
+func WithSession(session *gocql.Session) Option {
+	return func(c *Checker) {
+		c.session = session
+	}
+}
+
+// WithAuth sets the username and password for standalone mode
+// (PasswordAuthenticator).
+func WithAuth(username, password string) Option {
+	return func(c *Checker) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithConsistency sets the consistency level of the health check query
+// (default gocql.One).
+func WithConsistency(consistency gocql.Consistency) Option {
+	return func(c *Checker) {
+		c.consistency = consistency
+	}
+}
+
+// WithTLS enables TLS for standalone mode connections.
+func WithTLS(enabled bool) Option {
+	return func(c *Checker) {
+		c.tlsEnabled = enabled
+	}
+}
+
+// WithTLSConfig sets the base TLS configuration for standalone mode and
+// enables TLS. The files of WithTLSCA and WithTLSClientCert are loaded on
+// top of a copy.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithTLSCA sets the PEM CA bundle used to verify the server certificate
+// and enables TLS. The file is read again when it changes.
+func WithTLSCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tls.CAFile = caFile
+	}
+}
+
+// WithTLSClientCert sets the client certificate and key for mTLS and
+// enables TLS. The files are read again when they change.
+func WithTLSClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tls.CertFile = certFile
+		c.tls.KeyFile = keyFile
+	}
+}
+
+// WithTLSSkipVerify skips TLS certificate verification.
+func WithTLSSkipVerify(skip bool) Option {
+	return func(c *Checker) {
+		c.tlsSkipVerify = skip
+	}
+}
+
+// New creates a new Cassandra health checker with the given options.
+func New(opts ...Option) *Checker {
+	c := &Checker{
+		consistency: gocql.One,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.tlsSource = tlsutil.NewSource(c.tlsConfig, c.tls)
+	if !c.tlsSource.IsZero() {
+		c.tlsEnabled = true
+	}
+	return c
+}
+
+// NewFromConfig creates a Cassandra checker from DependencyConfig.
+func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
+	var opts []Option
+	if dc.CassandraUsername != "" {
+		opts = append(opts, WithAuth(dc.CassandraUsername, dc.CassandraPassword))
+	}
+	if dc.CassandraConsistency != "" {
+		// Validated by dephealth; fall back to the default on unexpected input.
+		if consistency, err := gocql.ParseConsistencyWrapper(strings.ToUpper(dc.CassandraConsistency)); err == nil {
+			opts = append(opts, WithConsistency(consistency))
+		}
+	}
+	if dc.CassandraTLS != nil {
+		opts = append(opts, WithTLS(*dc.CassandraTLS))
+	}
+	if dc.TLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.TLSSkipVerify))
+	}
+	if dc.CassandraTLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.CassandraTLSSkipVerify))
+	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
+	return New(opts...)
+}
+
+// Check performs a health check against the Cassandra endpoint.
+// In pool mode, uses the existing session. In standalone mode, creates a new one.
+func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
+	if c.session != nil {
+		return c.query(ctx, c.session, "pool")
+	}
+	return c.checkStandalone(ctx, endpoint)
+}
+
+func (c *Checker) checkStandalone(ctx context.Context, endpoint dephealth.Endpoint) error {
+	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
+
+	port, err := strconv.Atoi(endpoint.Port)
+	if err != nil {
+		return fmt.Errorf("cassandra %s: invalid port: %w", addr, err)
+	}
+
+	timeout := defaultDialTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	cluster := gocql.NewCluster(endpoint.Host)
+	cluster.Port = port
+	cluster.Consistency = c.consistency
+	cluster.Timeout = timeout
+	cluster.ConnectTimeout = timeout
+	cluster.NumConns = 1
+	// Talk to this node only: no peer discovery and no ring events.
+	cluster.DisableInitialHostLookup = true
+	cluster.Events.DisableNodeStatusEvents = true
+	cluster.Events.DisableTopologyEvents = true
+	cluster.Events.DisableSchemaEvents = true
+
+	if c.username != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: c.username,
+			Password: c.password,
+		}
+	}
+
+	if c.tlsEnabled {
+		if c.tlsSkipVerify {
+			tlsSkipVerifyWarnOnce.Do(func() {
+				slog.Warn("dephealth: Cassandra checker has TLS certificate verification disabled (InsecureSkipVerify=true)")
+			})
+		}
+		tlsCfg, err := c.tlsSource.Config()
+		if err != nil {
+			return fmt.Errorf("cassandra %s: %w", addr, err)
+		}
+		if c.tlsSkipVerify {
+			tlsCfg.InsecureSkipVerify = true //nolint:gosec // user-configurable
+		}
+		cluster.SslOpts = &gocql.SslOptions{
+			Config:                 tlsCfg,
+			EnableHostVerification: !tlsCfg.InsecureSkipVerify,
+		}
+	}
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return classifyError(err, addr)
+	}
+	defer session.Close()
+
+	return c.query(ctx, session, addr)
+}
+
+func (c *Checker) query(ctx context.Context, session *gocql.Session, target string) error {
+	var version string
+	err := session.Query(healthQuery).
+		WithContext(ctx).
+		Consistency(c.consistency).
+		Scan(&version)
+	if err != nil {
+		return classifyError(err, target)
+	}
+	return nil
+}
+
+// classifyError wraps Cassandra errors with appropriate classification.
+// Detects auth errors via CQL error codes and the authenticator messages
+// reported while the session is being created.
+func classifyError(err error, target string) error {
+	var reqErr gocql.RequestError
+	if errors.As(err, &reqErr) {
+		switch reqErr.Code() {
+		case gocql.ErrCodeCredentials, gocql.ErrCodeUnauthorized:
+			return &dephealth.ClassifiedCheckError{
+				Category: dephealth.StatusAuthError,
+				Detail:   "auth_error",
+				Cause:    fmt.Errorf("cassandra %s: %w", target, err),
+			}
+		case gocql.ErrCodeUnavailable, gocql.ErrCodeOverloaded, gocql.ErrCodeBootstrapping:
+			return &dephealth.ClassifiedCheckError{
+				Category: dephealth.StatusUnhealthy,
+				Detail:   "unhealthy",
+				Cause:    fmt.Errorf("cassandra %s: %w", target, err),
+			}
+		}
+	}
+
+	// Session creation flattens the underlying error into text.
+	msg := err.Error()
+	if strings.Contains(msg, "authentication required") ||
+		strings.Contains(msg, "Provided username and/or password are incorrect") ||
+		strings.Contains(msg, "Bad credentials") {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusAuthError,
+			Detail:   "auth_error",
+			Cause:    fmt.Errorf("cassandra %s: %w", target, err),
+		}
+	}
+
+	return fmt.Errorf("cassandra query %s: %w", target, err)
+}
+
+// Type returns the dependency type for this checker.
+func (c *Checker) Type() string {
+	return string(dephealth.TypeCassandra)
+}
@@ -0,0 +1,106 @@
+package cassandracheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+)
+
+// fakeRequestError implements gocql.RequestError.
+type fakeRequestError struct {
+	code int
+	msg  string
+}
+
+func (e *fakeRequestError) Code() int       { return e.code }
+func (e *fakeRequestError) Message() string { return e.msg }
+func (e *fakeRequestError) Error() string   { return e.msg }
+
+func TestChecker_Check_Standalone_ConnectionRefused(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	checker := New()
+	ep := dephealth.Endpoint{Host: "127.0.0.1", Port: "1"}
+
+	if err := checker.Check(ctx, ep); err == nil {
+		t.Error("expected error for closed port, got nil")
+	}
+}
+
+func TestChecker_Check_Standalone_InvalidPort(t *testing.T) {
+	checker := New()
+	ep := dephealth.Endpoint{Host: "127.0.0.1", Port: "cql"}
+
+	if err := checker.Check(context.Background(), ep); err == nil {
+		t.Error("expected error for invalid port, got nil")
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		category dephealth.StatusCategory
+	}{
+		{"bad credentials", &fakeRequestError{code: gocql.ErrCodeCredentials, msg: "Provided username and/or password are incorrect"}, dephealth.StatusAuthError},
+		{"unauthorized", &fakeRequestError{code: gocql.ErrCodeUnauthorized, msg: "User has no SELECT permission"}, dephealth.StatusAuthError},
+		{"unavailable", &fakeRequestError{code: gocql.ErrCodeUnavailable, msg: "Cannot achieve consistency level"}, dephealth.StatusUnhealthy},
+		{"session auth required", errors.New(`gocql: unable to create session: authentication required (using "org.apache.cassandra.auth.PasswordAuthenticator")`), dephealth.StatusAuthError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ce *dephealth.ClassifiedCheckError
+			if !errors.As(classifyError(tt.err, "cassandra:9042"), &ce) {
+				t.Fatal("expected ClassifiedCheckError")
+			}
+			if ce.Category != tt.category {
+				t.Errorf("category = %s, expected %s", ce.Category, tt.category)
+			}
+		})
+	}
+}
+
+func TestClassifyError_Unclassified(t *testing.T) {
+	err := classifyError(errors.New("gocql: no hosts available in the pool"), "cassandra:9042")
+	var ce *dephealth.ClassifiedCheckError
+	if errors.As(err, &ce) {
+		t.Errorf("expected plain error, got classified %s", ce.Category)
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	dc := &dephealth.DependencyConfig{
+		CassandraUsername:    "monitor",
+		CassandraPassword:    "secret",
+		CassandraConsistency: "local_quorum",
+	}
+	checker, ok := NewFromConfig(dc).(*Checker)
+	if !ok {
+		t.Fatal("expected *Checker")
+	}
+	if checker.username != "monitor" || checker.password != "secret" {
+		t.Errorf("auth = %q/%q, expected monitor/secret", checker.username, checker.password)
+	}
+	if checker.consistency != gocql.LocalQuorum {
+		t.Errorf("consistency = %v, expected LOCAL_QUORUM", checker.consistency)
+	}
+}
+
+func TestNew_DefaultConsistency(t *testing.T) {
+	if got := New().consistency; got != gocql.One {
+		t.Errorf("consistency = %v, expected ONE", got)
+	}
+}
+
+func TestChecker_Type(t *testing.T) {
+	checker := New()
+	if got := checker.Type(); got != "cassandra" {
+		t.Errorf("Type() = %q, expected %q", got, "cassandra")
+	}
+}
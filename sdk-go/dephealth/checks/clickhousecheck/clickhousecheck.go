@@ -0,0 +1,331 @@
+// Package clickhousecheck provides a ClickHouse health checker for dephealth.
+//
+// Import this package to register the ClickHouse checker factory:
+//
+//	import _ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/clickhousecheck"
+package clickhousecheck
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
+)
+
+// defaultDialTimeout is used for standalone ClickHouse connections to ensure
+// errors are classifiable before the check scheduler's context timeout fires.
+const defaultDialTimeout = 3 * time.Second
+
+// replicaDelayQuery returns the largest replication delay (in seconds)
+// across all replicated tables on the server.
+const replicaDelayQuery = "SELECT toUInt64(ifNull(max(absolute_delay), 0)) FROM system.replicas"
+
+// ClickHouse server error codes treated as authentication failures.
+const (
+	codeUnknownUser          = 192
+	codeWrongPassword        = 193
+	codeRequiredPassword     = 194
+	codeAuthenticationFailed = 516
+)
+
+// Protocol selects the ClickHouse interface used in standalone mode.
+type Protocol string
+
+// Supported protocols.
+const (
+	ProtocolNative Protocol = "native"
+	ProtocolHTTP   Protocol = "http"
+)
+
+var tlsSkipVerifyWarnOnce sync.Once
+
+var _ dephealth.HealthChecker = (*Checker)(nil)
+
+func init() {
+	dephealth.RegisterCheckerFactory(dephealth.TypeClickHouse, NewFromConfig)
+}
+
+// queryRowFunc runs a single-row query and scans the result into dest.
+type queryRowFunc func(ctx context.Context, query string, dest ...any) error
+
+// Option configures the Checker.
+type Option func(*Checker)
+
+// Checker performs health checks against a ClickHouse server.
+// It runs "SELECT 1" and, if a replica delay threshold is set, verifies that
+// no replicated table lags behind by more than the threshold.
+// Supports two modes:
+//   - Standalone: opens a new connection per check using the endpoint
+//   - Pool: uses an existing *sql.DB or driver.Conn
+type Checker struct {
+	queryRow        queryRowFunc // nil = standalone, non-nil = pool mode
+	protocol        Protocol
+	username        string
+	password        string
+	database        string
+	maxReplicaDelay time.Duration
+	tlsEnabled      bool
+	tlsSkipVerify   bool
+	tlsConfig       *tls.Config     // base config from WithTLSConfig
+	tls             tlsutil.Options // CA and client certificate files
+	tlsSource       *tlsutil.Source
+}
+
+// WithDB sets an existing database/sql connection pool (clickhouse.OpenDB) for pool mode.
+func WithDB(db *sql.DB) Option {
+	return func(c *Checker) {
+		c.queryRow = func(ctx context.Context, query string, dest ...any) error {
+			return db.QueryRowContext(ctx, query).Scan(dest...)
+		}
+	}
+}
+
+// WithConn sets an existing native connection (clickhouse.Open) for pool mode.
+func WithConn(conn driver.Conn) Option {
+	return func(c *Checker) {
+		c.queryRow = func(ctx context.Context, query string, dest ...any) error {
+			return conn.QueryRow(ctx, query).Scan(dest...)
+		}
+	}
+}
+
+// WithProtocol sets the interface used in standalone mode (default ProtocolNative).
+func WithProtocol(protocol Protocol) Option {
+	return func(c *Checker) {
+		c.protocol = protocol
+	}
+}
+
+// WithAuth sets the username and password for standalone mode.
+func WithAuth(username, password string) Option {
+	return func(c *Checker) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithDatabase sets the database for standalone mode.
+func WithDatabase(database string) Option {
+	return func(c *Checker) {
+		c.database = database
+	}
+}
+
+// WithMaxReplicaDelay sets the maximum allowed replication delay.
+// Zero (default) disables the system.replicas check.
+func WithMaxReplicaDelay(d time.Duration) Option {
+	return func(c *Checker) {
+		c.maxReplicaDelay = d
+	}
+}
+
+// WithTLS enables TLS for standalone mode connections.
+func WithTLS(enabled bool) Option {
+	return func(c *Checker) {
+		c.tlsEnabled = enabled
+	}
+}
+
+// WithTLSConfig sets the base TLS configuration for standalone mode and
+// enables TLS. The files of WithTLSCA and WithTLSClientCert are loaded on
+// top of a copy.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithTLSCA sets the PEM CA bundle used to verify the server certificate
+// and enables TLS. The file is read again when it changes.
+func WithTLSCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tls.CAFile = caFile
+	}
+}
+
+// WithTLSClientCert sets the client certificate and key for mTLS and
+// enables TLS. The files are read again when they change.
+func WithTLSClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tls.CertFile = certFile
+		c.tls.KeyFile = keyFile
+	}
+}
+
+// WithTLSSkipVerify skips TLS certificate verification.
+func WithTLSSkipVerify(skip bool) Option {
+	return func(c *Checker) {
+		c.tlsSkipVerify = skip
+	}
+}
+
+// New creates a new ClickHouse health checker with the given options.
+func New(opts ...Option) *Checker {
+	c := &Checker{
+		protocol: ProtocolNative,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.tlsSource = tlsutil.NewSource(c.tlsConfig, c.tls)
+	if !c.tlsSource.IsZero() {
+		c.tlsEnabled = true
+	}
+	return c
+}
+
+// NewFromConfig creates a ClickHouse checker from DependencyConfig.
+func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
+	var opts []Option
+	if dc.ClickHouseProtocol != "" {
+		opts = append(opts, WithProtocol(Protocol(dc.ClickHouseProtocol)))
+	}
+	if dc.ClickHouseUsername != "" {
+		opts = append(opts, WithAuth(dc.ClickHouseUsername, dc.ClickHousePassword))
+	}
+	if dc.ClickHouseDatabase != "" {
+		opts = append(opts, WithDatabase(dc.ClickHouseDatabase))
+	}
+	if dc.ClickHouseMaxReplicaDelay > 0 {
+		opts = append(opts, WithMaxReplicaDelay(dc.ClickHouseMaxReplicaDelay))
+	}
+	if dc.ClickHouseTLS != nil {
+		opts = append(opts, WithTLS(*dc.ClickHouseTLS))
+	}
+	if dc.TLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.TLSSkipVerify))
+	}
+	if dc.ClickHouseTLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.ClickHouseTLSSkipVerify))
+	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
+	return New(opts...)
+}
+
+// Check performs a health check against the ClickHouse endpoint.
+// In pool mode, uses the existing connection. In standalone mode, opens a new one.
+func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
+	if c.queryRow != nil {
+		return c.check(ctx, c.queryRow, "pool")
+	}
+	return c.checkStandalone(ctx, endpoint)
+}
+
+func (c *Checker) checkStandalone(ctx context.Context, endpoint dephealth.Endpoint) error {
+	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
+
+	opts := &clickhouse.Options{
+		Addr: []string{addr},
+		Auth: clickhouse.Auth{
+			Database: c.database,
+			Username: c.username,
+			Password: c.password,
+		},
+		DialTimeout:  defaultDialTimeout,
+		MaxOpenConns: 1,
+	}
+	if c.protocol == ProtocolHTTP {
+		opts.Protocol = clickhouse.HTTP
+	}
+	if c.tlsEnabled {
+		if c.tlsSkipVerify {
+			tlsSkipVerifyWarnOnce.Do(func() {
+				slog.Warn("dephealth: ClickHouse checker has TLS certificate verification disabled (InsecureSkipVerify=true)")
+			})
+		}
+		tlsCfg, err := c.tlsSource.Config()
+		if err != nil {
+			return fmt.Errorf("clickhouse %s: %w", addr, err)
+		}
+		if c.tlsSkipVerify {
+			tlsCfg.InsecureSkipVerify = true //nolint:gosec // user-configurable
+		}
+		opts.TLS = tlsCfg
+	}
+
+	db := clickhouse.OpenDB(opts)
+	defer func() { _ = db.Close() }()
+
+	queryRow := func(ctx context.Context, query string, dest ...any) error {
+		return db.QueryRowContext(ctx, query).Scan(dest...)
+	}
+	return c.check(ctx, queryRow, addr)
+}
+
+func (c *Checker) check(ctx context.Context, queryRow queryRowFunc, target string) error {
+	var one uint8
+	if err := queryRow(ctx, "SELECT 1", &one); err != nil {
+		return classifyError(err, target)
+	}
+
+	if c.maxReplicaDelay <= 0 {
+		return nil
+	}
+
+	var delay uint64
+	if err := queryRow(ctx, replicaDelayQuery, &delay); err != nil {
+		return classifyError(err, target)
+	}
+	if lag := time.Duration(delay) * time.Second; lag > c.maxReplicaDelay {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "replica_delay",
+			Cause:    fmt.Errorf("clickhouse %s: replica delay %v exceeds %v", target, lag, c.maxReplicaDelay),
+		}
+	}
+	return nil
+}
+
+// classifyError wraps ClickHouse errors with appropriate classification.
+// Detects auth errors via server exception codes (native protocol) and
+// HTTP 401/403 responses or exception text (HTTP protocol).
+func classifyError(err error, target string) error {
+	var ex *clickhouse.Exception
+	if errors.As(err, &ex) {
+		switch ex.Code {
+		case codeUnknownUser, codeWrongPassword, codeRequiredPassword, codeAuthenticationFailed:
+			return &dephealth.ClassifiedCheckError{
+				Category: dephealth.StatusAuthError,
+				Detail:   "auth_error",
+				Cause:    fmt.Errorf("clickhouse %s: %w", target, err),
+			}
+		}
+	}
+
+	msg := err.Error()
+	if strings.Contains(msg, "[HTTP 401]") || strings.Contains(msg, "[HTTP 403]") ||
+		strings.Contains(msg, "Code: 516") || strings.Contains(msg, "Authentication failed") {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusAuthError,
+			Detail:   "auth_error",
+			Cause:    fmt.Errorf("clickhouse %s: %w", target, err),
+		}
+	}
+
+	return fmt.Errorf("clickhouse query %s: %w", target, err)
+}
+
+// Type returns the dependency type for this checker.
+func (c *Checker) Type() string {
+	return string(dephealth.TypeClickHouse)
+}
@@ -0,0 +1,181 @@
+package clickhousecheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+)
+
+func TestChecker_Check_PoolMode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	checker := New(WithDB(db))
+	ep := dephealth.Endpoint{Host: "ignored", Port: "9000"}
+
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success in pool mode, got error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("not all sqlmock expectations were met: %v", err)
+	}
+}
+
+func TestChecker_Check_PoolMode_ReplicaDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		delay   uint64
+		wantErr bool
+	}{
+		{"within threshold", 5, false},
+		{"exceeds threshold", 120, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer func() { _ = db.Close() }()
+
+			mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+			mock.ExpectQuery("system.replicas").WillReturnRows(sqlmock.NewRows([]string{"delay"}).AddRow(tt.delay))
+
+			checker := New(WithDB(db), WithMaxReplicaDelay(time.Minute))
+			err = checker.Check(context.Background(), dephealth.Endpoint{Host: "ignored", Port: "9000"})
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("expected success, got error: %v", err)
+				}
+				return
+			}
+
+			var ce *dephealth.ClassifiedCheckError
+			if !errors.As(err, &ce) {
+				t.Fatalf("expected ClassifiedCheckError, got %v", err)
+			}
+			if ce.Category != dephealth.StatusUnhealthy || ce.Detail != "replica_delay" {
+				t.Errorf("expected unhealthy/replica_delay, got %s/%s", ce.Category, ce.Detail)
+			}
+		})
+	}
+}
+
+func TestChecker_Check_PoolMode_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	mock.ExpectQuery("SELECT 1").WillReturnError(&clickhouse.Exception{Code: 516, Message: "Authentication failed"})
+
+	err = New(WithDB(db)).Check(context.Background(), dephealth.Endpoint{Host: "ignored", Port: "9000"})
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected ClassifiedCheckError, got %v", err)
+	}
+	if ce.Category != dephealth.StatusAuthError {
+		t.Errorf("expected auth_error, got %s", ce.Category)
+	}
+}
+
+func TestChecker_Check_Standalone_HTTPAuthError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("Code: 516. DB::Exception: default: Authentication failed. (AUTHENTICATION_FAILED)"))
+	}))
+	defer srv.Close()
+
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	checker := New(WithProtocol(ProtocolHTTP), WithAuth("default", "wrong"))
+
+	err := checker.Check(context.Background(), dephealth.Endpoint{Host: host, Port: port})
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected ClassifiedCheckError, got %v", err)
+	}
+	if ce.Category != dephealth.StatusAuthError {
+		t.Errorf("expected auth_error, got %s", ce.Category)
+	}
+}
+
+func TestChecker_Check_Standalone_ConnectionRefused(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	checker := New()
+	ep := dephealth.Endpoint{Host: "127.0.0.1", Port: "1"}
+
+	if err := checker.Check(ctx, ep); err == nil {
+		t.Error("expected error for closed port, got nil")
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantAuth bool
+	}{
+		{"unknown user", &clickhouse.Exception{Code: 192}, true},
+		{"wrong password", &clickhouse.Exception{Code: 193}, true},
+		{"authentication failed", &clickhouse.Exception{Code: 516}, true},
+		{"http forbidden", errors.New(`[HTTP 403] response body: "Code: 516"`), true},
+		{"table missing", &clickhouse.Exception{Code: 60, Message: "Table doesn't exist"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyError(tt.err, "ch:9000")
+			var ce *dephealth.ClassifiedCheckError
+			isAuth := errors.As(err, &ce) && ce.Category == dephealth.StatusAuthError
+			if isAuth != tt.wantAuth {
+				t.Errorf("auth classification = %v, expected %v (err: %v)", isAuth, tt.wantAuth, err)
+			}
+		})
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	dc := &dephealth.DependencyConfig{
+		ClickHouseProtocol:        "http",
+		ClickHouseUsername:        "monitor",
+		ClickHousePassword:        "secret",
+		ClickHouseDatabase:        "analytics",
+		ClickHouseMaxReplicaDelay: 30 * time.Second,
+	}
+	checker, ok := NewFromConfig(dc).(*Checker)
+	if !ok {
+		t.Fatal("expected *Checker")
+	}
+	if checker.protocol != ProtocolHTTP {
+		t.Errorf("protocol = %q, expected %q", checker.protocol, ProtocolHTTP)
+	}
+	if checker.username != "monitor" || checker.password != "secret" || checker.database != "analytics" {
+		t.Errorf("unexpected auth/database: %q/%q/%q", checker.username, checker.password, checker.database)
+	}
+	if checker.maxReplicaDelay != 30*time.Second {
+		t.Errorf("maxReplicaDelay = %v, expected 30s", checker.maxReplicaDelay)
+	}
+}
+
+func TestChecker_Type(t *testing.T) {
+	checker := New()
+	if got := checker.Type(); got != "clickhouse" {
+		t.Errorf("Type() = %q, expected %q", got, "clickhouse")
+	}
+}
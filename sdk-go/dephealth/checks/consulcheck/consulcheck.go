@@ -0,0 +1,272 @@
+// Package consulcheck provides a Consul health checker for dephealth.
+//
+// Import this package to register the Consul checker factory:
+//
+//	import _ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/consulcheck"
+package consulcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
+)
+
+var tlsSkipVerifyWarnOnce sync.Once
+
+var _ dephealth.HealthChecker = (*Checker)(nil)
+
+func init() {
+	dephealth.RegisterCheckerFactory(dephealth.TypeConsul, NewFromConfig)
+}
+
+// Option configures the Checker.
+type Option func(*Checker)
+
+// Checker performs health checks against a Consul agent or server.
+// It queries /v1/status/leader and fails if the cluster has no elected leader.
+// Optionally also queries /v1/agent/self to verify the local agent.
+// Supports two modes:
+//   - Standalone: creates a new Consul API client per check
+//   - Pool: uses an existing *api.Client
+type Checker struct {
+	client     *api.Client // nil = standalone, non-nil = pool mode
+	token      string
+	agentCheck bool
+	tlsEnabled bool
+	tls        tlsutil.Options
+	tlsConfig  *tls.Config // base config from WithTLSConfig
+	tlsSource  *tlsutil.Source
+}
+
+// WithClient sets an existing Consul API client for pool mode.
+func WithClient(client *api.Client) Option {
+	return func(c *Checker) {
+		c.client = client
+	}
+}
+
+// WithToken sets the ACL token for standalone mode requests.
+func WithToken(token string) Option {
+	return func(c *Checker) {
+		c.token = token
+	}
+}
+
+// WithAgentCheck enables the additional /v1/agent/self check.
+func WithAgentCheck(enabled bool) Option {
+	return func(c *Checker) {
+		c.agentCheck = enabled
+	}
+}
+
+// WithTLS enables HTTPS for standalone mode requests.
+func WithTLS(enabled bool) Option {
+	return func(c *Checker) {
+		c.tlsEnabled = enabled
+	}
+}
+
+// WithTLSCA sets the PEM CA bundle used to verify the server certificate.
+// Implies TLS.
+func WithTLSCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tls.CAFile = caFile
+	}
+}
+
+// WithTLSClientCert sets the client certificate and key for mTLS.
+// Implies TLS.
+func WithTLSClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tls.CertFile = certFile
+		c.tls.KeyFile = keyFile
+	}
+}
+
+// WithTLSConfig sets the base TLS configuration and enables TLS. The files
+// of WithTLSCA and WithTLSClientCert are loaded on top of a copy and read
+// again when they change.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithTLSSkipVerify skips TLS certificate verification.
+func WithTLSSkipVerify(skip bool) Option {
+	return func(c *Checker) {
+		c.tls.SkipVerify = skip
+	}
+}
+
+// New creates a new Consul health checker with the given options.
+func New(opts ...Option) *Checker {
+	c := &Checker{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.tlsSource = tlsutil.NewSource(c.tlsConfig, c.tls)
+	return c
+}
+
+// NewFromConfig creates a Consul checker from DependencyConfig.
+func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
+	var opts []Option
+	if dc.ConsulToken != "" {
+		opts = append(opts, WithToken(dc.ConsulToken))
+	}
+	if dc.ConsulAgentCheck != nil {
+		opts = append(opts, WithAgentCheck(*dc.ConsulAgentCheck))
+	}
+	if dc.ConsulTLS != nil {
+		opts = append(opts, WithTLS(*dc.ConsulTLS))
+	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
+	if dc.ConsulTLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.ConsulTLSCAFile))
+	}
+	if dc.ConsulTLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.ConsulTLSCertFile, dc.ConsulTLSKeyFile))
+	}
+	if dc.TLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.TLSSkipVerify))
+	}
+	if dc.ConsulTLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.ConsulTLSSkipVerify))
+	}
+	return New(opts...)
+}
+
+// Check queries the Consul leader (and optionally the local agent).
+// In pool mode, uses the existing client. In standalone mode, creates a new client
+// targeting the endpoint.
+func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
+	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
+	if c.client != nil {
+		return c.checkClient(ctx, c.client, "pool")
+	}
+
+	client, transport, err := c.newClient(addr)
+	if err != nil {
+		return fmt.Errorf("consul %s: %w", addr, err)
+	}
+	defer transport.CloseIdleConnections()
+
+	return c.checkClient(ctx, client, addr)
+}
+
+func (c *Checker) newClient(addr string) (*api.Client, *http.Transport, error) {
+	transport := &http.Transport{}
+	cfg := &api.Config{
+		Address:    addr,
+		Scheme:     "http",
+		Token:      c.token,
+		HttpClient: &http.Client{Transport: transport},
+	}
+
+	if c.useTLS() {
+		if c.tls.SkipVerify {
+			tlsSkipVerifyWarnOnce.Do(func() {
+				slog.Warn("dephealth: Consul checker has TLS certificate verification disabled (InsecureSkipVerify=true)")
+			})
+		}
+		tlsCfg, err := c.tlsSource.Config()
+		if err != nil {
+			return nil, nil, err
+		}
+		transport.TLSClientConfig = tlsCfg
+		cfg.Scheme = "https"
+	}
+
+	client, err := api.NewClient(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, transport, nil
+}
+
+func (c *Checker) checkClient(ctx context.Context, client *api.Client, target string) error {
+	q := (&api.QueryOptions{}).WithContext(ctx)
+
+	leader, err := client.Status().LeaderWithQueryOptions(q)
+	if err != nil {
+		return classifyError(err, target)
+	}
+	if leader == "" {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "no_leader",
+			Cause:    fmt.Errorf("consul %s: cluster has no leader", target),
+		}
+	}
+
+	if c.agentCheck {
+		var self map[string]map[string]any
+		if _, err := client.Raw().Query("/v1/agent/self", &self, q); err != nil {
+			return classifyError(err, target)
+		}
+	}
+
+	return nil
+}
+
+// useTLS reports whether standalone requests use HTTPS.
+func (c *Checker) useTLS() bool {
+	return c.tlsEnabled || c.tlsConfig != nil || c.tls.CAFile != "" || c.tls.CertFile != ""
+}
+
+// classifyError wraps Consul API errors with appropriate classification.
+func classifyError(err error, target string) error {
+	var se api.StatusError
+	if errors.As(err, &se) {
+		if se.Code == http.StatusUnauthorized || se.Code == http.StatusForbidden {
+			return &dephealth.ClassifiedCheckError{
+				Category: dephealth.StatusAuthError,
+				Detail:   "auth_error",
+				Cause:    fmt.Errorf("consul %s: %w", target, err),
+			}
+		}
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   statusDetail(se.Code),
+			Cause:    fmt.Errorf("consul %s: %w", target, err),
+		}
+	}
+	return fmt.Errorf("consul %s: %w", target, err)
+}
+
+// statusDetail maps a rejected status code to a detail from the closed
+// set of the metric contract: http_4xx, http_5xx or unhealthy.
+func statusDetail(code int) string {
+	switch {
+	case code >= 500 && code <= 599:
+		return "http_5xx"
+	case code >= 400 && code <= 499:
+		return "http_4xx"
+	default:
+		return "unhealthy"
+	}
+}
+
+// Type returns the dependency type for this checker.
+func (c *Checker) Type() string {
+	return string(dephealth.TypeConsul)
+}
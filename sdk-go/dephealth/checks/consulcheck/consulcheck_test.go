@@ -0,0 +1,161 @@
+package consulcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+)
+
+// newConsulStub starts a fake Consul HTTP API with the given leader address.
+func newConsulStub(t *testing.T, leader string, agentStatus int) (*httptest.Server, dephealth.Endpoint) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/status/leader", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") == "bad" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("Permission denied"))
+			return
+		}
+		_, _ = w.Write([]byte(`"` + leader + `"`))
+	})
+	mux.HandleFunc("/v1/agent/self", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(agentStatus)
+		_, _ = w.Write([]byte(`{"Config":{"NodeName":"node-1"}}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	return srv, dephealth.Endpoint{Host: host, Port: port}
+}
+
+func TestChecker_Check_Success(t *testing.T) {
+	_, ep := newConsulStub(t, "10.0.0.1:8300", http.StatusOK)
+
+	checker := New(WithAgentCheck(true))
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success, got error: %v", err)
+	}
+}
+
+func TestChecker_Check_NoLeader(t *testing.T) {
+	_, ep := newConsulStub(t, "", http.StatusOK)
+
+	err := New().Check(context.Background(), ep)
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected ClassifiedCheckError, got %v", err)
+	}
+	if ce.Category != dephealth.StatusUnhealthy || ce.Detail != "no_leader" {
+		t.Errorf("expected unhealthy/no_leader, got %s/%s", ce.Category, ce.Detail)
+	}
+}
+
+func TestChecker_Check_AgentUnhealthy(t *testing.T) {
+	_, ep := newConsulStub(t, "10.0.0.1:8300", http.StatusInternalServerError)
+
+	// Without the agent check the leader check alone succeeds.
+	if err := New().Check(context.Background(), ep); err != nil {
+		t.Fatalf("expected success without agent check, got %v", err)
+	}
+
+	err := New(WithAgentCheck(true)).Check(context.Background(), ep)
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected ClassifiedCheckError, got %v", err)
+	}
+	if ce.Category != dephealth.StatusUnhealthy || ce.Detail != "http_5xx" {
+		t.Errorf("expected unhealthy/http_5xx, got %s/%s", ce.Category, ce.Detail)
+	}
+}
+
+func TestStatusDetail(t *testing.T) {
+	tests := []struct {
+		code int
+		want string
+	}{
+		{http.StatusInternalServerError, "http_5xx"},
+		{http.StatusServiceUnavailable, "http_5xx"},
+		{http.StatusNotFound, "http_4xx"},
+		{http.StatusTooManyRequests, "http_4xx"},
+		{http.StatusMultipleChoices, "unhealthy"},
+	}
+	for _, tt := range tests {
+		if got := statusDetail(tt.code); got != tt.want {
+			t.Errorf("statusDetail(%d) = %q, expected %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestChecker_Check_AuthError(t *testing.T) {
+	_, ep := newConsulStub(t, "10.0.0.1:8300", http.StatusOK)
+
+	err := New(WithToken("bad")).Check(context.Background(), ep)
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected ClassifiedCheckError, got %v", err)
+	}
+	if ce.Category != dephealth.StatusAuthError {
+		t.Errorf("expected auth_error, got %s", ce.Category)
+	}
+}
+
+func TestChecker_Check_PoolMode(t *testing.T) {
+	srv, _ := newConsulStub(t, "10.0.0.1:8300", http.StatusOK)
+
+	client, err := api.NewClient(&api.Config{Address: srv.Listener.Addr().String()})
+	if err != nil {
+		t.Fatalf("api.NewClient: %v", err)
+	}
+
+	checker := New(WithClient(client))
+	ep := dephealth.Endpoint{Host: "ignored", Port: "8500"}
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success in pool mode, got error: %v", err)
+	}
+}
+
+func TestChecker_Check_ConnectionRefused(t *testing.T) {
+	checker := New()
+	ep := dephealth.Endpoint{Host: "127.0.0.1", Port: "1"}
+
+	if err := checker.Check(context.Background(), ep); err == nil {
+		t.Error("expected error for closed port, got nil")
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	agent := true
+	dc := &dephealth.DependencyConfig{
+		ConsulToken:      "acl-token",
+		ConsulAgentCheck: &agent,
+		ConsulTLSCAFile:  "/etc/consul/ca.pem",
+	}
+	checker, ok := NewFromConfig(dc).(*Checker)
+	if !ok {
+		t.Fatal("expected *Checker")
+	}
+	if checker.token != "acl-token" {
+		t.Errorf("token = %q, expected %q", checker.token, "acl-token")
+	}
+	if !checker.agentCheck {
+		t.Error("expected agent check enabled")
+	}
+	if !checker.useTLS() {
+		t.Error("expected CA file to imply TLS")
+	}
+}
+
+func TestChecker_Type(t *testing.T) {
+	checker := New()
+	if got := checker.Type(); got != "consul" {
+		t.Errorf("Type() = %q, expected %q", got, "consul")
+	}
+}
@@ -0,0 +1,373 @@
+// Package dnscheck provides a DNS resolution health checker for dephealth.
+//
+// Import this package to register the DNS checker factory:
+//
+//	import _ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/dnscheck"
+package dnscheck
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+)
+
+// defaultDialTimeout bounds a query when the context carries no deadline.
+const defaultDialTimeout = 3 * time.Second
+
+// udpPayloadSize is the EDNS(0) UDP payload size advertised to the resolver.
+const udpPayloadSize = 4096
+
+// Protocols.
+const (
+	ProtocolUDP = "udp" // UDP with TCP fallback on truncated answers (default)
+	ProtocolTCP = "tcp" // TCP only
+)
+
+var _ dephealth.HealthChecker = (*Checker)(nil)
+
+func init() {
+	dephealth.RegisterCheckerFactory(dephealth.TypeDNS, NewFromConfig)
+}
+
+// recordTypes maps supported record type names to DNS types.
+var recordTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"CNAME": dnsmessage.TypeCNAME,
+	"SRV":   dnsmessage.TypeSRV,
+	"TXT":   dnsmessage.TypeTXT,
+}
+
+// Option configures the Checker.
+type Option func(*Checker)
+
+// Checker performs health checks by sending a DNS query for a configured
+// name and record type directly to the resolver at the endpoint.
+// The check succeeds if the resolver answers NOERROR with at least one record
+// of the requested type and, when expected values are configured, every
+// expected value is present in the answer.
+type Checker struct {
+	name       string
+	recordType string
+	expected   []string
+	protocol   string
+}
+
+// WithName sets the domain name to resolve.
+func WithName(name string) Option {
+	return func(c *Checker) {
+		c.name = name
+	}
+}
+
+// WithRecordType sets the record type to query: A (default), AAAA, CNAME, SRV or TXT.
+func WithRecordType(recordType string) Option {
+	return func(c *Checker) {
+		c.recordType = strings.ToUpper(recordType)
+	}
+}
+
+// WithExpected sets values that must all be present in the answer.
+// Formats: A/AAAA — IP address, CNAME — target name, SRV — "target:port",
+// TXT — the record text. Names are compared case-insensitively without the
+// trailing dot.
+func WithExpected(values ...string) Option {
+	return func(c *Checker) {
+		c.expected = append(c.expected, values...)
+	}
+}
+
+// WithProtocol sets the transport: ProtocolUDP (default) or ProtocolTCP.
+func WithProtocol(protocol string) Option {
+	return func(c *Checker) {
+		c.protocol = strings.ToLower(protocol)
+	}
+}
+
+// New creates a new DNS health checker with the given options.
+func New(opts ...Option) *Checker {
+	c := &Checker{
+		recordType: "A",
+		protocol:   ProtocolUDP,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// NewFromConfig creates a DNS checker from DependencyConfig.
+func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
+	opts := []Option{WithName(dc.DNSName)}
+	if dc.DNSRecordType != "" {
+		opts = append(opts, WithRecordType(dc.DNSRecordType))
+	}
+	if len(dc.DNSExpected) > 0 {
+		opts = append(opts, WithExpected(dc.DNSExpected...))
+	}
+	if dc.DNSProtocol != "" {
+		opts = append(opts, WithProtocol(dc.DNSProtocol))
+	}
+	return New(opts...)
+}
+
+// Check queries the resolver at the endpoint and validates the answer.
+func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
+	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
+
+	qtype, ok := recordTypes[c.recordType]
+	if !ok {
+		return fmt.Errorf("dns %s: unsupported record type %q", addr, c.recordType)
+	}
+	if c.protocol != ProtocolUDP && c.protocol != ProtocolTCP {
+		return fmt.Errorf("dns %s: unsupported protocol %q", addr, c.protocol)
+	}
+	fqdn := c.name
+	if !strings.HasSuffix(fqdn, ".") {
+		fqdn += "."
+	}
+	qname, err := dnsmessage.NewName(fqdn)
+	if err != nil {
+		return fmt.Errorf("dns %s: invalid name %q: %w", addr, c.name, err)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultDialTimeout)
+		defer cancel()
+	}
+
+	query, id, err := buildQuery(qname, qtype)
+	if err != nil {
+		return fmt.Errorf("dns %s: build query: %w", addr, err)
+	}
+
+	var resp *dnsmessage.Message
+	if c.protocol == ProtocolTCP {
+		resp, err = exchangeTCP(ctx, addr, query, id)
+	} else {
+		resp, err = exchangeUDP(ctx, addr, query, id)
+		if err == nil && resp.Truncated {
+			resp, err = exchangeTCP(ctx, addr, query, id)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("dns query %s %s %s: %w", addr, c.recordType, c.name, err)
+	}
+
+	return c.validate(resp, qtype, addr)
+}
+
+// validate classifies the response code and checks the answer records.
+func (c *Checker) validate(resp *dnsmessage.Message, qtype dnsmessage.Type, addr string) error {
+	query := fmt.Sprintf("%s %s", c.recordType, c.name)
+
+	switch resp.RCode {
+	case dnsmessage.RCodeSuccess:
+		// continue below
+	case dnsmessage.RCodeNameError:
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusDNSError,
+			Detail:   "dns_nxdomain",
+			Cause:    fmt.Errorf("dns %s: %s: NXDOMAIN", addr, query),
+		}
+	case dnsmessage.RCodeServerFailure:
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusDNSError,
+			Detail:   "dns_servfail",
+			Cause:    fmt.Errorf("dns %s: %s: SERVFAIL", addr, query),
+		}
+	case dnsmessage.RCodeRefused:
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusDNSError,
+			Detail:   "dns_refused",
+			Cause:    fmt.Errorf("dns %s: %s: REFUSED", addr, query),
+		}
+	default:
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusDNSError,
+			Detail:   "dns_error",
+			Cause:    fmt.Errorf("dns %s: %s: rcode %s", addr, query, resp.RCode),
+		}
+	}
+
+	values := answerValues(resp.Answers, qtype)
+	if len(values) == 0 {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "dns_no_records",
+			Cause:    fmt.Errorf("dns %s: %s: empty answer", addr, query),
+		}
+	}
+
+	for _, want := range c.expected {
+		if !slices.Contains(values, normalize(want, qtype)) {
+			return &dephealth.ClassifiedCheckError{
+				Category: dephealth.StatusUnhealthy,
+				Detail:   "dns_mismatch",
+				Cause: fmt.Errorf("dns %s: %s: expected %q, got [%s]",
+					addr, query, want, strings.Join(values, ", ")),
+			}
+		}
+	}
+	return nil
+}
+
+// answerValues returns the normalized values of answers with the requested type.
+func answerValues(answers []dnsmessage.Resource, qtype dnsmessage.Type) []string {
+	var values []string
+	for _, rr := range answers {
+		if rr.Header.Type != qtype {
+			continue
+		}
+		switch body := rr.Body.(type) {
+		case *dnsmessage.AResource:
+			values = append(values, net.IP(body.A[:]).String())
+		case *dnsmessage.AAAAResource:
+			values = append(values, net.IP(body.AAAA[:]).String())
+		case *dnsmessage.CNAMEResource:
+			values = append(values, normalizeName(body.CNAME.String()))
+		case *dnsmessage.SRVResource:
+			values = append(values, net.JoinHostPort(normalizeName(body.Target.String()), strconv.Itoa(int(body.Port))))
+		case *dnsmessage.TXTResource:
+			values = append(values, strings.Join(body.TXT, ""))
+		}
+	}
+	return values
+}
+
+// normalize converts an expected value to the form produced by answerValues.
+func normalize(value string, qtype dnsmessage.Type) string {
+	switch qtype {
+	case dnsmessage.TypeA, dnsmessage.TypeAAAA:
+		if ip := net.ParseIP(value); ip != nil {
+			return ip.String()
+		}
+	case dnsmessage.TypeCNAME:
+		return normalizeName(value)
+	case dnsmessage.TypeSRV:
+		if host, port, err := net.SplitHostPort(value); err == nil {
+			return net.JoinHostPort(normalizeName(host), port)
+		}
+	}
+	return value
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// buildQuery packs a recursive query for a single question with an EDNS(0)
+// OPT record and returns it together with its message ID.
+func buildQuery(name dnsmessage.Name, qtype dnsmessage.Type) ([]byte, uint16, error) {
+	var idb [2]byte
+	if _, err := rand.Read(idb[:]); err != nil {
+		return nil, 0, err
+	}
+	id := binary.BigEndian.Uint16(idb[:])
+
+	b := dnsmessage.NewBuilder(make([]byte, 0, 512), dnsmessage.Header{ID: id, RecursionDesired: true})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, 0, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: name, Type: qtype, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, 0, err
+	}
+	if err := b.StartAdditionals(); err != nil {
+		return nil, 0, err
+	}
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(udpPayloadSize, dnsmessage.RCodeSuccess, false); err != nil {
+		return nil, 0, err
+	}
+	if err := b.OPTResource(opt, dnsmessage.OPTResource{}); err != nil {
+		return nil, 0, err
+	}
+	msg, err := b.Finish()
+	return msg, id, err
+}
+
+// exchangeUDP sends the query over UDP and waits for the response with a
+// matching ID.
+func exchangeUDP(ctx context.Context, addr string, query []byte, id uint16) (*dnsmessage.Message, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, udpPayloadSize)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil || msg.ID != id || !msg.Response {
+			continue // ignore malformed or unrelated datagrams
+		}
+		return &msg, nil
+	}
+}
+
+// exchangeTCP sends the length-prefixed query over TCP and reads the response.
+func exchangeTCP(ctx context.Context, addr string, query []byte, id uint16) (*dnsmessage.Message, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req := make([]byte, 2+len(query))
+	binary.BigEndian.PutUint16(req, uint16(len(query))) //nolint:gosec // DNS messages are < 64 KiB
+	copy(req[2:], query)
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+
+	var lenb [2]byte
+	if _, err := io.ReadFull(conn, lenb[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(lenb[:]))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, err
+	}
+	var msg dnsmessage.Message
+	if err := msg.Unpack(buf); err != nil {
+		return nil, fmt.Errorf("malformed response: %w", err)
+	}
+	if msg.ID != id {
+		return nil, errors.New("response ID mismatch")
+	}
+	return &msg, nil
+}
+
+// Type returns the dependency type for this checker.
+func (c *Checker) Type() string {
+	return string(dephealth.TypeDNS)
+}
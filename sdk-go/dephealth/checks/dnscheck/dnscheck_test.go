@@ -0,0 +1,339 @@
+package dnscheck
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+)
+
+// stubServer is an in-process DNS server answering on UDP and TCP at the
+// same port from a static zone.
+type stubServer struct {
+	zone     map[string][]dnsmessage.Resource // key: "name.|TYPE"
+	rcode    dnsmessage.RCode
+	truncUDP bool // mark UDP answers truncated to force a TCP retry
+	udpHits  atomic.Int64
+	tcpHits  atomic.Int64
+}
+
+func key(name string, t dnsmessage.Type) string {
+	return strings.ToLower(name) + "|" + t.String()
+}
+
+func (s *stubServer) add(name string, body dnsmessage.ResourceBody) {
+	if s.zone == nil {
+		s.zone = make(map[string][]dnsmessage.Resource)
+	}
+	rr := dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{
+			Name:  dnsmessage.MustNewName(name),
+			Class: dnsmessage.ClassINET,
+			TTL:   60,
+		},
+		Body: body,
+	}
+	rr.Header.Type = rrType(body)
+	k := key(name, rrType(body))
+	s.zone[k] = append(s.zone[k], rr)
+}
+
+func rrType(body dnsmessage.ResourceBody) dnsmessage.Type {
+	switch body.(type) {
+	case *dnsmessage.AResource:
+		return dnsmessage.TypeA
+	case *dnsmessage.AAAAResource:
+		return dnsmessage.TypeAAAA
+	case *dnsmessage.CNAMEResource:
+		return dnsmessage.TypeCNAME
+	case *dnsmessage.SRVResource:
+		return dnsmessage.TypeSRV
+	case *dnsmessage.TXTResource:
+		return dnsmessage.TypeTXT
+	}
+	return 0
+}
+
+func (s *stubServer) answer(t *testing.T, req []byte, udp bool) []byte {
+	t.Helper()
+	var msg dnsmessage.Message
+	if err := msg.Unpack(req); err != nil || len(msg.Questions) != 1 {
+		return nil
+	}
+	q := msg.Questions[0]
+	resp := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:                 msg.ID,
+			Response:           true,
+			RecursionDesired:   msg.RecursionDesired,
+			RecursionAvailable: true,
+			RCode:              s.rcode,
+		},
+		Questions: msg.Questions,
+	}
+	if udp && s.truncUDP {
+		resp.Truncated = true
+	} else if s.rcode == dnsmessage.RCodeSuccess {
+		resp.Answers = s.zone[key(q.Name.String(), q.Type)]
+	}
+	out, err := resp.Pack()
+	if err != nil {
+		t.Errorf("stub: pack response: %v", err)
+		return nil
+	}
+	return out
+}
+
+// start runs the stub on 127.0.0.1 and returns the endpoint.
+func (s *stubServer) start(t *testing.T) dephealth.Endpoint {
+	t.Helper()
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen tcp: %v", err)
+	}
+	port := tcp.Addr().(*net.TCPAddr).Port
+	udp, err := net.ListenPacket("udp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		_ = tcp.Close()
+		t.Skipf("UDP port %d unavailable: %v", port, err)
+	}
+	t.Cleanup(func() {
+		_ = tcp.Close()
+		_ = udp.Close()
+	})
+
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, from, err := udp.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			s.udpHits.Add(1)
+			if out := s.answer(t, buf[:n], true); out != nil {
+				_, _ = udp.WriteTo(out, from)
+			}
+		}
+	}()
+	go func() {
+		for {
+			conn, err := tcp.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				var lenb [2]byte
+				if _, err := io.ReadFull(conn, lenb[:]); err != nil {
+					return
+				}
+				req := make([]byte, binary.BigEndian.Uint16(lenb[:]))
+				if _, err := io.ReadFull(conn, req); err != nil {
+					return
+				}
+				s.tcpHits.Add(1)
+				out := s.answer(t, req, false)
+				if out == nil {
+					return
+				}
+				resp := make([]byte, 2+len(out))
+				binary.BigEndian.PutUint16(resp, uint16(len(out))) //nolint:gosec // test message
+				copy(resp[2:], out)
+				_, _ = conn.Write(resp)
+			}()
+		}
+	}()
+	return dephealth.Endpoint{Host: "127.0.0.1", Port: strconv.Itoa(port)}
+}
+
+func checkCtx(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func expectDetail(t *testing.T, err error, category dephealth.StatusCategory, detail string) {
+	t.Helper()
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected ClassifiedCheckError, got %v", err)
+	}
+	if ce.Category != category || ce.Detail != detail {
+		t.Errorf("got %s/%s, expected %s/%s (%v)", ce.Category, ce.Detail, category, detail, err)
+	}
+}
+
+func TestChecker_Check_A(t *testing.T) {
+	s := &stubServer{}
+	s.add("api.example.com.", &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}})
+	s.add("api.example.com.", &dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}})
+	ep := s.start(t)
+
+	if err := New(WithName("api.example.com")).Check(checkCtx(t), ep); err != nil {
+		t.Errorf("expected success, got: %v", err)
+	}
+	checker := New(WithName("api.example.com"), WithExpected("10.0.0.2"))
+	if err := checker.Check(checkCtx(t), ep); err != nil {
+		t.Errorf("expected success with expected value, got: %v", err)
+	}
+	checker = New(WithName("api.example.com"), WithExpected("10.0.0.1", "10.0.0.9"))
+	expectDetail(t, checker.Check(checkCtx(t), ep), dephealth.StatusUnhealthy, "dns_mismatch")
+	if s.tcpHits.Load() != 0 {
+		t.Errorf("expected UDP only, got %d TCP queries", s.tcpHits.Load())
+	}
+}
+
+func TestChecker_Check_AAAA(t *testing.T) {
+	s := &stubServer{}
+	s.add("api.example.com.", &dnsmessage.AAAAResource{AAAA: [16]byte{0x20, 0x01, 0x0d, 0xb8, 15: 1}})
+	ep := s.start(t)
+
+	checker := New(WithName("api.example.com"), WithRecordType("aaaa"), WithExpected("2001:db8:0::1"))
+	if err := checker.Check(checkCtx(t), ep); err != nil {
+		t.Errorf("expected success, got: %v", err)
+	}
+}
+
+func TestChecker_Check_CNAME(t *testing.T) {
+	s := &stubServer{}
+	s.add("www.example.com.", &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("lb.example.net.")})
+	ep := s.start(t)
+
+	checker := New(WithName("www.example.com."), WithRecordType("CNAME"), WithExpected("LB.example.net"))
+	if err := checker.Check(checkCtx(t), ep); err != nil {
+		t.Errorf("expected success, got: %v", err)
+	}
+}
+
+func TestChecker_Check_SRV(t *testing.T) {
+	s := &stubServer{}
+	s.add("_pg._tcp.db.svc.", &dnsmessage.SRVResource{Priority: 10, Weight: 5, Port: 5432, Target: dnsmessage.MustNewName("pg-0.db.svc.")})
+	ep := s.start(t)
+
+	checker := New(WithName("_pg._tcp.db.svc"), WithRecordType("SRV"), WithExpected("pg-0.db.svc:5432"))
+	if err := checker.Check(checkCtx(t), ep); err != nil {
+		t.Errorf("expected success, got: %v", err)
+	}
+}
+
+func TestChecker_Check_TXT(t *testing.T) {
+	s := &stubServer{}
+	s.add("example.com.", &dnsmessage.TXTResource{TXT: []string{"v=spf1 ", "-all"}})
+	ep := s.start(t)
+
+	checker := New(WithName("example.com"), WithRecordType("TXT"), WithExpected("v=spf1 -all"))
+	if err := checker.Check(checkCtx(t), ep); err != nil {
+		t.Errorf("expected success, got: %v", err)
+	}
+}
+
+func TestChecker_Check_EmptyAnswer(t *testing.T) {
+	s := &stubServer{}
+	s.add("api.example.com.", &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}})
+	ep := s.start(t)
+
+	err := New(WithName("api.example.com"), WithRecordType("AAAA")).Check(checkCtx(t), ep)
+	expectDetail(t, err, dephealth.StatusUnhealthy, "dns_no_records")
+}
+
+func TestChecker_Check_RCodes(t *testing.T) {
+	tests := []struct {
+		rcode  dnsmessage.RCode
+		detail string
+	}{
+		{dnsmessage.RCodeNameError, "dns_nxdomain"},
+		{dnsmessage.RCodeServerFailure, "dns_servfail"},
+		{dnsmessage.RCodeRefused, "dns_refused"},
+		{dnsmessage.RCodeNotImplemented, "dns_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.detail, func(t *testing.T) {
+			s := &stubServer{rcode: tt.rcode}
+			ep := s.start(t)
+			err := New(WithName("missing.example.com")).Check(checkCtx(t), ep)
+			expectDetail(t, err, dephealth.StatusDNSError, tt.detail)
+		})
+	}
+}
+
+func TestChecker_Check_TruncatedFallsBackToTCP(t *testing.T) {
+	s := &stubServer{truncUDP: true}
+	s.add("api.example.com.", &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}})
+	ep := s.start(t)
+
+	if err := New(WithName("api.example.com")).Check(checkCtx(t), ep); err != nil {
+		t.Errorf("expected success, got: %v", err)
+	}
+	if s.udpHits.Load() != 1 || s.tcpHits.Load() != 1 {
+		t.Errorf("expected 1 UDP and 1 TCP query, got %d/%d", s.udpHits.Load(), s.tcpHits.Load())
+	}
+}
+
+func TestChecker_Check_TCP(t *testing.T) {
+	s := &stubServer{}
+	s.add("api.example.com.", &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}})
+	ep := s.start(t)
+
+	if err := New(WithName("api.example.com"), WithProtocol("TCP")).Check(checkCtx(t), ep); err != nil {
+		t.Errorf("expected success, got: %v", err)
+	}
+	if s.udpHits.Load() != 0 {
+		t.Errorf("expected TCP only, got %d UDP queries", s.udpHits.Load())
+	}
+}
+
+func TestChecker_Check_NoResolver(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err := New(WithName("api.example.com"), WithProtocol(ProtocolTCP)).Check(ctx,
+		dephealth.Endpoint{Host: "127.0.0.1", Port: "1"})
+	if err == nil {
+		t.Fatal("expected error for closed port, got nil")
+	}
+}
+
+func TestChecker_Check_InvalidConfig(t *testing.T) {
+	ep := dephealth.Endpoint{Host: "127.0.0.1", Port: "53"}
+	if err := New(WithName("example.com"), WithRecordType("MX")).Check(context.Background(), ep); err == nil {
+		t.Error("expected error for unsupported record type")
+	}
+	if err := New(WithName("example.com"), WithProtocol("quic")).Check(context.Background(), ep); err == nil {
+		t.Error("expected error for unsupported protocol")
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	dc := &dephealth.DependencyConfig{
+		DNSName:       "_pg._tcp.db.svc",
+		DNSRecordType: "srv",
+		DNSExpected:   []string{"pg-0.db.svc:5432"},
+		DNSProtocol:   "tcp",
+	}
+	checker, ok := NewFromConfig(dc).(*Checker)
+	if !ok {
+		t.Fatal("expected *Checker")
+	}
+	if checker.name != "_pg._tcp.db.svc" || checker.recordType != "SRV" || checker.protocol != ProtocolTCP {
+		t.Errorf("unexpected checker: %+v", checker)
+	}
+	if len(checker.expected) != 1 {
+		t.Errorf("expected 1 expected value, got %d", len(checker.expected))
+	}
+}
+
+func TestChecker_Type(t *testing.T) {
+	if got := New().Type(); got != "dns" {
+		t.Errorf("Type() = %q, expected %q", got, "dns")
+	}
+}
@@ -9,6 +9,11 @@ package checks
 
 import (
 	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/amqpcheck"
+	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/cassandracheck"
+	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/clickhousecheck"
+	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/consulcheck"
+	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/dnscheck"
+	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/etcdcheck"
 	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/grpccheck"
 	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/httpcheck"
 	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/kafkacheck"
@@ -17,4 +22,5 @@ import (
 	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/pgcheck"
 	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/redischeck"
 	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/tcpcheck"
+	_ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/tlscheck"
 )
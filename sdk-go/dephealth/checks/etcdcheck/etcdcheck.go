@@ -0,0 +1,277 @@
+// Package etcdcheck provides an etcd health checker for dephealth.
+//
+// Import this package to register the etcd checker factory:
+//
+//	import _ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/etcdcheck"
+package etcdcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
+)
+
+// defaultDialTimeout bounds standalone connections and status calls when the
+// context carries no deadline, so that an unreachable member cannot block forever.
+const defaultDialTimeout = 3 * time.Second
+
+var tlsSkipVerifyWarnOnce sync.Once
+
+var _ dephealth.HealthChecker = (*Checker)(nil)
+
+func init() {
+	dephealth.RegisterCheckerFactory(dephealth.TypeEtcd, NewFromConfig)
+}
+
+// maintenance is the subset of clientv3.Maintenance used by the checker.
+type maintenance interface {
+	Status(ctx context.Context, endpoint string) (*clientv3.StatusResponse, error)
+}
+
+// Option configures the Checker.
+type Option func(*Checker)
+
+// Checker performs health checks against an etcd member using the
+// Maintenance.Status RPC. The check succeeds if the member responds and
+// reports a known cluster leader.
+// Supports two modes:
+//   - Standalone: creates a new etcd client per check
+//   - Pool: uses an existing *clientv3.Client
+type Checker struct {
+	maint      maintenance // nil = standalone, non-nil = pool mode
+	username   string
+	password   string
+	tlsEnabled bool
+	tls        tlsutil.Options
+	tlsConfig  *tls.Config // base config from WithTLSConfig
+	tlsSource  *tlsutil.Source
+}
+
+// WithClient sets an existing etcd client for pool mode.
+func WithClient(client *clientv3.Client) Option {
+	return func(c *Checker) {
+		c.maint = client.Maintenance
+	}
+}
+
+// WithAuth sets the username and password for standalone mode connections.
+func WithAuth(username, password string) Option {
+	return func(c *Checker) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithTLS enables TLS for standalone mode connections.
+func WithTLS(enabled bool) Option {
+	return func(c *Checker) {
+		c.tlsEnabled = enabled
+	}
+}
+
+// WithTLSCA sets the PEM CA bundle used to verify the server certificate.
+// Implies TLS.
+func WithTLSCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tls.CAFile = caFile
+	}
+}
+
+// WithTLSClientCert sets the client certificate and key for mTLS.
+// Implies TLS.
+func WithTLSClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tls.CertFile = certFile
+		c.tls.KeyFile = keyFile
+	}
+}
+
+// WithTLSConfig sets the base TLS configuration and enables TLS. The files
+// of WithTLSCA and WithTLSClientCert are loaded on top of a copy and read
+// again when they change.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithTLSSkipVerify skips TLS certificate verification.
+func WithTLSSkipVerify(skip bool) Option {
+	return func(c *Checker) {
+		c.tls.SkipVerify = skip
+	}
+}
+
+// New creates a new etcd health checker with the given options.
+func New(opts ...Option) *Checker {
+	c := &Checker{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.tlsSource = tlsutil.NewSource(c.tlsConfig, c.tls)
+	return c
+}
+
+// NewFromConfig creates an etcd checker from DependencyConfig.
+func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
+	var opts []Option
+	if dc.EtcdUsername != "" {
+		opts = append(opts, WithAuth(dc.EtcdUsername, dc.EtcdPassword))
+	}
+	if dc.EtcdTLS != nil {
+		opts = append(opts, WithTLS(*dc.EtcdTLS))
+	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
+	if dc.EtcdTLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.EtcdTLSCAFile))
+	}
+	if dc.EtcdTLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.EtcdTLSCertFile, dc.EtcdTLSKeyFile))
+	}
+	if dc.TLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.TLSSkipVerify))
+	}
+	if dc.EtcdTLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.EtcdTLSSkipVerify))
+	}
+	return New(opts...)
+}
+
+// Check queries the status of the etcd member at the endpoint.
+// Returns nil if the member responds and knows the current leader.
+func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
+	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultDialTimeout)
+		defer cancel()
+	}
+
+	if c.maint != nil {
+		return c.checkStatus(ctx, c.maint, addr)
+	}
+	return c.checkStandalone(ctx, addr)
+}
+
+func (c *Checker) checkStandalone(ctx context.Context, addr string) error {
+	cfg := clientv3.Config{
+		Endpoints:   []string{"http://" + addr},
+		DialTimeout: defaultDialTimeout,
+		Username:    c.username,
+		Password:    c.password,
+		Context:     ctx,
+		Logger:      zap.NewNop(),
+	}
+
+	if c.useTLS() {
+		if c.tls.SkipVerify {
+			tlsSkipVerifyWarnOnce.Do(func() {
+				slog.Warn("dephealth: etcd checker has TLS certificate verification disabled (InsecureSkipVerify=true)")
+			})
+		}
+		tlsCfg, err := c.tlsSource.Config()
+		if err != nil {
+			return fmt.Errorf("etcd %s: %w", addr, err)
+		}
+		cfg.TLS = tlsCfg
+		cfg.Endpoints = []string{"https://" + addr}
+	}
+
+	client, err := clientv3.New(cfg)
+	if err != nil {
+		return classifyError(err, addr)
+	}
+	defer func() { _ = client.Close() }()
+
+	return c.checkStatus(ctx, client.Maintenance, addr)
+}
+
+func (c *Checker) checkStatus(ctx context.Context, m maintenance, addr string) error {
+	resp, err := m.Status(ctx, addr)
+	if err != nil {
+		return classifyError(err, addr)
+	}
+	if resp.Leader == 0 {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "no_leader",
+			Cause:    fmt.Errorf("etcd %s: member reports no leader", addr),
+		}
+	}
+	return nil
+}
+
+// useTLS reports whether standalone connections use TLS.
+func (c *Checker) useTLS() bool {
+	return c.tlsEnabled || c.tlsConfig != nil || c.tls.CAFile != "" || c.tls.CertFile != ""
+}
+
+// classifyError wraps etcd errors with appropriate classification.
+func classifyError(err error, target string) error {
+	if errors.Is(err, rpctypes.ErrNoLeader) {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "no_leader",
+			Cause:    fmt.Errorf("etcd %s: %w", target, err),
+		}
+	}
+
+	if errors.Is(err, rpctypes.ErrAuthFailed) || errors.Is(err, rpctypes.ErrPermissionDenied) ||
+		errors.Is(err, rpctypes.ErrInvalidAuthToken) {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusAuthError,
+			Detail:   "auth_error",
+			Cause:    fmt.Errorf("etcd %s: %w", target, err),
+		}
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unauthenticated, codes.PermissionDenied:
+			return &dephealth.ClassifiedCheckError{
+				Category: dephealth.StatusAuthError,
+				Detail:   "auth_error",
+				Cause:    fmt.Errorf("etcd %s: %w", target, err),
+			}
+		case codes.Unavailable:
+			if strings.Contains(s.Message(), "connection refused") {
+				return &dephealth.ClassifiedCheckError{
+					Category: dephealth.StatusConnectionError,
+					Detail:   "connection_refused",
+					Cause:    fmt.Errorf("etcd %s: %w", target, err),
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("etcd status %s: %w", target, err)
+}
+
+// Type returns the dependency type for this checker.
+func (c *Checker) Type() string {
+	return string(dephealth.TypeEtcd)
+}
@@ -0,0 +1,143 @@
+package etcdcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+)
+
+// fakeMaintenance is a stub for the Maintenance.Status RPC.
+type fakeMaintenance struct {
+	leader   uint64
+	err      error
+	endpoint string
+}
+
+func (f *fakeMaintenance) Status(_ context.Context, endpoint string) (*clientv3.StatusResponse, error) {
+	f.endpoint = endpoint
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &clientv3.StatusResponse{Header: &pb.ResponseHeader{MemberId: 1}, Leader: f.leader}, nil
+}
+
+func TestChecker_Check_PoolMode_Leader(t *testing.T) {
+	fake := &fakeMaintenance{leader: 42}
+	checker := &Checker{maint: fake}
+	ep := dephealth.Endpoint{Host: "etcd-0.svc", Port: "2379"}
+
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success, got error: %v", err)
+	}
+	if fake.endpoint != "etcd-0.svc:2379" {
+		t.Errorf("status endpoint = %q, expected %q", fake.endpoint, "etcd-0.svc:2379")
+	}
+}
+
+func TestChecker_Check_PoolMode_NoLeader(t *testing.T) {
+	checker := &Checker{maint: &fakeMaintenance{leader: 0}}
+	ep := dephealth.Endpoint{Host: "etcd-0.svc", Port: "2379"}
+
+	err := checker.Check(context.Background(), ep)
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected ClassifiedCheckError, got %v", err)
+	}
+	if ce.Category != dephealth.StatusUnhealthy || ce.Detail != "no_leader" {
+		t.Errorf("expected unhealthy/no_leader, got %s/%s", ce.Category, ce.Detail)
+	}
+}
+
+func TestChecker_Check_Standalone_ConnectionRefused(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	checker := New()
+	ep := dephealth.Endpoint{Host: "127.0.0.1", Port: "1"}
+
+	if err := checker.Check(ctx, ep); err == nil {
+		t.Error("expected error for closed port, got nil")
+	}
+}
+
+func TestChecker_Check_Standalone_InvalidTLSFiles(t *testing.T) {
+	checker := New(WithTLSCA("/nonexistent/ca.pem"))
+	ep := dephealth.Endpoint{Host: "127.0.0.1", Port: "2379"}
+
+	if err := checker.Check(context.Background(), ep); err == nil {
+		t.Error("expected error for missing CA file, got nil")
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		category dephealth.StatusCategory
+		detail   string
+	}{
+		{"no leader", rpctypes.ErrNoLeader, dephealth.StatusUnhealthy, "no_leader"},
+		{"auth failed", rpctypes.ErrAuthFailed, dephealth.StatusAuthError, "auth_error"},
+		{"permission denied", rpctypes.ErrPermissionDenied, dephealth.StatusAuthError, "auth_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ce *dephealth.ClassifiedCheckError
+			if !errors.As(classifyError(tt.err, "etcd:2379"), &ce) {
+				t.Fatal("expected ClassifiedCheckError")
+			}
+			if ce.Category != tt.category || ce.Detail != tt.detail {
+				t.Errorf("expected %s/%s, got %s/%s", tt.category, tt.detail, ce.Category, ce.Detail)
+			}
+		})
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	enabled := true
+	dc := &dephealth.DependencyConfig{
+		EtcdUsername:    "root",
+		EtcdPassword:    "secret",
+		EtcdTLS:         &enabled,
+		EtcdTLSCAFile:   "/etc/etcd/ca.pem",
+		EtcdTLSCertFile: "/etc/etcd/client.pem",
+		EtcdTLSKeyFile:  "/etc/etcd/client-key.pem",
+	}
+	checker, ok := NewFromConfig(dc).(*Checker)
+	if !ok {
+		t.Fatal("expected *Checker")
+	}
+	if checker.username != "root" || checker.password != "secret" {
+		t.Errorf("auth = %q/%q, expected root/secret", checker.username, checker.password)
+	}
+	if !checker.useTLS() {
+		t.Error("expected TLS enabled")
+	}
+	if checker.tls.CAFile != dc.EtcdTLSCAFile || checker.tls.CertFile != dc.EtcdTLSCertFile ||
+		checker.tls.KeyFile != dc.EtcdTLSKeyFile {
+		t.Errorf("unexpected TLS options: %+v", checker.tls)
+	}
+}
+
+func TestChecker_UseTLS_ImpliedByCA(t *testing.T) {
+	if New().useTLS() {
+		t.Error("expected plain connection by default")
+	}
+	if !New(WithTLSCA("ca.pem")).useTLS() {
+		t.Error("expected CA file to imply TLS")
+	}
+}
+
+func TestChecker_Type(t *testing.T) {
+	checker := New()
+	if got := checker.Type(); got != "etcd" {
+		t.Errorf("Type() = %q, expected %q", got, "etcd")
+	}
+}
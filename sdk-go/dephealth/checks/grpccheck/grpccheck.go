@@ -9,26 +9,36 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"maps"
 	"net"
 	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
 	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
 )
 
 var tlsSkipVerifyWarnOnce sync.Once
 
-var _ dephealth.HealthChecker = (*Checker)(nil)
+var (
+	_ dephealth.HealthChecker  = (*Checker)(nil)
+	_ dephealth.EndpointCloser = (*Checker)(nil)
+	_ io.Closer                = (*Checker)(nil)
+)
 
 func init() {
 	dephealth.RegisterCheckerFactory(dephealth.TypeGRPC, NewFromConfig)
@@ -40,12 +50,36 @@ type Option func(*Checker)
 // Checker performs health checks using the gRPC Health Checking Protocol.
 // Each check creates a new connection, calls Health/Check, and closes the connection.
 // The check succeeds if the response status is SERVING.
+//
+// With WithPersistentConnections, one ClientConn is kept per endpoint and
+// closed by CloseEndpoint.
 type Checker struct {
 	serviceName   string
 	tlsEnabled    bool
 	tlsSkipVerify bool
+	tlsConfig     *tls.Config     // base config from WithTLSConfig
+	tls           tlsutil.Options // CA and client certificate files
+	tlsSource     *tlsutil.Source
 	metadata      map[string]string
 	authority     string // overrides :authority pseudo-header (and TLS SNI when TLS)
+
+	// Credential providers called for every check; they override the
+	// authorization metadata.
+	bearerProvider dephealth.CredentialProvider
+	basicUser      string
+	basicProvider  dephealth.CredentialProvider
+
+	persistent  bool
+	idleTimeout time.Duration
+	mu          sync.Mutex
+	conns       map[string]endpointConn // key: host:port, persistent mode only
+}
+
+// endpointConn is a persistent ClientConn and the generation of the TLS
+// files it was created with.
+type endpointConn struct {
+	conn   *grpc.ClientConn
+	tlsGen uint64
 }
 
 // WithServiceName sets the gRPC service name for health checks.
@@ -70,6 +104,32 @@ func WithTLSSkipVerify(skip bool) Option {
 	}
 }
 
+// WithTLSConfig sets the base TLS configuration and enables TLS. The
+// checker applies WithTLSSkipVerify, WithAuthority (SNI) and the files of
+// WithTLSCA and WithTLSClientCert to a copy.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithTLSCA sets the PEM CA bundle used to verify the server certificate
+// and enables TLS. The file is read again when it changes.
+func WithTLSCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tls.CAFile = caFile
+	}
+}
+
+// WithTLSClientCert sets the client certificate and key for mTLS and
+// enables TLS. The files are read again when they change.
+func WithTLSClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tls.CertFile = certFile
+		c.tls.KeyFile = keyFile
+	}
+}
+
 // WithAuthority sets the :authority pseudo-header for gRPC health check calls.
 // When TLS is enabled, also sets TLS SNI (ServerName) to the same value.
 func WithAuthority(authority string) Option {
@@ -102,6 +162,35 @@ func WithBasicAuth(username, password string) Option {
 	}
 }
 
+// WithBearerTokenProvider obtains the Bearer token from p before every
+// call. It takes precedence over WithBearerToken, WithBasicAuth and
+// authorization metadata.
+func WithBearerTokenProvider(p dephealth.CredentialProvider) Option {
+	return func(c *Checker) {
+		c.bearerProvider = p
+	}
+}
+
+// WithBasicAuthProvider sets Basic Auth credentials whose password is
+// obtained from p before every call. It takes precedence over
+// WithBearerToken, WithBasicAuth and authorization metadata.
+func WithBasicAuthProvider(username string, password dephealth.CredentialProvider) Option {
+	return func(c *Checker) {
+		c.basicUser = username
+		c.basicProvider = password
+	}
+}
+
+// WithPersistentConnections keeps one ClientConn per endpoint and reuses it
+// across checks. The connection goes idle after idleTimeout without checks
+// (gRPC default of 30m when zero) and reconnects on the next check.
+func WithPersistentConnections(idleTimeout time.Duration) Option {
+	return func(c *Checker) {
+		c.persistent = true
+		c.idleTimeout = idleTimeout
+	}
+}
+
 // New creates a new gRPC health checker with the given options.
 func New(opts ...Option) *Checker {
 	c := &Checker{
@@ -110,6 +199,10 @@ func New(opts ...Option) *Checker {
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.tlsSource = tlsutil.NewSource(c.tlsConfig, c.tls)
+	if !c.tlsSource.IsZero() {
+		c.tlsEnabled = true
+	}
 	return c
 }
 
@@ -122,9 +215,21 @@ func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
 	if dc.GRPCTLS != nil {
 		opts = append(opts, WithTLS(*dc.GRPCTLS))
 	}
+	if dc.TLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.TLSSkipVerify))
+	}
 	if dc.GRPCTLSSkipVerify != nil {
 		opts = append(opts, WithTLSSkipVerify(*dc.GRPCTLSSkipVerify))
 	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
 	if dc.GRPCAuthority != "" {
 		opts = append(opts, WithAuthority(dc.GRPCAuthority))
 	}
@@ -137,29 +242,88 @@ func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
 	if dc.GRPCBasicUser != "" {
 		opts = append(opts, WithBasicAuth(dc.GRPCBasicUser, dc.GRPCBasicPass))
 	}
+	if dc.GRPCBearerTokenProvider != nil {
+		opts = append(opts, WithBearerTokenProvider(dc.GRPCBearerTokenProvider))
+	}
+	if dc.GRPCBasicPassProvider != nil {
+		opts = append(opts, WithBasicAuthProvider(dc.GRPCBasicUser, dc.GRPCBasicPassProvider))
+	}
+	if dc.GRPCPersistentConnections {
+		opts = append(opts, WithPersistentConnections(dc.GRPCIdleTimeout))
+	}
 	return New(opts...)
 }
 
 // Check performs a gRPC health check against the endpoint.
-// Creates a new connection, sends Health/Check request, and closes.
+// Creates a new connection (or reuses the persistent one), sends
+// Health/Check request, and closes it unless it is persistent.
 // Returns nil if the service status is SERVING.
 func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
+	return c.check(ctx, endpoint)
+}
+
+// check performs the gRPC check.
+func (c *Checker) check(ctx context.Context, endpoint dephealth.Endpoint) error {
 	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
 
-	var dialOpts []grpc.DialOption
+	auth, err := c.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var tlsCfg *tls.Config
+	var tlsGen uint64
 	if c.tlsEnabled {
-		if c.tlsSkipVerify {
-			tlsSkipVerifyWarnOnce.Do(func() {
-				slog.Warn("dephealth: gRPC checker has TLS certificate verification disabled (InsecureSkipVerify=true)")
-			})
-		}
-		tlsCfg := &tls.Config{
-			InsecureSkipVerify: c.tlsSkipVerify, //nolint:gosec // configurable by user
+		var err error
+		if tlsCfg, tlsGen, err = c.clientTLSConfig(); err != nil {
+			return fmt.Errorf("grpc TLS config: %w", err)
 		}
-		// Set TLS SNI when authority is configured.
-		if c.authority != "" {
-			tlsCfg.ServerName = c.authority
+	}
+
+	var conn *grpc.ClientConn
+	if c.persistent {
+		conn, err = c.connFor(addr, tlsCfg, tlsGen)
+	} else {
+		conn, err = c.newConn(addr, tlsCfg)
+		if conn != nil {
+			defer func() { _ = conn.Close() }()
 		}
+	}
+	if err != nil {
+		return fmt.Errorf("grpc new client %s: %w", addr, err)
+	}
+	connect(ctx, conn)
+	return c.invoke(ctx, conn, addr, auth)
+}
+
+// clientTLSConfig returns the TLS config for the next connection, with the
+// current certificate files, and the generation of those files.
+func (c *Checker) clientTLSConfig() (*tls.Config, uint64, error) {
+	if c.tlsSkipVerify {
+		tlsSkipVerifyWarnOnce.Do(func() {
+			slog.Warn("dephealth: gRPC checker has TLS certificate verification disabled (InsecureSkipVerify=true)")
+		})
+	}
+	tlsCfg, gen, err := c.tlsSource.Load()
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.tlsSkipVerify {
+		tlsCfg.InsecureSkipVerify = true //nolint:gosec // configurable by user
+	}
+	// Set TLS SNI when authority is configured.
+	if c.authority != "" {
+		tlsCfg.ServerName = c.authority
+	}
+	return tlsCfg, gen, nil
+}
+
+// newConn creates a ClientConn for addr with the checker's authority
+// settings, using TLS when tlsCfg is not nil. The connection is established
+// lazily.
+func (c *Checker) newConn(addr string, tlsCfg *tls.Config) (*grpc.ClientConn, error) {
+	var dialOpts []grpc.DialOption
+	if tlsCfg != nil {
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
 	} else {
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -169,26 +333,146 @@ func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error
 		dialOpts = append(dialOpts, grpc.WithAuthority(c.authority))
 	}
 
-	conn, err := grpc.NewClient("passthrough:///"+addr, dialOpts...)
+	if c.persistent && c.idleTimeout > 0 {
+		dialOpts = append(dialOpts, grpc.WithIdleTimeout(c.idleTimeout))
+	}
+
+	return grpc.NewClient("passthrough:///"+addr, dialOpts...)
+}
+
+// connFor returns the persistent ClientConn of the endpoint at addr,
+// creating it on first use. After the certificate files have been reloaded
+// the connection is replaced, so that the new certificates are used.
+func (c *Checker) connFor(addr string, tlsCfg *tls.Config, tlsGen uint64) (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ec, ok := c.conns[addr]; ok {
+		if ec.tlsGen == tlsGen {
+			return ec.conn, nil
+		}
+		_ = ec.conn.Close()
+		delete(c.conns, addr)
+	}
+	conn, err := c.newConn(addr, tlsCfg)
 	if err != nil {
-		return fmt.Errorf("grpc new client %s: %w", addr, err)
+		return nil, err
+	}
+	if c.conns == nil {
+		c.conns = make(map[string]endpointConn)
 	}
-	defer func() { _ = conn.Close() }()
+	c.conns[addr] = endpointConn{conn: conn, tlsGen: tlsGen}
+	return conn, nil
+}
 
+// CloseEndpoint closes the persistent connection of the endpoint.
+// The scheduler calls it when the endpoint is removed or the scheduler stops.
+func (c *Checker) CloseEndpoint(endpoint dephealth.Endpoint) {
+	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
+	c.mu.Lock()
+	ec, ok := c.conns[addr]
+	delete(c.conns, addr)
+	c.mu.Unlock()
+	if ok {
+		_ = ec.conn.Close()
+	}
+}
+
+// Close closes the persistent connections of all endpoints. The scheduler
+// calls it once no endpoint uses the checker any more.
+func (c *Checker) Close() error {
+	c.mu.Lock()
+	conns := c.conns
+	c.conns = nil
+	c.mu.Unlock()
+	var errs []error
+	for _, ec := range conns {
+		errs = append(errs, ec.conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// connect waits until conn is ready when it is not connected yet and reports
+// the time taken with ReportConnectTime. A failed connection attempt returns
+// early; the health check call then reports the connection error.
+func connect(ctx context.Context, conn *grpc.ClientConn) {
+	state := conn.GetState()
+	if state == connectivity.Ready {
+		return
+	}
+	if state == connectivity.TransientFailure {
+		// Retry now instead of waiting for the reconnect backoff.
+		conn.ResetConnectBackoff()
+	}
+
+	start := time.Now()
+	conn.Connect()
+	attempted := false
+	for {
+		switch state {
+		case connectivity.Ready:
+			dephealth.ReportConnectTime(ctx, time.Since(start))
+			return
+		case connectivity.Connecting:
+			attempted = true
+		case connectivity.TransientFailure:
+			if attempted {
+				return
+			}
+		case connectivity.Shutdown:
+			return
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return
+		}
+		state = conn.GetState()
+	}
+}
+
+// authorization returns the authorization metadata value obtained from the
+// credential providers, or "" when none is configured.
+func (c *Checker) authorization(ctx context.Context) (string, error) {
+	switch {
+	case c.bearerProvider != nil:
+		token, err := dephealth.ResolveCredential(ctx, c.bearerProvider, "")
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	case c.basicProvider != nil:
+		password, err := dephealth.ResolveCredential(ctx, c.basicProvider, "")
+		if err != nil {
+			return "", err
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.basicUser+":"+password)), nil
+	default:
+		return "", nil
+	}
+}
+
+// invoke calls Health/Check on conn and classifies the result. A non-empty
+// auth replaces the configured authorization metadata.
+func (c *Checker) invoke(ctx context.Context, conn *grpc.ClientConn, addr, auth string) error {
 	// Attach metadata if configured.
 	callCtx := ctx
-	if len(c.metadata) > 0 {
+	if len(c.metadata) > 0 || auth != "" {
 		md := metadata.New(nil)
 		for k, v := range c.metadata {
 			md.Set(k, v)
 		}
+		if auth != "" {
+			md.Set("authorization", auth)
+		}
 		callCtx = metadata.NewOutgoingContext(ctx, md)
 	}
 
+	var p peer.Peer
 	client := healthpb.NewHealthClient(conn)
 	resp, err := client.Check(callCtx, &healthpb.HealthCheckRequest{
 		Service: c.serviceName,
-	})
+	}, grpc.Peer(&p))
+	if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		dephealth.ReportTLSConnectionState(ctx, &info.State)
+	}
 	if err != nil {
 		// Classify UNAUTHENTICATED and PERMISSION_DENIED as auth_error.
 		if s, ok := status.FromError(err); ok {
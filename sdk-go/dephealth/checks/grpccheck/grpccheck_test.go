@@ -2,17 +2,27 @@ package grpccheck
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"net"
+	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/goleak"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/checktest"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlstest"
 )
 
 // testHealthServer implements the gRPC Health service for testing.
@@ -192,6 +202,34 @@ func TestChecker_Check_BasicAuth(t *testing.T) {
 	}
 }
 
+func TestChecker_Check_CredentialProviders(t *testing.T) {
+	addr, authSrv, stop := startTestAuthGRPCServer(t, "Basic YWRtaW46c2VjcmV0")
+	defer stop()
+
+	host, port, _ := net.SplitHostPort(addr)
+	ep := dephealth.Endpoint{Host: host, Port: port}
+
+	provider := dephealth.CredentialFunc(func(context.Context) (string, error) {
+		return "secret", nil
+	})
+	checker := New(WithBearerToken("static"), WithBasicAuthProvider("admin", provider))
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if authSrv.gotAuth != "Basic YWRtaW46c2VjcmV0" {
+		t.Errorf("authorization = %q, expected %q", authSrv.gotAuth, "Basic YWRtaW46c2VjcmV0")
+	}
+
+	failing := dephealth.CredentialFunc(func(context.Context) (string, error) {
+		return "", errors.New("vault unavailable")
+	})
+	err := New(WithBearerTokenProvider(failing)).Check(context.Background(), ep)
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) || ce.Detail != "credential_error" {
+		t.Errorf("expected credential_error, got %v", err)
+	}
+}
+
 func TestChecker_Check_CustomMetadata(t *testing.T) {
 	addr, _, stop := startTestAuthGRPCServer(t, "Bearer custom-meta")
 	defer stop()
@@ -363,3 +401,279 @@ func TestChecker_Check_NotServing_Unhealthy(t *testing.T) {
 		t.Errorf("Detail = %q, expected %q", ce.Detail, "grpc_not_serving")
 	}
 }
+
+// countingListener counts accepted connections.
+type countingListener struct {
+	net.Listener
+	accepted atomic.Int64
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.accepted.Add(1)
+	}
+	return conn, err
+}
+
+func TestChecker_Check_PersistentConnections(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start TCP listener: %v", err)
+	}
+	ln := &countingListener{Listener: inner}
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, &testHealthServer{status: healthpb.HealthCheckResponse_SERVING})
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Stop()
+
+	host, port, _ := net.SplitHostPort(inner.Addr().String())
+	ep := dephealth.Endpoint{Host: host, Port: port}
+
+	checker := New(WithPersistentConnections(time.Minute))
+	for range 3 {
+		if err := checker.Check(context.Background(), ep); err != nil {
+			t.Fatalf("check failed: %v", err)
+		}
+	}
+	if got := ln.accepted.Load(); got != 1 {
+		t.Errorf("accepted connections = %d, expected 1 reused connection", got)
+	}
+
+	checker.CloseEndpoint(ep)
+	checker.mu.Lock()
+	remaining := len(checker.conns)
+	checker.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("conns after CloseEndpoint = %d, expected 0", remaining)
+	}
+
+	// The next check connects again.
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Fatalf("check after CloseEndpoint failed: %v", err)
+	}
+	if got := ln.accepted.Load(); got != 2 {
+		t.Errorf("accepted connections = %d, expected 2", got)
+	}
+	checker.CloseEndpoint(ep)
+}
+
+func TestChecker_Check_PersistentReconnects(t *testing.T) {
+	addr, stop := startTestGRPCServer(t, healthpb.HealthCheckResponse_SERVING)
+	host, port, _ := net.SplitHostPort(addr)
+	ep := dephealth.Endpoint{Host: host, Port: port}
+
+	checker := New(WithPersistentConnections(0))
+	defer checker.CloseEndpoint(ep)
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+
+	stop()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := checker.Check(ctx, ep); err == nil {
+		t.Fatal("expected error after the server stopped, got nil")
+	}
+
+	// Restart on the same address; the persistent connection recovers
+	// without waiting for the reconnect backoff.
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot rebind %s: %v", addr, err)
+	}
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, &testHealthServer{status: healthpb.HealthCheckResponse_SERVING})
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Stop()
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel2()
+	if err := checker.Check(ctx2, ep); err != nil {
+		t.Errorf("expected recovery after restart, got: %v", err)
+	}
+}
+
+func TestChecker_Close(t *testing.T) {
+	addr, stop := startTestGRPCServer(t, healthpb.HealthCheckResponse_SERVING)
+	defer stop()
+	host, port, _ := net.SplitHostPort(addr)
+
+	checker := New(WithPersistentConnections(time.Minute))
+	for _, ep := range []dephealth.Endpoint{{Host: host, Port: port}, {Host: "localhost", Port: port}} {
+		if err := checker.Check(context.Background(), ep); err != nil {
+			t.Fatalf("check failed: %v", err)
+		}
+	}
+	if err := checker.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	checker.mu.Lock()
+	remaining := len(checker.conns)
+	checker.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("conns after Close = %d, expected 0", remaining)
+	}
+}
+
+func TestChecker_NoLeakAfterSchedulerStop(t *testing.T) {
+	addr, stop := startTestGRPCServer(t, healthpb.HealthCheckResponse_SERVING)
+	ignore := goleak.IgnoreCurrent()
+	host, port, _ := net.SplitHostPort(addr)
+
+	metrics, err := dephealth.NewMetricsExporter("test-app", "test-group",
+		dephealth.WithMetricsRegisterer(prometheus.NewRegistry()))
+	if err != nil {
+		t.Fatalf("failed to create MetricsExporter: %v", err)
+	}
+	sched := dephealth.NewScheduler(metrics, dephealth.WithGlobalCheckConfig(dephealth.CheckConfig{
+		Interval:         50 * time.Millisecond,
+		Timeout:          time.Second,
+		FailureThreshold: dephealth.DefaultFailureThreshold,
+		SuccessThreshold: dephealth.DefaultSuccessThreshold,
+	}))
+	if err := sched.Start(context.Background()); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+
+	checker := New(WithPersistentConnections(time.Minute))
+	ep := dephealth.Endpoint{Host: host, Port: port}
+	_ = sched.AddEndpoint("grpc-svc", dephealth.TypeGRPC, false, ep, checker)
+	deadline := time.Now().Add(2 * time.Second)
+	for !sched.Health()["grpc-svc:"+host+":"+port] && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !sched.Health()["grpc-svc:"+host+":"+port] {
+		t.Fatal("expected the endpoint to become healthy")
+	}
+
+	sched.Stop()
+	stop()
+	goleak.VerifyNone(t, ignore)
+}
+
+func TestNewFromConfig_PersistentConnections(t *testing.T) {
+	checker := NewFromConfig(&dephealth.DependencyConfig{
+		GRPCPersistentConnections: true,
+		GRPCIdleTimeout:           5 * time.Minute,
+	}).(*Checker)
+	if !checker.persistent || checker.idleTimeout != 5*time.Minute {
+		t.Errorf("persistent = %v, idleTimeout = %v", checker.persistent, checker.idleTimeout)
+	}
+}
+
+// startMTLSGRPCServer starts a SERVING gRPC server that requires a client
+// certificate issued by ca.
+func startMTLSGRPCServer(t *testing.T, ca *tlstest.CA) dephealth.Endpoint {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start TCP listener: %v", err)
+	}
+	leaf := ca.Issue(t, time.Now().Add(time.Hour), "127.0.0.1")
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{leaf.TLSCertificate(t)},
+		ClientCAs:    ca.Pool(),
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	})))
+	healthpb.RegisterHealthServer(srv, &testHealthServer{status: healthpb.HealthCheckResponse_SERVING})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	return dephealth.Endpoint{Host: host, Port: port}
+}
+
+func TestChecker_Check_MTLSFiles(t *testing.T) {
+	ca := tlstest.NewCA(t)
+	ep := startMTLSGRPCServer(t, ca)
+	dir := t.TempDir()
+	client := ca.Issue(t, time.Now().Add(time.Hour), "client")
+
+	checker := NewFromConfig(&dephealth.DependencyConfig{
+		TLSCAFile:   tlstest.WriteFile(t, dir, "ca.pem", ca.PEM),
+		TLSCertFile: tlstest.WriteFile(t, dir, "tls.crt", client.CertPEM),
+		TLSKeyFile:  tlstest.WriteFile(t, dir, "tls.key", client.KeyPEM),
+	}).(*Checker)
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success with the client certificate, got: %v", err)
+	}
+
+	base := New(WithTLSConfig(&tls.Config{
+		RootCAs:      ca.Pool(),
+		Certificates: []tls.Certificate{client.TLSCertificate(t)},
+		MinVersion:   tls.VersionTLS12,
+	}))
+	if err := base.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success with the base TLS config, got: %v", err)
+	}
+}
+
+func TestChecker_Check_ReportsTLSCertExpiry(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start TCP listener: %v", err)
+	}
+	leaf := tlstest.NewCA(t).Issue(t, time.Now().Add(48*time.Hour).Truncate(time.Second), "127.0.0.1")
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{leaf.TLSCertificate(t)},
+		MinVersion:   tls.VersionTLS12,
+	})))
+	healthpb.RegisterHealthServer(srv, &testHealthServer{status: healthpb.HealthCheckResponse_SERVING})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Stop()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	checker := New(WithTLS(true), WithTLSSkipVerify(true))
+	got := checktest.CertExpiry(t, checker, dephealth.TypeGRPC, dephealth.Endpoint{Host: host, Port: port})
+	if !got.Equal(leaf.Cert.NotAfter) {
+		t.Errorf("TLSCertExpiry = %v, expected %v", got, leaf.Cert.NotAfter)
+	}
+}
+
+func TestChecker_Check_ReloadsRotatedClientCert(t *testing.T) {
+	ca := tlstest.NewCA(t)
+	ep := startMTLSGRPCServer(t, ca)
+	dir := t.TempDir()
+
+	untrusted := tlstest.NewCA(t).Issue(t, time.Now().Add(time.Hour), "client")
+	certFile := tlstest.WriteFile(t, dir, "tls.crt", untrusted.CertPEM)
+	keyFile := tlstest.WriteFile(t, dir, "tls.key", untrusted.KeyPEM)
+
+	checker := New(WithPersistentConnections(time.Minute),
+		WithTLSCA(tlstest.WriteFile(t, dir, "ca.pem", ca.PEM)),
+		WithTLSClientCert(certFile, keyFile))
+	defer func() { _ = checker.Close() }()
+	if err := checker.Check(context.Background(), ep); err == nil {
+		t.Fatal("expected failure with an untrusted client certificate")
+	}
+
+	rotated := ca.Issue(t, time.Now().Add(time.Hour), "client")
+	tlstest.WriteFile(t, dir, "tls.crt", rotated.CertPEM)
+	tlstest.WriteFile(t, dir, "tls.key", rotated.KeyPEM)
+	future := time.Now().Add(time.Minute)
+	for _, f := range []string{certFile, keyFile} {
+		if err := os.Chtimes(f, future, future); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success after the rotation, got: %v", err)
+	}
+}
+
+// echoAuthHealthServer rejects every request with a message that echoes the
+// authorization metadata.
+type echoAuthHealthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (echoAuthHealthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return nil, status.Errorf(codes.PermissionDenied, "rejected authorization: %s", strings.Join(md.Get("authorization"), ","))
+}
@@ -0,0 +1,278 @@
+package httpcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+)
+
+// ResponseFormat selects how the health response body of a dependency is
+// interpreted.
+type ResponseFormat string
+
+const (
+	// FormatAuto detects the format from the Content-Type and the body.
+	// Responses in an unknown format are judged by the status code only.
+	FormatAuto ResponseFormat = "auto"
+	// FormatSpring parses Spring Boot Actuator health responses:
+	// {"status":"UP","components":{"db":{"status":"UP"}}}.
+	FormatSpring ResponseFormat = "spring"
+	// FormatHealthJSON parses IETF application/health+json responses:
+	// {"status":"pass","checks":{"db:connections":[{"status":"pass"}]}}.
+	FormatHealthJSON ResponseFormat = "health+json"
+	// FormatDephealth parses the HealthDetails JSON of another service
+	// instrumented with dephealth.
+	FormatDephealth ResponseFormat = "dephealth"
+)
+
+// Normalized overall statuses of a parsed health response.
+const (
+	healthUp           = "UP"
+	healthDown         = "DOWN"
+	healthOutOfService = "OUT_OF_SERVICE"
+	healthWarn         = "WARN"
+	healthUnknown      = "UNKNOWN"
+)
+
+// healthResponse is a health response body in one of the known formats.
+type healthResponse struct {
+	status     string // one of the health* constants
+	components map[string]dephealth.ComponentStatus
+
+	// dependencies holds the endpoints of a dephealth response, including
+	// the transitive edges they carry; nil for other formats.
+	dependencies map[string]dephealth.EndpointStatus
+}
+
+// parseHealthResponse parses data in the given format. With FormatAuto the
+// format is detected from contentType and the body; ok is false when data
+// is not a health response in the format.
+func parseHealthResponse(format ResponseFormat, contentType string, data []byte) (healthResponse, bool) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return healthResponse{}, false
+	}
+	if format == FormatAuto {
+		format = detectFormat(contentType, doc)
+	}
+	switch format {
+	case FormatSpring:
+		return parseSpring(data)
+	case FormatHealthJSON:
+		return parseHealthJSON(data)
+	case FormatDephealth:
+		return parseDephealth(doc)
+	default:
+		return healthResponse{}, false
+	}
+}
+
+// detectFormat guesses the format of a decoded JSON object.
+func detectFormat(contentType string, doc map[string]json.RawMessage) ResponseFormat {
+	if strings.HasPrefix(strings.ToLower(contentType), "application/health+json") {
+		return FormatHealthJSON
+	}
+	var status string
+	if raw, ok := doc["status"]; ok && json.Unmarshal(raw, &status) == nil {
+		switch strings.ToLower(status) {
+		case "pass", "fail", "warn":
+			return FormatHealthJSON
+		}
+		return FormatSpring
+	}
+	if len(doc) == 0 {
+		return ""
+	}
+	for _, raw := range doc {
+		var es struct {
+			Healthy *bool  `json:"healthy"`
+			Status  string `json:"status"`
+		}
+		if json.Unmarshal(raw, &es) != nil || es.Status == "" {
+			return ""
+		}
+	}
+	return FormatDephealth
+}
+
+// springComponent is a Spring Boot Actuator health component. Boot 2.2+
+// nests sub-components under "components", older versions under "details".
+type springComponent struct {
+	Status     string                     `json:"status"`
+	Components map[string]json.RawMessage `json:"components"`
+	Details    map[string]json.RawMessage `json:"details"`
+}
+
+func parseSpring(data []byte) (healthResponse, bool) {
+	var root springComponent
+	if json.Unmarshal(data, &root) != nil || root.Status == "" {
+		return healthResponse{}, false
+	}
+	resp := healthResponse{status: normalizeStatus(root.Status), components: map[string]dephealth.ComponentStatus{}}
+	addSpringComponents(resp.components, "", root)
+	return resp, true
+}
+
+// addSpringComponents flattens nested components into dst, joining names
+// with "/".
+func addSpringComponents(dst map[string]dephealth.ComponentStatus, prefix string, c springComponent) {
+	children := c.Components
+	if children == nil {
+		children = c.Details
+	}
+	for name, raw := range children {
+		var child springComponent
+		if json.Unmarshal(raw, &child) != nil || child.Status == "" {
+			continue // a plain detail value, not a component
+		}
+		status := normalizeStatus(child.Status)
+		dst[prefix+name] = dephealth.ComponentStatus{
+			Status:  child.Status,
+			Healthy: status == healthUp || status == healthWarn,
+			Detail:  springError(child.Details),
+		}
+		addSpringComponents(dst, prefix+name+"/", child)
+	}
+}
+
+// springError returns the "error" entry of component details, if any.
+func springError(details map[string]json.RawMessage) string {
+	var msg string
+	if raw, ok := details["error"]; ok && json.Unmarshal(raw, &msg) == nil {
+		return msg
+	}
+	return ""
+}
+
+// healthJSONCheck is a check entry of an application/health+json response.
+type healthJSONCheck struct {
+	ComponentID string `json:"componentId"`
+	Status      string `json:"status"`
+	Output      string `json:"output"`
+}
+
+func parseHealthJSON(data []byte) (healthResponse, bool) {
+	var root struct {
+		Status string                       `json:"status"`
+		Checks map[string][]healthJSONCheck `json:"checks"`
+	}
+	if json.Unmarshal(data, &root) != nil || root.Status == "" {
+		return healthResponse{}, false
+	}
+	resp := healthResponse{status: normalizeStatus(root.Status), components: map[string]dephealth.ComponentStatus{}}
+	for key, entries := range root.Checks {
+		for i, e := range entries {
+			name := key
+			if len(entries) > 1 {
+				id := e.ComponentID
+				if id == "" {
+					id = strconv.Itoa(i)
+				}
+				name = key + "/" + id
+			}
+			st := normalizeStatus(e.Status)
+			resp.components[name] = dephealth.ComponentStatus{
+				Status:  e.Status,
+				Healthy: st == healthUp || st == healthWarn,
+				Detail:  e.Output,
+			}
+		}
+	}
+	return resp, true
+}
+
+// parseDephealth parses a HealthDetails map. The response is DOWN when a
+// critical endpoint is unhealthy and WARN when only non-critical ones are.
+func parseDephealth(doc map[string]json.RawMessage) (healthResponse, bool) {
+	if len(doc) == 0 {
+		return healthResponse{}, false
+	}
+	resp := healthResponse{
+		status:       healthUp,
+		components:   make(map[string]dephealth.ComponentStatus, len(doc)),
+		dependencies: make(map[string]dephealth.EndpointStatus, len(doc)),
+	}
+	for key, raw := range doc {
+		var es dephealth.EndpointStatus
+		if json.Unmarshal(raw, &es) != nil || es.Status == "" {
+			return healthResponse{}, false
+		}
+		resp.dependencies[key] = es
+		healthy := es.Healthy != nil && *es.Healthy
+		resp.components[key] = dephealth.ComponentStatus{
+			Status:  string(es.Status),
+			Healthy: healthy,
+			Detail:  es.Detail,
+		}
+		if es.Healthy == nil || healthy {
+			continue // not checked yet, or healthy
+		}
+		if es.Critical {
+			resp.status = healthDown
+		} else if resp.status == healthUp {
+			resp.status = healthWarn
+		}
+	}
+	return resp, true
+}
+
+// limitTopology returns a copy of deps whose nested Dependencies are kept
+// down to depth levels. An endpoint whose host:port is already in path is
+// kept without its own dependencies, which breaks cycles between services.
+func limitTopology(deps map[string]dephealth.EndpointStatus, depth int, path map[string]bool) map[string]dephealth.EndpointStatus {
+	out := make(map[string]dephealth.EndpointStatus, len(deps))
+	for key, es := range deps {
+		addr := net.JoinHostPort(es.Host, es.Port)
+		if depth <= 1 || path[addr] || len(es.Dependencies) == 0 {
+			es.Dependencies = nil
+		} else {
+			path[addr] = true
+			es.Dependencies = limitTopology(es.Dependencies, depth-1, path)
+			delete(path, addr)
+		}
+		out[key] = es
+	}
+	return out
+}
+
+// normalizeStatus maps the status values of the supported formats to the
+// health* constants.
+func normalizeStatus(status string) string {
+	switch strings.ToUpper(status) {
+	case "UP", "PASS", "OK":
+		return healthUp
+	case "DOWN", "FAIL", "ERROR":
+		return healthDown
+	case "OUT_OF_SERVICE":
+		return healthOutOfService
+	case "WARN", "WARNING":
+		return healthWarn
+	default:
+		return healthUnknown
+	}
+}
+
+// healthError returns the classified error for a non-healthy overall status,
+// or nil for UP and WARN.
+func healthError(status, url string) error {
+	var detail string
+	switch status {
+	case healthUp, healthWarn:
+		return nil
+	case healthDown:
+		detail = "health_down"
+	case healthOutOfService:
+		detail = "health_out_of_service"
+	default:
+		detail = "health_unknown"
+	}
+	return &dephealth.ClassifiedCheckError{
+		Category: dephealth.StatusUnhealthy,
+		Detail:   detail,
+		Cause:    fmt.Errorf("health status %s from %s", status, url),
+	}
+}
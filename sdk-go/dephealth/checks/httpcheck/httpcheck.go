@@ -6,22 +6,48 @@
 package httpcheck
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"maps"
 	"net"
 	"net/http"
+	"net/http/httptrace"
+	"regexp"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/jsonpath"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
 )
 
+// defaultIdleTimeout closes persistent connections that stay unused longer
+// than this when WithPersistentConnections is given no timeout.
+const defaultIdleTimeout = 90 * time.Second
+
+// defaultMaxBodySize limits how much of the response body is read for body
+// assertions when WithMaxBodySize is not set.
+const defaultMaxBodySize = 1 << 20
+
+// maxDrainBytes limits how much of an unread response body is drained so the
+// connection can be reused.
+const maxDrainBytes = 64 << 10
+
 var tlsSkipVerifyWarnOnce sync.Once
 
-var _ dephealth.HealthChecker = (*Checker)(nil)
+var (
+	_ dephealth.HealthChecker  = (*Checker)(nil)
+	_ dephealth.EndpointCloser = (*Checker)(nil)
+	_ io.Closer                = (*Checker)(nil)
+)
 
 func init() {
 	dephealth.RegisterCheckerFactory(dephealth.TypeHTTP, NewFromConfig)
@@ -30,15 +56,62 @@ func init() {
 // Option configures the Checker.
 type Option func(*Checker)
 
-// Checker performs health checks via HTTP GET requests.
-// The check succeeds if the final response status code is 2xx.
-// Redirects (3xx) are followed automatically.
+// Checker performs health checks via HTTP requests (GET by default).
+// The check succeeds if the final response status code is 2xx (or one of
+// the codes set with WithExpectedStatus) and the body matches the configured
+// assertions. Redirects (3xx) are followed automatically, up to
+// WithMaxRedirects when set.
+//
+// By default each check uses a new transport whose connections are closed
+// afterwards. With WithPersistentConnections, one transport is kept per
+// endpoint and released by CloseEndpoint.
 type Checker struct {
 	healthPath    string
 	tlsEnabled    bool
 	tlsSkipVerify bool
+	tlsConfig     *tls.Config     // base config from WithTLSConfig
+	tls           tlsutil.Options // CA and client certificate files
+	tlsSource     *tlsutil.Source
 	headers       map[string]string
 	hostHeader    string // overrides Host header (and TLS SNI when HTTPS)
+
+	// Credential providers called for every request; they override the
+	// Authorization header.
+	bearerProvider dephealth.CredentialProvider
+	basicUser      string
+	basicProvider  dephealth.CredentialProvider
+
+	method         string
+	body           []byte
+	contentType    string
+	expectedStatus []int // empty = any 2xx
+	maxRedirects   int   // negative = net/http default (10)
+
+	maxBodySize  int64
+	bodyContains string
+	bodyRegex    string
+	jsonPath     string
+	jsonValue    string
+
+	// Compiled in New; configErr is returned by Check.
+	bodyRe    *regexp.Regexp
+	path      jsonpath.Path
+	configErr error
+
+	format        ResponseFormat // empty = body not interpreted
+	topologyDepth int            // 0 = transitive edges not reported
+
+	persistent  bool
+	idleTimeout time.Duration
+	mu          sync.Mutex
+	transports  map[string]endpointTransport // key: host:port, persistent mode only
+}
+
+// endpointTransport is a persistent transport and the generation of the
+// TLS files it was created with.
+type endpointTransport struct {
+	transport *http.Transport
+	tlsGen    uint64
 }
 
 // WithHealthPath sets the HTTP path for health checks (default "/health").
@@ -62,6 +135,32 @@ func WithTLSSkipVerify(skip bool) Option {
 	}
 }
 
+// WithTLSConfig sets the base TLS configuration and enables HTTPS. The
+// checker applies WithTLSSkipVerify, WithHostHeader (SNI) and the files of
+// WithTLSCA and WithTLSClientCert to a copy.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithTLSCA sets the PEM CA bundle used to verify the server certificate
+// and enables HTTPS. The file is read again when it changes.
+func WithTLSCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tls.CAFile = caFile
+	}
+}
+
+// WithTLSClientCert sets the client certificate and key for mTLS and
+// enables HTTPS. The files are read again when they change.
+func WithTLSClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tls.CertFile = certFile
+		c.tls.KeyFile = keyFile
+	}
+}
+
 // WithHostHeader sets the HTTP Host header for health check requests.
 // When TLS is enabled, also sets TLS SNI (ServerName) to the same value.
 func WithHostHeader(host string) Option {
@@ -94,15 +193,170 @@ func WithBasicAuth(username, password string) Option {
 	}
 }
 
+// WithBearerTokenProvider obtains the Bearer token from p before every
+// request. It takes precedence over WithBearerToken, WithBasicAuth and an
+// Authorization header.
+func WithBearerTokenProvider(p dephealth.CredentialProvider) Option {
+	return func(c *Checker) {
+		c.bearerProvider = p
+	}
+}
+
+// WithBasicAuthProvider sets Basic Auth credentials whose password is
+// obtained from p before every request. It takes precedence over
+// WithBearerToken, WithBasicAuth and an Authorization header.
+func WithBasicAuthProvider(username string, password dephealth.CredentialProvider) Option {
+	return func(c *Checker) {
+		c.basicUser = username
+		c.basicProvider = password
+	}
+}
+
+// WithMethod sets the HTTP method of health check requests (default GET).
+func WithMethod(method string) Option {
+	return func(c *Checker) {
+		c.method = method
+	}
+}
+
+// WithRequestBody sets the request body and its Content-Type, typically
+// together with WithMethod("POST").
+func WithRequestBody(contentType string, body []byte) Option {
+	return func(c *Checker) {
+		c.contentType = contentType
+		c.body = body
+	}
+}
+
+// WithExpectedStatus sets the accepted response status codes, replacing the
+// default of any 2xx code.
+func WithExpectedStatus(codes ...int) Option {
+	return func(c *Checker) {
+		c.expectedStatus = codes
+	}
+}
+
+// WithMaxRedirects limits the number of redirects followed. With 0 the
+// first response is final, so a 3xx fails unless accepted by
+// WithExpectedStatus. By default up to 10 redirects are followed.
+func WithMaxRedirects(n int) Option {
+	return func(c *Checker) {
+		c.maxRedirects = n
+	}
+}
+
+// WithMaxBodySize limits how many bytes of the response body are read for
+// body assertions (default 1 MiB when zero). A larger body fails with detail
+// body_mismatch.
+func WithMaxBodySize(n int64) Option {
+	return func(c *Checker) {
+		c.maxBodySize = n
+	}
+}
+
+// WithBodyContains requires the response body to contain substr.
+func WithBodyContains(substr string) Option {
+	return func(c *Checker) {
+		c.bodyContains = substr
+	}
+}
+
+// WithBodyRegex requires the response body to match the regular expression
+// pattern (RE2 syntax). An invalid pattern makes every check fail.
+func WithBodyRegex(pattern string) Option {
+	return func(c *Checker) {
+		c.bodyRegex = pattern
+	}
+}
+
+// WithBodyJSONPath requires the JSON response body to have expected at path,
+// e.g. WithBodyJSONPath("$.status", "UP"). Strings are compared as-is and
+// other values in compact JSON form ("true", "42", "null"). The path
+// supports member names and array indexes ($.checks[0].ok).
+func WithBodyJSONPath(path, expected string) Option {
+	return func(c *Checker) {
+		c.jsonPath = path
+		c.jsonValue = expected
+	}
+}
+
+// WithResponseFormat interprets the response body as a health response in
+// the given format (Spring Boot Actuator, application/health+json or
+// dephealth HealthDetails). Its overall status decides the result even
+// when the status code is rejected: DOWN fails with detail health_down,
+// OUT_OF_SERVICE with health_out_of_service, and WARN succeeds with detail
+// health_warn. Sub-components are reported with dephealth.ReportComponents.
+func WithResponseFormat(format ResponseFormat) Option {
+	return func(c *Checker) {
+		c.format = format
+	}
+}
+
+// WithTopologyDepth reports the endpoints of a downstream dephealth service
+// as transitive edges with dephealth.ReportDependencies, following the
+// edges that service itself reports up to depth levels (1 = its direct
+// dependencies only). The response format defaults to FormatDephealth.
+// An endpoint that leads back to one already on the path, including the
+// checked endpoint, is not expanded.
+func WithTopologyDepth(depth int) Option {
+	return func(c *Checker) {
+		c.topologyDepth = depth
+	}
+}
+
+// WithPersistentConnections keeps one transport per endpoint and reuses its
+// connections across checks instead of connecting for every check. Idle
+// connections are closed after idleTimeout (default 90s when zero).
+func WithPersistentConnections(idleTimeout time.Duration) Option {
+	return func(c *Checker) {
+		c.persistent = true
+		c.idleTimeout = idleTimeout
+	}
+}
+
 // New creates a new HTTP health checker with the given options.
 func New(opts ...Option) *Checker {
 	c := &Checker{
-		healthPath: "/health",
-		headers:    make(map[string]string),
+		healthPath:   "/health",
+		headers:      make(map[string]string),
+		method:       http.MethodGet,
+		maxRedirects: -1,
+		maxBodySize:  defaultMaxBodySize,
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.maxBodySize <= 0 {
+		c.maxBodySize = defaultMaxBodySize
+	}
+	c.tlsSource = tlsutil.NewSource(c.tlsConfig, c.tls)
+	if !c.tlsSource.IsZero() {
+		c.tlsEnabled = true
+	}
+	if c.bodyRegex != "" {
+		c.bodyRe, c.configErr = regexp.Compile(c.bodyRegex)
+	}
+	if c.jsonPath != "" && c.configErr == nil {
+		c.path, c.configErr = jsonpath.Parse(c.jsonPath)
+	}
+	if c.topologyDepth > 0 && c.format == "" {
+		c.format = FormatDephealth
+	}
+	switch c.format {
+	case "", FormatAuto, FormatSpring, FormatHealthJSON, FormatDephealth:
+	default:
+		if c.configErr == nil {
+			c.configErr = fmt.Errorf("unknown response format %q", c.format)
+		}
+	}
+	if c.configErr == nil {
+		switch {
+		case c.topologyDepth < 0:
+			c.configErr = fmt.Errorf("negative topology depth %d", c.topologyDepth)
+		case c.topologyDepth > 0 && c.format != FormatDephealth && c.format != FormatAuto:
+			c.configErr = fmt.Errorf("topology depth requires the %s response format, got %q", FormatDephealth, c.format)
+		}
+	}
 	return c
 }
 
@@ -115,9 +369,21 @@ func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
 	if dc.HTTPTLS != nil {
 		opts = append(opts, WithTLSEnabled(*dc.HTTPTLS))
 	}
+	if dc.TLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.TLSSkipVerify))
+	}
 	if dc.HTTPTLSSkipVerify != nil {
 		opts = append(opts, WithTLSSkipVerify(*dc.HTTPTLSSkipVerify))
 	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
 	if dc.HTTPHostHeader != "" {
 		opts = append(opts, WithHostHeader(dc.HTTPHostHeader))
 	}
@@ -130,12 +396,83 @@ func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
 	if dc.HTTPBasicUser != "" {
 		opts = append(opts, WithBasicAuth(dc.HTTPBasicUser, dc.HTTPBasicPass))
 	}
+	if dc.HTTPBearerTokenProvider != nil {
+		opts = append(opts, WithBearerTokenProvider(dc.HTTPBearerTokenProvider))
+	}
+	if dc.HTTPBasicPassProvider != nil {
+		opts = append(opts, WithBasicAuthProvider(dc.HTTPBasicUser, dc.HTTPBasicPassProvider))
+	}
+	if dc.HTTPPersistentConnections {
+		opts = append(opts, WithPersistentConnections(dc.HTTPIdleTimeout))
+	}
+	if dc.HTTPMethod != "" {
+		opts = append(opts, WithMethod(dc.HTTPMethod))
+	}
+	if dc.HTTPBody != nil {
+		opts = append(opts, WithRequestBody(dc.HTTPBodyContentType, dc.HTTPBody))
+	}
+	if len(dc.HTTPExpectedStatus) > 0 {
+		opts = append(opts, WithExpectedStatus(dc.HTTPExpectedStatus...))
+	}
+	if dc.HTTPMaxRedirects != nil {
+		opts = append(opts, WithMaxRedirects(*dc.HTTPMaxRedirects))
+	}
+	if dc.HTTPMaxBodySize > 0 {
+		opts = append(opts, WithMaxBodySize(dc.HTTPMaxBodySize))
+	}
+	if dc.HTTPBodyContains != "" {
+		opts = append(opts, WithBodyContains(dc.HTTPBodyContains))
+	}
+	if dc.HTTPBodyRegex != "" {
+		opts = append(opts, WithBodyRegex(dc.HTTPBodyRegex))
+	}
+	if dc.HTTPBodyJSONPath != "" {
+		opts = append(opts, WithBodyJSONPath(dc.HTTPBodyJSONPath, dc.HTTPBodyJSONValue))
+	}
+	if dc.HTTPResponseFormat != "" {
+		opts = append(opts, WithResponseFormat(ResponseFormat(dc.HTTPResponseFormat)))
+	}
+	if dc.HTTPTopologyDepth > 0 {
+		opts = append(opts, WithTopologyDepth(dc.HTTPTopologyDepth))
+	}
 	return New(opts...)
 }
 
-// Check sends an HTTP GET request to the endpoint's health path.
-// Returns nil if the response status code is 2xx, or an error otherwise.
+// authorization returns the Authorization header value obtained from the
+// credential providers, or "" when none is configured.
+func (c *Checker) authorization(ctx context.Context) (string, error) {
+	switch {
+	case c.bearerProvider != nil:
+		token, err := dephealth.ResolveCredential(ctx, c.bearerProvider, "")
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	case c.basicProvider != nil:
+		password, err := dephealth.ResolveCredential(ctx, c.basicProvider, "")
+		if err != nil {
+			return "", err
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.basicUser+":"+password)), nil
+	default:
+		return "", nil
+	}
+}
+
+// Check sends an HTTP request to the endpoint's health path.
+// Returns nil if the response status code is accepted and the body matches
+// the configured assertions, or an error otherwise.
+// The time spent on a new connection is reported with ReportConnectTime.
 func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
+	return c.check(ctx, endpoint)
+}
+
+// check performs the HTTP check.
+func (c *Checker) check(ctx context.Context, endpoint dephealth.Endpoint) error {
+	if c.configErr != nil {
+		return fmt.Errorf("http checker config: %w", c.configErr)
+	}
+
 	scheme := "http"
 	if c.tlsEnabled {
 		scheme = "https"
@@ -144,16 +481,30 @@ func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error
 	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
 	url := fmt.Sprintf("%s://%s%s", scheme, addr, c.healthPath)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	var body io.Reader
+	if c.body != nil {
+		body = bytes.NewReader(c.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, c.method, url, body)
 	if err != nil {
 		return fmt.Errorf("http create request: %w", err)
 	}
 	req.Header.Set("User-Agent", "dephealth/"+dephealth.Version)
+	if c.contentType != "" {
+		req.Header.Set("Content-Type", c.contentType)
+	}
 
 	// Apply custom headers after User-Agent so they can override it.
 	for k, v := range c.headers {
 		req.Header.Set(k, v)
 	}
+	auth, err := c.authorization(ctx)
+	if err != nil {
+		return err
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
 
 	// Override Host header if configured (used for ingress/gateway routing by IP).
 	// req.Host overrides the Host header in the actual HTTP request.
@@ -167,44 +518,252 @@ func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error
 		})
 	}
 
-	tlsCfg := &tls.Config{
-		InsecureSkipVerify: c.tlsSkipVerify, //nolint:gosec // configurable by user
+	tlsCfg, tlsGen, err := c.clientTLSConfig()
+	if err != nil {
+		return fmt.Errorf("http TLS config: %w", err)
 	}
-	// Set TLS SNI when hostHeader is configured and TLS is enabled.
-	if c.tlsEnabled && c.hostHeader != "" {
-		tlsCfg.ServerName = c.hostHeader
+	var transport *http.Transport
+	if c.persistent {
+		transport = c.transportFor(addr, tlsCfg, tlsGen)
+	} else {
+		transport = c.newTransport(tlsCfg)
+		defer transport.CloseIdleConnections()
 	}
 
-	transport := &http.Transport{
-		TLSClientConfig: tlsCfg,
-	}
+	// Report the dial and handshake time of new connections.
+	var connStart time.Time
+	req = req.WithContext(httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GetConn: func(string) { connStart = time.Now() },
+		GotConn: func(info httptrace.GotConnInfo) {
+			if !info.Reused && !connStart.IsZero() {
+				dephealth.ReportConnectTime(ctx, time.Since(connStart))
+			}
+		},
+	}))
 
 	client := &http.Client{
 		Transport: transport,
 	}
+	if c.maxRedirects >= 0 {
+		client.CheckRedirect = func(_ *http.Request, via []*http.Request) error {
+			if len(via) > c.maxRedirects {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		}
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("http request %s: %w", url, err)
 	}
-	_ = resp.Body.Close()
+	defer func() {
+		// Drain the body so the connection can be reused.
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+		_ = resp.Body.Close()
+	}()
+	dephealth.ReportTLSConnectionState(ctx, resp.TLS)
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		// HTTP 401/403 → auth_error.
-		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
-			return &dephealth.ClassifiedCheckError{
-				Category: dephealth.StatusAuthError,
-				Detail:   "auth_error",
-				Cause:    fmt.Errorf("http status %d from %s", resp.StatusCode, url),
-			}
+	// With a response format the body of a rejected status (e.g. 503 from
+	// Spring Boot) still explains the failure, so only auth errors are final.
+	code := resp.StatusCode
+	accepted := c.statusAccepted(code)
+	if !accepted && (c.format == "" || code == http.StatusUnauthorized || code == http.StatusForbidden) {
+		return statusError(code, url)
+	}
+
+	if c.format == "" && !c.hasBodyAssertions() {
+		return nil
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, c.maxBodySize+1))
+	if err != nil {
+		return fmt.Errorf("http read body %s: %w", url, err)
+	}
+	if int64(len(data)) > c.maxBodySize {
+		if !accepted {
+			return statusError(code, url)
+		}
+		return bodyMismatch("response body from %s exceeds %d bytes", url, c.maxBodySize)
+	}
+
+	if c.format != "" {
+		if err := c.checkHealthResponse(ctx, resp, data, addr, url); err != nil {
+			return err
+		}
+		if !accepted {
+			return statusError(code, url)
 		}
+	}
+	if !c.hasBodyAssertions() {
+		return nil
+	}
+	return c.checkBody(data, url)
+}
+
+// statusError classifies a rejected response status: 401/403 as auth_error,
+// anything else as unhealthy with detail http_<code>.
+func statusError(code int, url string) error {
+	if code == http.StatusUnauthorized || code == http.StatusForbidden {
 		return &dephealth.ClassifiedCheckError{
-			Category: dephealth.StatusUnhealthy,
-			Detail:   fmt.Sprintf("http_%d", resp.StatusCode),
-			Cause:    fmt.Errorf("http status %d from %s", resp.StatusCode, url),
+			Category: dephealth.StatusAuthError,
+			Detail:   "auth_error",
+			Cause:    fmt.Errorf("http status %d from %s", code, url),
 		}
 	}
+	return &dephealth.ClassifiedCheckError{
+		Category: dephealth.StatusUnhealthy,
+		Detail:   fmt.Sprintf("http_%d", code),
+		Cause:    fmt.Errorf("http status %d from %s", code, url),
+	}
+}
 
+// checkHealthResponse parses the body in the configured response format,
+// reports its sub-components (and transitive edges, with a topology depth)
+// and maps its overall status: DOWN, OUT_OF_SERVICE and unknown values fail
+// the check, WARN succeeds with detail health_warn. A body in another format
+// fails with body_mismatch unless the format is auto-detected.
+func (c *Checker) checkHealthResponse(ctx context.Context, resp *http.Response, data []byte, addr, url string) error {
+	hr, ok := parseHealthResponse(c.format, resp.Header.Get("Content-Type"), data)
+	if !ok {
+		if c.format == FormatAuto || !c.statusAccepted(resp.StatusCode) {
+			return nil // judged by the status code
+		}
+		return bodyMismatch("response body from %s is not a %s health response", url, c.format)
+	}
+	dephealth.ReportComponents(ctx, hr.components)
+	if c.topologyDepth > 0 && hr.dependencies != nil {
+		dephealth.ReportDependencies(ctx, limitTopology(hr.dependencies, c.topologyDepth, map[string]bool{addr: true}))
+	}
+	if err := healthError(hr.status, url); err != nil {
+		return err
+	}
+	if hr.status == healthWarn {
+		dephealth.ReportDetail(ctx, "health_warn")
+	}
+	return nil
+}
+
+// statusAccepted reports whether code is one of the expected status codes.
+func (c *Checker) statusAccepted(code int) bool {
+	if len(c.expectedStatus) == 0 {
+		return code >= 200 && code < 300
+	}
+	return slices.Contains(c.expectedStatus, code)
+}
+
+// hasBodyAssertions reports whether the response body must be inspected.
+func (c *Checker) hasBodyAssertions() bool {
+	return c.bodyContains != "" || c.bodyRe != nil || c.jsonPath != ""
+}
+
+// checkBody applies the substring, regex and JSONPath assertions to data.
+func (c *Checker) checkBody(data []byte, url string) error {
+	if c.bodyContains != "" && !strings.Contains(string(data), c.bodyContains) {
+		return bodyMismatch("response body from %s does not contain %q", url, c.bodyContains)
+	}
+	if c.bodyRe != nil && !c.bodyRe.Match(data) {
+		return bodyMismatch("response body from %s does not match %q", url, c.bodyRegex)
+	}
+	if c.jsonPath != "" {
+		var doc any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return bodyMismatch("response body from %s is not JSON: %v", url, err)
+		}
+		v, ok := c.path.Lookup(doc)
+		if !ok {
+			return bodyMismatch("%s not found in response body from %s", c.jsonPath, url)
+		}
+		if got := jsonpath.Format(v); got != c.jsonValue {
+			return bodyMismatch("%s = %q in response body from %s, expected %q", c.jsonPath, got, url, c.jsonValue)
+		}
+	}
+	return nil
+}
+
+// bodyMismatch returns an unhealthy error with detail body_mismatch.
+func bodyMismatch(format string, args ...any) error {
+	return &dephealth.ClassifiedCheckError{
+		Category: dephealth.StatusUnhealthy,
+		Detail:   "body_mismatch",
+		Cause:    fmt.Errorf(format, args...),
+	}
+}
+
+// clientTLSConfig returns the TLS config for the next connection, with the
+// current certificate files, and the generation of those files.
+func (c *Checker) clientTLSConfig() (*tls.Config, uint64, error) {
+	tlsCfg, gen, err := c.tlsSource.Load()
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.tlsSkipVerify {
+		tlsCfg.InsecureSkipVerify = true //nolint:gosec // configurable by user
+	}
+	// Set TLS SNI when hostHeader is configured and TLS is enabled.
+	if c.tlsEnabled && c.hostHeader != "" {
+		tlsCfg.ServerName = c.hostHeader
+	}
+	return tlsCfg, gen, nil
+}
+
+// newTransport returns a transport using tlsCfg.
+func (c *Checker) newTransport(tlsCfg *tls.Config) *http.Transport {
+	t := &http.Transport{
+		TLSClientConfig: tlsCfg,
+	}
+	if c.persistent {
+		t.MaxIdleConnsPerHost = 1
+		t.IdleConnTimeout = c.idleTimeout
+		if t.IdleConnTimeout <= 0 {
+			t.IdleConnTimeout = defaultIdleTimeout
+		}
+	}
+	return t
+}
+
+// transportFor returns the persistent transport of the endpoint at addr,
+// creating it on first use. After the certificate files have been reloaded
+// the transport is replaced, so that new connections use them.
+func (c *Checker) transportFor(addr string, tlsCfg *tls.Config, gen uint64) *http.Transport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if et, ok := c.transports[addr]; ok {
+		if et.tlsGen == gen {
+			return et.transport
+		}
+		et.transport.CloseIdleConnections()
+	}
+	if c.transports == nil {
+		c.transports = make(map[string]endpointTransport)
+	}
+	t := c.newTransport(tlsCfg)
+	c.transports[addr] = endpointTransport{transport: t, tlsGen: gen}
+	return t
+}
+
+// CloseEndpoint closes the persistent connections of the endpoint.
+// The scheduler calls it when the endpoint is removed or the scheduler stops.
+func (c *Checker) CloseEndpoint(endpoint dephealth.Endpoint) {
+	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
+	c.mu.Lock()
+	et, ok := c.transports[addr]
+	delete(c.transports, addr)
+	c.mu.Unlock()
+	if ok {
+		et.transport.CloseIdleConnections()
+	}
+}
+
+// Close closes the persistent connections of all endpoints. The scheduler
+// calls it once no endpoint uses the checker any more.
+func (c *Checker) Close() error {
+	c.mu.Lock()
+	transports := c.transports
+	c.transports = nil
+	c.mu.Unlock()
+	for _, et := range transports {
+		et.transport.CloseIdleConnections()
+	}
 	return nil
 }
 
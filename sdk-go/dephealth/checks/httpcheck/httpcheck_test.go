@@ -4,14 +4,22 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"errors"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlstest"
 )
 
 func TestChecker_Check_Success(t *testing.T) {
@@ -274,6 +282,65 @@ func TestChecker_Check_BasicAuth(t *testing.T) {
 	}
 }
 
+func TestChecker_Check_CredentialProviders(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	ep := dephealth.Endpoint{Host: host, Port: port}
+
+	// The provider is called for every request, so a rotated token is used
+	// by the next check.
+	token := "first"
+	provider := dephealth.CredentialFunc(func(context.Context) (string, error) {
+		return token, nil
+	})
+	checker := New(WithHealthPath("/"), WithBearerToken("static"), WithBearerTokenProvider(provider))
+	for _, want := range []string{"first", "rotated"} {
+		token = want
+		if err := checker.Check(context.Background(), ep); err != nil {
+			t.Fatalf("expected success, got error: %v", err)
+		}
+		if gotAuth != "Bearer "+want {
+			t.Errorf("Authorization = %q, expected %q", gotAuth, "Bearer "+want)
+		}
+	}
+
+	checker = New(WithHealthPath("/"), WithBasicAuthProvider("admin", provider))
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if want := "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:rotated")); gotAuth != want {
+		t.Errorf("Authorization = %q, expected %q", gotAuth, want)
+	}
+}
+
+func TestChecker_Check_CredentialProviderError(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	ep := dephealth.Endpoint{Host: host, Port: port}
+
+	checker := New(WithHealthPath("/"), WithBearerTokenProvider(dephealth.EnvCredential("DEPHEALTH_TEST_UNSET_TOKEN")))
+	err := checker.Check(context.Background(), ep)
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) || ce.Detail != "credential_error" {
+		t.Fatalf("expected credential_error, got %v", err)
+	}
+	if called {
+		t.Error("expected no request without credentials")
+	}
+}
+
 func TestChecker_Check_CustomHeaders(t *testing.T) {
 	var gotAPIKey, gotCustom string
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -453,3 +520,763 @@ func TestChecker_Check_503_Unhealthy(t *testing.T) {
 		t.Errorf("Detail = %q, expected %q", ce.Detail, "http_503")
 	}
 }
+
+func TestChecker_Check_ReportsTLSCertExpiry(t *testing.T) {
+	ca := tlstest.NewCA(t)
+	leaf := ca.Issue(t, time.Now().Add(48*time.Hour).Truncate(time.Second), "127.0.0.1")
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{leaf.TLSCertificate(t)}}
+	srv.StartTLS()
+	defer srv.Close()
+
+	metrics, err := dephealth.NewMetricsExporter("test-app", "test-group",
+		dephealth.WithMetricsRegisterer(prometheus.NewRegistry()))
+	if err != nil {
+		t.Fatalf("NewMetricsExporter: %v", err)
+	}
+	cfg := dephealth.DefaultCheckConfig()
+	cfg.InitialDelay = 0
+	cfg.TLSExpiryWarning = 7 * 24 * time.Hour
+	sched := dephealth.NewScheduler(metrics, dephealth.WithGlobalCheckConfig(cfg))
+	if err := sched.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer sched.Stop()
+
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	ep := dephealth.Endpoint{Host: host, Port: port}
+	checker := New(WithHealthPath("/"), WithTLSEnabled(true), WithTLSSkipVerify(true))
+	if err := sched.AddEndpoint("web", dephealth.TypeHTTP, false, ep, checker); err != nil {
+		t.Fatalf("AddEndpoint: %v", err)
+	}
+
+	key := "web:" + host + ":" + port
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		es := sched.HealthDetails()[key]
+		if !es.LastCheckedAt.IsZero() {
+			if !es.TLSCertExpiry.Equal(leaf.Cert.NotAfter) {
+				t.Errorf("TLSCertExpiry = %v, expected %v", es.TLSCertExpiry, leaf.Cert.NotAfter)
+			}
+			if es.Detail != "tls_expiring" {
+				t.Errorf("Detail = %q, expected %q", es.Detail, "tls_expiring")
+			}
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("endpoint was not checked in time")
+}
+
+// startConnCountingServer starts a server that counts opened and closed
+// client connections.
+func startConnCountingServer(t *testing.T) (dephealth.Endpoint, *atomic.Int64, *atomic.Int64) {
+	t.Helper()
+	var opened, closed atomic.Int64
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"UP"}`))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		switch state {
+		case http.StateNew:
+			opened.Add(1)
+		case http.StateClosed:
+			closed.Add(1)
+		}
+	}
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	return dephealth.Endpoint{Host: host, Port: port}, &opened, &closed
+}
+
+// waitFor polls cond until it holds or a second has passed.
+func waitFor(cond func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return true
+}
+
+func TestChecker_Check_ClosesConnectionsByDefault(t *testing.T) {
+	ep, opened, closed := startConnCountingServer(t)
+
+	checker := New(WithHealthPath("/"))
+	for range 3 {
+		if err := checker.Check(context.Background(), ep); err != nil {
+			t.Fatalf("check failed: %v", err)
+		}
+	}
+	if got := opened.Load(); got != 3 {
+		t.Errorf("opened connections = %d, expected 3", got)
+	}
+	if !waitFor(func() bool { return closed.Load() == 3 }) {
+		t.Errorf("closed connections = %d, expected 3", closed.Load())
+	}
+}
+
+func TestChecker_Check_PersistentConnections(t *testing.T) {
+	ep, opened, closed := startConnCountingServer(t)
+
+	checker := New(WithHealthPath("/"), WithPersistentConnections(time.Minute))
+	for range 3 {
+		if err := checker.Check(context.Background(), ep); err != nil {
+			t.Fatalf("check failed: %v", err)
+		}
+	}
+	if got := opened.Load(); got != 1 {
+		t.Errorf("opened connections = %d, expected 1 reused connection", got)
+	}
+
+	checker.CloseEndpoint(ep)
+	if !waitFor(func() bool { return closed.Load() == 1 }) {
+		t.Errorf("connection not closed by CloseEndpoint")
+	}
+
+	// The next check connects again.
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Fatalf("check after CloseEndpoint failed: %v", err)
+	}
+	if got := opened.Load(); got != 2 {
+		t.Errorf("opened connections = %d, expected 2", got)
+	}
+	checker.CloseEndpoint(ep)
+}
+
+func TestChecker_Check_PersistentIdleTimeout(t *testing.T) {
+	ep, opened, closed := startConnCountingServer(t)
+
+	checker := New(WithHealthPath("/"), WithPersistentConnections(50*time.Millisecond))
+	defer checker.CloseEndpoint(ep)
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if !waitFor(func() bool { return closed.Load() == 1 }) {
+		t.Fatal("idle connection not closed after the idle timeout")
+	}
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if got := opened.Load(); got != 2 {
+		t.Errorf("opened connections = %d, expected 2", got)
+	}
+}
+
+func TestChecker_Close(t *testing.T) {
+	ep1, _, closed1 := startConnCountingServer(t)
+	ep2, _, closed2 := startConnCountingServer(t)
+
+	checker := New(WithHealthPath("/"), WithPersistentConnections(time.Minute))
+	for _, ep := range []dephealth.Endpoint{ep1, ep2} {
+		if err := checker.Check(context.Background(), ep); err != nil {
+			t.Fatalf("check failed: %v", err)
+		}
+	}
+	if err := checker.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !waitFor(func() bool { return closed1.Load() == 1 && closed2.Load() == 1 }) {
+		t.Errorf("closed connections = %d, %d, expected 1, 1", closed1.Load(), closed2.Load())
+	}
+}
+
+func TestNewFromConfig_PersistentConnections(t *testing.T) {
+	checker := NewFromConfig(&dephealth.DependencyConfig{
+		HTTPPersistentConnections: true,
+		HTTPIdleTimeout:           2 * time.Minute,
+	}).(*Checker)
+	if !checker.persistent || checker.idleTimeout != 2*time.Minute {
+		t.Errorf("persistent = %v, idleTimeout = %v", checker.persistent, checker.idleTimeout)
+	}
+	if got := checker.newTransport(nil).IdleConnTimeout; got != 2*time.Minute {
+		t.Errorf("IdleConnTimeout = %v, expected 2m", got)
+	}
+	if got := New(WithPersistentConnections(0)).newTransport(nil).IdleConnTimeout; got != defaultIdleTimeout {
+		t.Errorf("default IdleConnTimeout = %v, expected %v", got, defaultIdleTimeout)
+	}
+}
+
+// startBodyServer serves body with status on every path and records the
+// last request method, body and Content-Type.
+func startBodyServer(t *testing.T, status int, body string) (dephealth.Endpoint, *http.Request, *[]byte) {
+	t.Helper()
+	var last http.Request
+	var lastBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = *r
+		lastBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	return dephealth.Endpoint{Host: host, Port: port}, &last, &lastBody
+}
+
+// expectBodyMismatch fails unless err is unhealthy with detail body_mismatch.
+func expectBodyMismatch(t *testing.T, err error) {
+	t.Helper()
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected ClassifiedCheckError, got %T: %v", err, err)
+	}
+	if ce.Category != dephealth.StatusUnhealthy || ce.Detail != "body_mismatch" {
+		t.Errorf("category/detail = %s/%s, expected unhealthy/body_mismatch", ce.Category, ce.Detail)
+	}
+}
+
+func TestChecker_Check_MethodAndBody(t *testing.T) {
+	ep, last, lastBody := startBodyServer(t, http.StatusOK, "")
+
+	checker := New(WithHealthPath("/probe"), WithMethod(http.MethodPost),
+		WithRequestBody("application/json", []byte(`{"ping":true}`)))
+	for range 2 {
+		if err := checker.Check(context.Background(), ep); err != nil {
+			t.Fatalf("check failed: %v", err)
+		}
+	}
+	if last.Method != http.MethodPost {
+		t.Errorf("method = %s, expected POST", last.Method)
+	}
+	if got := last.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, expected application/json", got)
+	}
+	// The body is sent again on every check.
+	if string(*lastBody) != `{"ping":true}` {
+		t.Errorf("body = %q, expected {\"ping\":true}", *lastBody)
+	}
+}
+
+func TestChecker_Check_ExpectedStatus(t *testing.T) {
+	ep, _, _ := startBodyServer(t, http.StatusServiceUnavailable, "")
+
+	checker := New(WithHealthPath("/"), WithExpectedStatus(200, 503))
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected 503 to be accepted, got: %v", err)
+	}
+
+	ep2, _, _ := startBodyServer(t, http.StatusNoContent, "")
+	err := New(WithHealthPath("/"), WithExpectedStatus(200)).Check(context.Background(), ep2)
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) || ce.Detail != "http_204" {
+		t.Errorf("expected detail http_204 for a status outside the set, got %v", err)
+	}
+}
+
+func TestChecker_Check_BodyAssertions(t *testing.T) {
+	body := `{"status":"UP","components":{"db":{"status":"DOWN"}},"checks":[{"ok":true}]}`
+	ep, _, _ := startBodyServer(t, http.StatusOK, body)
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		healthy bool
+	}{
+		{"contains", []Option{WithBodyContains(`"status":"UP"`)}, true},
+		{"contains mismatch", []Option{WithBodyContains("OUT_OF_SERVICE")}, false},
+		{"regex", []Option{WithBodyRegex(`"status":\s*"UP"`)}, true},
+		{"regex mismatch", []Option{WithBodyRegex(`^\s*\[`)}, false},
+		{"json path", []Option{WithBodyJSONPath("$.status", "UP")}, true},
+		{"json path nested", []Option{WithBodyJSONPath("$.components.db.status", "DOWN")}, true},
+		{"json path bool", []Option{WithBodyJSONPath("$.checks[0].ok", "true")}, true},
+		{"json path mismatch", []Option{WithBodyJSONPath("$.components.db.status", "UP")}, false},
+		{"json path missing", []Option{WithBodyJSONPath("$.version", "1")}, false},
+		{"all", []Option{WithBodyContains("UP"), WithBodyRegex("components"), WithBodyJSONPath("$.status", "UP")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := New(append([]Option{WithHealthPath("/")}, tt.opts...)...)
+			err := checker.Check(context.Background(), ep)
+			if tt.healthy {
+				if err != nil {
+					t.Errorf("expected success, got: %v", err)
+				}
+				return
+			}
+			expectBodyMismatch(t, err)
+		})
+	}
+}
+
+func TestChecker_Check_BodyNotJSON(t *testing.T) {
+	ep, _, _ := startBodyServer(t, http.StatusOK, "OK")
+
+	err := New(WithHealthPath("/"), WithBodyJSONPath("$.status", "UP")).Check(context.Background(), ep)
+	expectBodyMismatch(t, err)
+	if !strings.Contains(err.Error(), "not JSON") {
+		t.Errorf("expected 'not JSON' in error, got: %v", err)
+	}
+}
+
+func TestChecker_Check_MaxBodySize(t *testing.T) {
+	ep, _, _ := startBodyServer(t, http.StatusOK, strings.Repeat("x", 100)+"UP")
+
+	err := New(WithHealthPath("/"), WithBodyContains("UP"), WithMaxBodySize(64)).Check(context.Background(), ep)
+	expectBodyMismatch(t, err)
+	if !strings.Contains(err.Error(), "exceeds 64 bytes") {
+		t.Errorf("expected size limit in error, got: %v", err)
+	}
+
+	if err := New(WithHealthPath("/"), WithBodyContains("UP")).Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success with the default limit, got: %v", err)
+	}
+}
+
+func TestChecker_Check_InvalidAssertionConfig(t *testing.T) {
+	ep, _, _ := startBodyServer(t, http.StatusOK, "")
+
+	for _, opt := range []Option{WithBodyRegex("("), WithBodyJSONPath("status", "UP")} {
+		err := New(WithHealthPath("/"), opt).Check(context.Background(), ep)
+		if err == nil || !strings.Contains(err.Error(), "http checker config") {
+			t.Errorf("expected config error, got: %v", err)
+		}
+	}
+}
+
+func TestChecker_Check_MaxRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) { http.Redirect(w, r, "/b", http.StatusFound) })
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) { http.Redirect(w, r, "/c", http.StatusFound) })
+	mux.HandleFunc("/c", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	ep := dephealth.Endpoint{Host: host, Port: port}
+
+	if err := New(WithHealthPath("/a")).Check(context.Background(), ep); err != nil {
+		t.Errorf("default: expected redirects to be followed, got: %v", err)
+	}
+	if err := New(WithHealthPath("/a"), WithMaxRedirects(2)).Check(context.Background(), ep); err != nil {
+		t.Errorf("limit 2: expected success, got: %v", err)
+	}
+
+	err := New(WithHealthPath("/a"), WithMaxRedirects(1)).Check(context.Background(), ep)
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) || ce.Detail != "http_302" {
+		t.Errorf("limit 1: expected detail http_302, got %v", err)
+	}
+
+	if err := New(WithHealthPath("/a"), WithMaxRedirects(0), WithExpectedStatus(http.StatusFound)).Check(context.Background(), ep); err != nil {
+		t.Errorf("limit 0 with 302 accepted: expected success, got: %v", err)
+	}
+}
+
+func TestNewFromConfig_Assertions(t *testing.T) {
+	redirects := 3
+	checker := NewFromConfig(&dephealth.DependencyConfig{
+		HTTPMethod:          http.MethodHead,
+		HTTPBody:            []byte("q"),
+		HTTPBodyContentType: "text/plain",
+		HTTPExpectedStatus:  []int{200, 204},
+		HTTPMaxRedirects:    &redirects,
+		HTTPMaxBodySize:     2048,
+		HTTPBodyContains:    "UP",
+		HTTPBodyRegex:       "U.",
+		HTTPBodyJSONPath:    "$.status",
+		HTTPBodyJSONValue:   "UP",
+	}).(*Checker)
+	if checker.method != http.MethodHead || string(checker.body) != "q" || checker.contentType != "text/plain" {
+		t.Errorf("method/body/contentType = %s/%s/%s", checker.method, checker.body, checker.contentType)
+	}
+	if len(checker.expectedStatus) != 2 || checker.maxRedirects != 3 || checker.maxBodySize != 2048 {
+		t.Errorf("expectedStatus/maxRedirects/maxBodySize = %v/%d/%d", checker.expectedStatus, checker.maxRedirects, checker.maxBodySize)
+	}
+	if checker.bodyContains != "UP" || checker.bodyRe == nil || checker.path.String() != "$.status" || checker.jsonValue != "UP" {
+		t.Errorf("body assertions not applied: %+v", checker)
+	}
+	if checker.configErr != nil {
+		t.Errorf("unexpected config error: %v", checker.configErr)
+	}
+}
+
+func TestParseHealthResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      ResponseFormat
+		contentType string
+		body        string
+		status      string
+		components  map[string]dephealth.ComponentStatus
+	}{
+		{
+			name:   "spring",
+			format: FormatSpring,
+			body: `{"status":"DOWN","components":{
+				"db":{"status":"DOWN","details":{"error":"connection refused"}},
+				"diskSpace":{"status":"UP","details":{"free":1024}},
+				"broker":{"status":"UP","components":{"primary":{"status":"OUT_OF_SERVICE"}}}}}`,
+			status: healthDown,
+			components: map[string]dephealth.ComponentStatus{
+				"db":             {Status: "DOWN", Detail: "connection refused"},
+				"diskSpace":      {Status: "UP", Healthy: true},
+				"broker":         {Status: "UP", Healthy: true},
+				"broker/primary": {Status: "OUT_OF_SERVICE"},
+			},
+		},
+		{
+			name:   "spring boot 2.0 details",
+			format: FormatSpring,
+			body:   `{"status":"UP","details":{"db":{"status":"UP","details":{"database":"PostgreSQL"}}}}`,
+			status: healthUp,
+			components: map[string]dephealth.ComponentStatus{
+				"db": {Status: "UP", Healthy: true},
+			},
+		},
+		{
+			name:   "health+json",
+			format: FormatHealthJSON,
+			body: `{"status":"warn","checks":{
+				"db:connections":[{"componentId":"primary","status":"pass"},{"componentId":"replica","status":"fail","output":"timeout"}],
+				"uptime":[{"status":"warn"}]}}`,
+			status: healthWarn,
+			components: map[string]dephealth.ComponentStatus{
+				"db:connections/primary": {Status: "pass", Healthy: true},
+				"db:connections/replica": {Status: "fail", Detail: "timeout"},
+				"uptime":                 {Status: "warn", Healthy: true},
+			},
+		},
+		{
+			name:   "dephealth critical down",
+			format: FormatDephealth,
+			body: `{"pg:db.svc:5432":{"healthy":false,"status":"timeout","detail":"timeout","critical":true},
+				"redis:cache.svc:6379":{"healthy":true,"status":"ok","detail":"ok","critical":false}}`,
+			status: healthDown,
+			components: map[string]dephealth.ComponentStatus{
+				"pg:db.svc:5432":       {Status: "timeout", Detail: "timeout"},
+				"redis:cache.svc:6379": {Status: "ok", Healthy: true, Detail: "ok"},
+			},
+		},
+		{
+			name:   "dephealth non-critical down",
+			format: FormatDephealth,
+			body:   `{"redis:cache.svc:6379":{"healthy":false,"status":"connection_error","detail":"connection_refused","critical":false}}`,
+			status: healthWarn,
+			components: map[string]dephealth.ComponentStatus{
+				"redis:cache.svc:6379": {Status: "connection_error", Detail: "connection_refused"},
+			},
+		},
+		{
+			name:        "auto by content type",
+			format:      FormatAuto,
+			contentType: "application/health+json; charset=utf-8",
+			body:        `{"status":"up"}`,
+			status:      healthUp,
+		},
+		{name: "auto spring", format: FormatAuto, body: `{"status":"OUT_OF_SERVICE"}`, status: healthOutOfService},
+		{name: "auto health+json", format: FormatAuto, body: `{"status":"fail"}`, status: healthDown},
+		{
+			name:   "auto dephealth",
+			format: FormatAuto,
+			body:   `{"pg:db.svc:5432":{"healthy":true,"status":"ok","detail":"ok","critical":true}}`,
+			status: healthUp,
+			components: map[string]dephealth.ComponentStatus{
+				"pg:db.svc:5432": {Status: "ok", Healthy: true, Detail: "ok"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hr, ok := parseHealthResponse(tt.format, tt.contentType, []byte(tt.body))
+			if !ok {
+				t.Fatal("expected the body to be parsed")
+			}
+			if hr.status != tt.status {
+				t.Errorf("status = %s, expected %s", hr.status, tt.status)
+			}
+			if tt.components == nil {
+				return
+			}
+			if len(hr.components) != len(tt.components) {
+				t.Errorf("components = %v, expected %v", hr.components, tt.components)
+			}
+			for name, want := range tt.components {
+				if got := hr.components[name]; got != want {
+					t.Errorf("component %s = %+v, expected %+v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseHealthResponse_Unrecognized(t *testing.T) {
+	for _, tt := range []struct {
+		format ResponseFormat
+		body   string
+	}{
+		{FormatAuto, `OK`},
+		{FormatAuto, `{"version":"1.2.3"}`},
+		{FormatAuto, `{}`},
+		{FormatSpring, `{"checks":{}}`},
+		{FormatHealthJSON, `[1,2]`},
+		{FormatDephealth, `{"status":"UP"}`},
+	} {
+		if _, ok := parseHealthResponse(tt.format, "", []byte(tt.body)); ok {
+			t.Errorf("%s %s: expected not to be parsed", tt.format, tt.body)
+		}
+	}
+}
+
+func TestChecker_Check_ResponseFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     ResponseFormat
+		status     int
+		body       string
+		wantDetail string // empty = healthy
+	}{
+		{"spring up", FormatSpring, http.StatusOK, `{"status":"UP"}`, ""},
+		{"spring down 503", FormatSpring, http.StatusServiceUnavailable, `{"status":"DOWN"}`, "health_down"},
+		{"spring out of service", FormatSpring, http.StatusServiceUnavailable, `{"status":"OUT_OF_SERVICE"}`, "health_out_of_service"},
+		{"spring down 200", FormatSpring, http.StatusOK, `{"status":"DOWN"}`, "health_down"},
+		{"spring unknown", FormatSpring, http.StatusOK, `{"status":"UNKNOWN"}`, "health_unknown"},
+		{"health+json warn", FormatHealthJSON, http.StatusOK, `{"status":"warn"}`, ""},
+		{"up with rejected status", FormatSpring, http.StatusInternalServerError, `{"status":"UP"}`, "http_500"},
+		{"explicit format mismatch", FormatSpring, http.StatusOK, `OK`, "body_mismatch"},
+		{"explicit format mismatch 503", FormatSpring, http.StatusServiceUnavailable, `Service Unavailable`, "http_503"},
+		{"auto plain text", FormatAuto, http.StatusOK, `OK`, ""},
+		{"auto plain text 503", FormatAuto, http.StatusServiceUnavailable, `down`, "http_503"},
+		{"auth error", FormatSpring, http.StatusUnauthorized, `{"status":"UP"}`, "auth_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep, _, _ := startBodyServer(t, tt.status, tt.body)
+			err := New(WithHealthPath("/"), WithResponseFormat(tt.format)).Check(context.Background(), ep)
+			if tt.wantDetail == "" {
+				if err != nil {
+					t.Errorf("expected success, got: %v", err)
+				}
+				return
+			}
+			var ce *dephealth.ClassifiedCheckError
+			if !errors.As(err, &ce) || ce.Detail != tt.wantDetail {
+				t.Errorf("expected detail %s, got %v", tt.wantDetail, err)
+			}
+		})
+	}
+}
+
+func TestChecker_Check_ResponseFormatWithAssertions(t *testing.T) {
+	ep, _, _ := startBodyServer(t, http.StatusOK, `{"status":"UP","components":{"db":{"status":"UP"}}}`)
+
+	err := New(WithHealthPath("/"), WithResponseFormat(FormatSpring),
+		WithBodyJSONPath("$.components.db.status", "DOWN")).Check(context.Background(), ep)
+	expectBodyMismatch(t, err)
+}
+
+func TestChecker_InvalidResponseFormat(t *testing.T) {
+	ep, _, _ := startBodyServer(t, http.StatusOK, "")
+	err := New(WithHealthPath("/"), WithResponseFormat("xml")).Check(context.Background(), ep)
+	if err == nil || !strings.Contains(err.Error(), "unknown response format") {
+		t.Errorf("expected config error, got: %v", err)
+	}
+}
+
+func TestNewFromConfig_ResponseFormat(t *testing.T) {
+	checker := NewFromConfig(&dephealth.DependencyConfig{HTTPResponseFormat: "health+json"}).(*Checker)
+	if checker.format != FormatHealthJSON {
+		t.Errorf("format = %q, expected health+json", checker.format)
+	}
+}
+
+func TestLimitTopology(t *testing.T) {
+	// orders -> payments -> {postgres, orders (cycle)}; the checked
+	// endpoint is orders.svc:8080 itself.
+	deps := map[string]dephealth.EndpointStatus{
+		"payments:payments.svc:8080": {
+			Name: "payments", Host: "payments.svc", Port: "8080",
+			Dependencies: map[string]dephealth.EndpointStatus{
+				"postgres:pg.svc:5432": {Name: "postgres", Host: "pg.svc", Port: "5432"},
+				"orders:orders.svc:8080": {
+					Name: "orders", Host: "orders.svc", Port: "8080",
+					Dependencies: map[string]dephealth.EndpointStatus{
+						"payments:payments.svc:8080": {Name: "payments", Host: "payments.svc", Port: "8080"},
+					},
+				},
+			},
+		},
+	}
+
+	got := limitTopology(deps, 1, map[string]bool{"orders.svc:8080": true})
+	if got["payments:payments.svc:8080"].Dependencies != nil {
+		t.Errorf("depth 1: expected no nested dependencies, got %v", got)
+	}
+
+	got = limitTopology(deps, 5, map[string]bool{"orders.svc:8080": true})
+	payments := got["payments:payments.svc:8080"]
+	if len(payments.Dependencies) != 2 {
+		t.Fatalf("depth 5: payments dependencies = %v", payments.Dependencies)
+	}
+	if orders := payments.Dependencies["orders:orders.svc:8080"]; orders.Name != "orders" || orders.Dependencies != nil {
+		t.Errorf("cycle back to the checked endpoint was expanded: %+v", orders)
+	}
+
+	// The input is not modified.
+	if deps["payments:payments.svc:8080"].Dependencies["orders:orders.svc:8080"].Dependencies == nil {
+		t.Error("limitTopology modified its input")
+	}
+}
+
+func TestChecker_Check_TopologyDepth(t *testing.T) {
+	body := `{"postgres:pg.svc:5432":{"healthy":false,"status":"connection_error","detail":"connection_refused",
+		"type":"postgres","name":"postgres","host":"pg.svc","port":"5432","critical":true},
+		"payments:payments.svc:8080":{"healthy":true,"status":"ok","detail":"ok",
+		"type":"http","name":"payments","host":"payments.svc","port":"8080","critical":false,
+		"dependencies":{"redis:redis.svc:6379":{"healthy":true,"status":"ok","detail":"ok",
+		"type":"redis","name":"redis","host":"redis.svc","port":"6379","critical":true}}}}`
+	ep, _, _ := startBodyServer(t, http.StatusServiceUnavailable, body)
+
+	metrics, err := dephealth.NewMetricsExporter("test-app", "test-group",
+		dephealth.WithMetricsRegisterer(prometheus.NewRegistry()))
+	if err != nil {
+		t.Fatalf("NewMetricsExporter: %v", err)
+	}
+	cfg := dephealth.DefaultCheckConfig()
+	cfg.InitialDelay = 0
+	sched := dephealth.NewScheduler(metrics, dephealth.WithGlobalCheckConfig(cfg))
+	if err := sched.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer sched.Stop()
+
+	checker := New(WithHealthPath("/"), WithTopologyDepth(1))
+	if err := sched.AddEndpoint("orders", dephealth.TypeHTTP, true, ep, checker); err != nil {
+		t.Fatalf("AddEndpoint: %v", err)
+	}
+
+	key := "orders:" + ep.Host + ":" + ep.Port
+	if !waitFor(func() bool { return !sched.HealthDetails()[key].LastCheckedAt.IsZero() }) {
+		t.Fatal("endpoint was not checked in time")
+	}
+	es := sched.HealthDetails()[key]
+	if es.Detail != "health_down" {
+		t.Errorf("Detail = %q, expected health_down", es.Detail)
+	}
+	pg := es.Dependencies["postgres:pg.svc:5432"]
+	if !pg.Critical || pg.Detail != "connection_refused" {
+		t.Errorf("postgres edge = %+v", pg)
+	}
+	if payments := es.Dependencies["payments:payments.svc:8080"]; payments.Name != "payments" || payments.Dependencies != nil {
+		t.Errorf("payments edge = %+v, expected no nested dependencies at depth 1", payments)
+	}
+}
+
+func TestChecker_TopologyDepthConfig(t *testing.T) {
+	if c := New(WithTopologyDepth(2)); c.format != FormatDephealth || c.configErr != nil {
+		t.Errorf("format = %q, configErr = %v, expected dephealth", c.format, c.configErr)
+	}
+	if c := New(WithTopologyDepth(2), WithResponseFormat(FormatAuto)); c.format != FormatAuto || c.configErr != nil {
+		t.Errorf("format = %q, configErr = %v, expected auto", c.format, c.configErr)
+	}
+	if c := New(WithTopologyDepth(1), WithResponseFormat(FormatSpring)); c.configErr == nil {
+		t.Error("expected a config error for spring with a topology depth")
+	}
+	if c := New(WithTopologyDepth(-1)); c.configErr == nil {
+		t.Error("expected a config error for a negative topology depth")
+	}
+	checker := NewFromConfig(&dephealth.DependencyConfig{HTTPTopologyDepth: 3}).(*Checker)
+	if checker.topologyDepth != 3 || checker.format != FormatDephealth {
+		t.Errorf("topologyDepth = %d, format = %q", checker.topologyDepth, checker.format)
+	}
+}
+
+// startMTLSServer starts an HTTPS server that requires a client certificate
+// issued by ca.
+func startMTLSServer(t *testing.T, ca *tlstest.CA) dephealth.Endpoint {
+	t.Helper()
+	leaf := ca.Issue(t, time.Now().Add(time.Hour), "127.0.0.1")
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{leaf.TLSCertificate(t)},
+		ClientCAs:    ca.Pool(),
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	return dephealth.Endpoint{Host: host, Port: port}
+}
+
+func TestChecker_Check_MTLSFiles(t *testing.T) {
+	ca := tlstest.NewCA(t)
+	ep := startMTLSServer(t, ca)
+	dir := t.TempDir()
+	client := ca.Issue(t, time.Now().Add(time.Hour), "client")
+
+	checker := NewFromConfig(&dephealth.DependencyConfig{
+		HTTPHealthPath: "/",
+		TLSCAFile:      tlstest.WriteFile(t, dir, "ca.pem", ca.PEM),
+		TLSCertFile:    tlstest.WriteFile(t, dir, "tls.crt", client.CertPEM),
+		TLSKeyFile:     tlstest.WriteFile(t, dir, "tls.key", client.KeyPEM),
+	}).(*Checker)
+	if !checker.tlsEnabled {
+		t.Error("expected shared TLS files to enable HTTPS")
+	}
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success with the client certificate, got: %v", err)
+	}
+
+	// Without a client certificate the handshake is rejected.
+	noCert := New(WithHealthPath("/"), WithTLSCA(checker.tls.CAFile))
+	if err := noCert.Check(context.Background(), ep); err == nil {
+		t.Error("expected failure without a client certificate")
+	}
+}
+
+func TestChecker_Check_TLSConfig(t *testing.T) {
+	ca := tlstest.NewCA(t)
+	ep := startMTLSServer(t, ca)
+	client := ca.Issue(t, time.Now().Add(time.Hour), "client")
+
+	checker := New(WithHealthPath("/"), WithTLSConfig(&tls.Config{
+		RootCAs:      ca.Pool(),
+		Certificates: []tls.Certificate{client.TLSCertificate(t)},
+		MinVersion:   tls.VersionTLS12,
+	}))
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success with the base TLS config, got: %v", err)
+	}
+}
+
+func TestChecker_Check_ReloadsRotatedClientCert(t *testing.T) {
+	ca := tlstest.NewCA(t)
+	ep := startMTLSServer(t, ca)
+	dir := t.TempDir()
+
+	// The first certificate is issued by a CA the server does not trust.
+	untrusted := tlstest.NewCA(t).Issue(t, time.Now().Add(time.Hour), "client")
+	certFile := tlstest.WriteFile(t, dir, "tls.crt", untrusted.CertPEM)
+	keyFile := tlstest.WriteFile(t, dir, "tls.key", untrusted.KeyPEM)
+
+	checker := New(WithHealthPath("/"), WithPersistentConnections(time.Minute),
+		WithTLSCA(tlstest.WriteFile(t, dir, "ca.pem", ca.PEM)),
+		WithTLSClientCert(certFile, keyFile))
+	defer func() { _ = checker.Close() }()
+	if err := checker.Check(context.Background(), ep); err == nil {
+		t.Fatal("expected failure with an untrusted client certificate")
+	}
+
+	rotated := ca.Issue(t, time.Now().Add(time.Hour), "client")
+	tlstest.WriteFile(t, dir, "tls.crt", rotated.CertPEM)
+	tlstest.WriteFile(t, dir, "tls.key", rotated.KeyPEM)
+	future := time.Now().Add(time.Minute)
+	for _, f := range []string{certFile, keyFile} {
+		if err := os.Chtimes(f, future, future); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success after the rotation, got: %v", err)
+	}
+}
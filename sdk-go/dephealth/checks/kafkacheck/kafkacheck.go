@@ -7,62 +7,455 @@ package kafkacheck
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net"
+	"strings"
+	"sync"
 
 	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
 
 	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
 )
 
+// SASL mechanisms.
+const (
+	MechanismPlain       = "PLAIN"
+	MechanismSCRAMSHA256 = "SCRAM-SHA-256"
+	MechanismSCRAMSHA512 = "SCRAM-SHA-512"
+)
+
+var tlsSkipVerifyWarnOnce sync.Once
+
 var _ dephealth.HealthChecker = (*Checker)(nil)
 
 func init() {
 	dephealth.RegisterCheckerFactory(dephealth.TypeKafka, NewFromConfig)
 }
 
+// Option configures the Checker.
+type Option func(*Checker)
+
 // Checker performs health checks against a Kafka broker.
 // Connects to the broker, requests broker metadata, and closes the connection.
+// When a topic is configured, the check also requires the topic to exist,
+// every partition to have a leader and, optionally, a minimum number of
+// in-sync replicas per partition. When a consumer group is configured, the
+// check reports the group's total lag and optionally compares it to a maximum.
 // Only standalone mode is supported.
-type Checker struct{}
+type Checker struct {
+	mechanism  string
+	username   string
+	password   string
+	tlsEnabled bool
+	tls        tlsutil.Options
+	tlsConfig  *tls.Config // base config from WithTLSConfig
+	tlsSource  *tlsutil.Source
+	topic      string
+	minISR     int
+	group      string
+	maxLag     int64
+}
+
+// WithSASL enables SASL authentication with the given mechanism
+// (MechanismPlain, MechanismSCRAMSHA256 or MechanismSCRAMSHA512).
+func WithSASL(mechanism, username, password string) Option {
+	return func(c *Checker) {
+		c.mechanism = mechanism
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithTLS enables TLS for broker connections.
+func WithTLS(enabled bool) Option {
+	return func(c *Checker) {
+		c.tlsEnabled = enabled
+	}
+}
+
+// WithTLSCA sets the PEM CA bundle used to verify broker certificates.
+// Implies TLS.
+func WithTLSCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tls.CAFile = caFile
+	}
+}
+
+// WithTLSClientCert sets the client certificate and key for mTLS.
+// Implies TLS.
+func WithTLSClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tls.CertFile = certFile
+		c.tls.KeyFile = keyFile
+	}
+}
 
-// New creates a new Kafka health checker.
-func New() *Checker {
-	return &Checker{}
+// WithTLSConfig sets the base TLS configuration and enables TLS. The files
+// of WithTLSCA and WithTLSClientCert are loaded on top of a copy and read
+// again when they change.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithTLSSkipVerify skips TLS certificate verification.
+func WithTLSSkipVerify(skip bool) Option {
+	return func(c *Checker) {
+		c.tls.SkipVerify = skip
+	}
+}
+
+// WithTopic requires the topic to exist and every partition to have a leader.
+func WithTopic(topic string) Option {
+	return func(c *Checker) {
+		c.topic = topic
+	}
+}
+
+// WithMinISR requires at least n in-sync replicas for every partition of the
+// topic set by WithTopic.
+func WithMinISR(n int) Option {
+	return func(c *Checker) {
+		c.minISR = n
+	}
+}
+
+// WithConsumerGroup reports the total lag of the consumer group: the sum over
+// its committed partitions of the high watermark minus the committed offset.
+// With maxLag > 0 the check fails with detail consumer_lag when the lag
+// exceeds maxLag. The lag is limited to the topic set by WithTopic, if any.
+func WithConsumerGroup(group string, maxLag int64) Option {
+	return func(c *Checker) {
+		c.group = group
+		c.maxLag = maxLag
+	}
+}
+
+// New creates a new Kafka health checker with the given options.
+func New(opts ...Option) *Checker {
+	c := &Checker{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.tlsSource = tlsutil.NewSource(c.tlsConfig, c.tls)
+	return c
 }
 
 // NewFromConfig creates a Kafka checker from DependencyConfig.
-func NewFromConfig(_ *dephealth.DependencyConfig) dephealth.HealthChecker {
-	return New()
+func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
+	var opts []Option
+	if dc.KafkaSASLMechanism != "" {
+		opts = append(opts, WithSASL(dc.KafkaSASLMechanism, dc.KafkaUsername, dc.KafkaPassword))
+	}
+	if dc.KafkaTLS != nil {
+		opts = append(opts, WithTLS(*dc.KafkaTLS))
+	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
+	if dc.KafkaTLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.KafkaTLSCAFile))
+	}
+	if dc.KafkaTLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.KafkaTLSCertFile, dc.KafkaTLSKeyFile))
+	}
+	if dc.TLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.TLSSkipVerify))
+	}
+	if dc.KafkaTLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.KafkaTLSSkipVerify))
+	}
+	if dc.KafkaTopic != "" {
+		opts = append(opts, WithTopic(dc.KafkaTopic))
+	}
+	if dc.KafkaMinISR > 0 {
+		opts = append(opts, WithMinISR(dc.KafkaMinISR))
+	}
+	if dc.KafkaConsumerGroup != "" {
+		opts = append(opts, WithConsumerGroup(dc.KafkaConsumerGroup, dc.KafkaMaxLag))
+	}
+	return New(opts...)
 }
 
 // Check connects to the Kafka broker, requests metadata, and closes.
-// Returns nil if the broker responds with metadata.
+// Returns nil if the broker responds with metadata and, when a topic is
+// configured, the topic assertions hold. The consumer group lag is checked
+// last.
 func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
+	return c.check(ctx, endpoint)
+}
+
+// check performs the Kafka check.
+func (c *Checker) check(ctx context.Context, endpoint dephealth.Endpoint) error {
 	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
 
-	dialer := &kafka.Dialer{}
+	mechanism, err := c.saslMechanism()
+	if err != nil {
+		return fmt.Errorf("kafka %s: %w", addr, err)
+	}
+
+	var tlsCfg *tls.Config
+	if c.useTLS() {
+		if c.tls.SkipVerify {
+			tlsSkipVerifyWarnOnce.Do(func() {
+				slog.Warn("dephealth: Kafka checker has TLS certificate verification disabled (InsecureSkipVerify=true)")
+			})
+		}
+		if tlsCfg, err = c.tlsSource.Config(); err != nil {
+			return fmt.Errorf("kafka %s: %w", addr, err)
+		}
+	}
+
+	if c.topic != "" {
+		if err := c.checkTopic(ctx, addr, mechanism, tlsCfg); err != nil {
+			return err
+		}
+	} else if err := checkBrokers(ctx, addr, mechanism, tlsCfg); err != nil {
+		return err
+	}
+
+	if c.group != "" {
+		return c.checkConsumerLag(ctx, addr, mechanism, tlsCfg)
+	}
+	return nil
+}
+
+// checkBrokers dials the broker at addr and requires a non-empty broker list.
+func checkBrokers(ctx context.Context, addr string, mechanism sasl.Mechanism, tlsCfg *tls.Config) error {
+	dialer := &kafka.Dialer{
+		SASLMechanism: mechanism,
+		TLS:           tlsCfg,
+	}
 	conn, err := dialer.DialContext(ctx, "tcp", addr)
 	if err != nil {
-		return fmt.Errorf("kafka dial %s: %w", addr, err)
+		return classifyError(dephealth.WrapHandshakeEOF(err), "dial", addr)
 	}
 	defer func() { _ = conn.Close() }()
 
 	brokers, err := conn.Brokers()
 	if err != nil {
-		return fmt.Errorf("kafka brokers %s: %w", addr, err)
+		return classifyError(err, "brokers", addr)
 	}
 	if len(brokers) == 0 {
+		return noBrokers(addr)
+	}
+
+	return nil
+}
+
+// checkTopic fetches the topic metadata from the broker at addr. Unlike
+// Conn.ReadPartitions, the request does not auto-create a missing topic.
+func (c *Checker) checkTopic(ctx context.Context, addr string, mechanism sasl.Mechanism, tlsCfg *tls.Config) error {
+	transport := &kafka.Transport{
+		SASL:           mechanism,
+		TLS:            tlsCfg,
+		MetadataTopics: []string{c.topic},
+	}
+	defer transport.CloseIdleConnections()
+
+	client := &kafka.Client{Addr: kafka.TCP(addr), Transport: transport}
+	md, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{c.topic}})
+	if err != nil {
+		return classifyError(err, "metadata", addr)
+	}
+	return c.evaluateTopic(md, addr)
+}
+
+// evaluateTopic applies the topic assertions to a metadata response.
+func (c *Checker) evaluateTopic(md *kafka.MetadataResponse, addr string) error {
+	if len(md.Brokers) == 0 {
+		return noBrokers(addr)
+	}
+
+	var topic *kafka.Topic
+	for i := range md.Topics {
+		if md.Topics[i].Name == c.topic {
+			topic = &md.Topics[i]
+			break
+		}
+	}
+	if topic == nil || errors.Is(topic.Error, kafka.UnknownTopicOrPartition) {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "topic_missing",
+			Cause:    fmt.Errorf("kafka %s: topic %q does not exist", addr, c.topic),
+		}
+	}
+	if topic.Error != nil {
+		return classifyError(topic.Error, "topic "+c.topic, addr)
+	}
+
+	var noLeader, underReplicated []int
+	for _, p := range topic.Partitions {
+		switch {
+		case p.Leader.Host == "" || errors.Is(p.Error, kafka.LeaderNotAvailable):
+			noLeader = append(noLeader, p.ID)
+		case c.minISR > 0 && len(p.Isr) < c.minISR:
+			underReplicated = append(underReplicated, p.ID)
+		}
+	}
+	if len(noLeader) > 0 {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "no_leader",
+			Cause:    fmt.Errorf("kafka %s: topic %q partitions %v have no leader", addr, c.topic, noLeader),
+		}
+	}
+	if len(underReplicated) > 0 {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "under_replicated",
+			Cause: fmt.Errorf("kafka %s: topic %q partitions %v have fewer than %d in-sync replicas",
+				addr, c.topic, underReplicated, c.minISR),
+		}
+	}
+	return nil
+}
+
+// checkConsumerLag fetches the committed offsets of the consumer group and
+// the high watermarks of the same partitions, reports the total lag and
+// compares it to the configured maximum.
+func (c *Checker) checkConsumerLag(ctx context.Context, addr string, mechanism sasl.Mechanism, tlsCfg *tls.Config) error {
+	transport := &kafka.Transport{SASL: mechanism, TLS: tlsCfg}
+	defer transport.CloseIdleConnections()
+	client := &kafka.Client{Addr: kafka.TCP(addr), Transport: transport}
+
+	committed, err := client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{GroupID: c.group})
+	if err == nil {
+		err = committed.Error
+	}
+	if err != nil {
+		return classifyError(err, "offset fetch "+c.group, addr)
+	}
+
+	req := &kafka.ListOffsetsRequest{Topics: make(map[string][]kafka.OffsetRequest)}
+	for topic, partitions := range committed.Topics {
+		if c.topic != "" && topic != c.topic {
+			continue
+		}
+		for _, p := range partitions {
+			if p.Error != nil {
+				return classifyError(p.Error, "offset fetch "+c.group, addr)
+			}
+			if p.CommittedOffset >= 0 {
+				req.Topics[topic] = append(req.Topics[topic], kafka.LastOffsetOf(p.Partition))
+			}
+		}
+	}
+	if len(req.Topics) == 0 {
 		return &dephealth.ClassifiedCheckError{
 			Category: dephealth.StatusUnhealthy,
-			Detail:   "no_brokers",
-			Cause:    fmt.Errorf("kafka %s: no brokers in metadata response", addr),
+			Detail:   "consumer_group_missing",
+			Cause:    fmt.Errorf("kafka %s: consumer group %q has no committed offsets", addr, c.group),
 		}
 	}
 
+	latest, err := client.ListOffsets(ctx, req)
+	if err != nil {
+		return classifyError(err, "list offsets", addr)
+	}
+	lag, err := consumerLag(committed.Topics, latest.Topics)
+	if err != nil {
+		return classifyError(err, "list offsets", addr)
+	}
+	dephealth.ReportConsumerLag(ctx, lag)
+	return c.evaluateLag(lag, addr)
+}
+
+// consumerLag sums max(high watermark - committed offset, 0) over the
+// partitions present in latest. Partitions without a committed offset are
+// not part of latest and do not contribute.
+func consumerLag(committed map[string][]kafka.OffsetFetchPartition, latest map[string][]kafka.PartitionOffsets) (int64, error) {
+	var lag int64
+	for topic, offsets := range latest {
+		commits := make(map[int]int64, len(committed[topic]))
+		for _, p := range committed[topic] {
+			commits[p.Partition] = p.CommittedOffset
+		}
+		for _, p := range offsets {
+			if p.Error != nil {
+				return 0, p.Error
+			}
+			if off, ok := commits[p.Partition]; ok && p.LastOffset > off {
+				lag += p.LastOffset - off
+			}
+		}
+	}
+	return lag, nil
+}
+
+// evaluateLag compares the consumer group lag to the configured maximum.
+func (c *Checker) evaluateLag(lag int64, addr string) error {
+	if c.maxLag > 0 && lag > c.maxLag {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "consumer_lag",
+			Cause: fmt.Errorf("kafka %s: consumer group %q lag %d exceeds %d",
+				addr, c.group, lag, c.maxLag),
+		}
+	}
 	return nil
 }
 
+func (c *Checker) saslMechanism() (sasl.Mechanism, error) {
+	switch strings.ToUpper(c.mechanism) {
+	case "":
+		return nil, nil
+	case MechanismPlain:
+		return plain.Mechanism{Username: c.username, Password: c.password}, nil
+	case MechanismSCRAMSHA256:
+		return scram.Mechanism(scram.SHA256, c.username, c.password)
+	case MechanismSCRAMSHA512:
+		return scram.Mechanism(scram.SHA512, c.username, c.password)
+	default:
+		return nil, fmt.Errorf("unsupported SASL mechanism %q", c.mechanism)
+	}
+}
+
+func (c *Checker) useTLS() bool {
+	return c.tlsEnabled || c.tlsConfig != nil || c.tls.CAFile != "" || c.tls.CertFile != ""
+}
+
+func noBrokers(addr string) error {
+	return &dephealth.ClassifiedCheckError{
+		Category: dephealth.StatusUnhealthy,
+		Detail:   "no_brokers",
+		Cause:    fmt.Errorf("kafka %s: no brokers in metadata response", addr),
+	}
+}
+
+// classifyError wraps Kafka errors, classifying SASL and authorization
+// failures as auth_error. Other errors are left for the core classifier.
+func classifyError(err error, op, target string) error {
+	if errors.Is(err, kafka.SASLAuthenticationFailed) ||
+		errors.Is(err, kafka.UnsupportedSASLMechanism) ||
+		errors.Is(err, kafka.IllegalSASLState) ||
+		errors.Is(err, kafka.TopicAuthorizationFailed) ||
+		errors.Is(err, kafka.GroupAuthorizationFailed) ||
+		errors.Is(err, kafka.ClusterAuthorizationFailed) {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusAuthError,
+			Detail:   "auth_error",
+			Cause:    fmt.Errorf("kafka %s %s: %w", op, target, err),
+		}
+	}
+	return fmt.Errorf("kafka %s %s: %w", op, target, err)
+}
+
 // Type returns the dependency type for this checker.
 func (c *Checker) Type() string {
 	return string(dephealth.TypeKafka)
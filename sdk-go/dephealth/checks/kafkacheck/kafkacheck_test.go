@@ -2,9 +2,13 @@ package kafkacheck
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/segmentio/kafka-go"
+
 	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
 )
 
@@ -50,3 +54,191 @@ func TestChecker_Type(t *testing.T) {
 		t.Errorf("Type() = %q, expected %q", got, "kafka")
 	}
 }
+
+func expectDetail(t *testing.T, err error, detail string) {
+	t.Helper()
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected ClassifiedCheckError, got %v", err)
+	}
+	if ce.Category != dephealth.StatusUnhealthy || ce.Detail != detail {
+		t.Errorf("got %s/%s, expected %s/%s (%v)", ce.Category, ce.Detail, dephealth.StatusUnhealthy, detail, err)
+	}
+}
+
+func partition(id int, leader kafka.Broker, isr ...kafka.Broker) kafka.Partition {
+	return kafka.Partition{Topic: "orders", ID: id, Leader: leader, Isr: isr}
+}
+
+func TestChecker_EvaluateTopic(t *testing.T) {
+	b1 := kafka.Broker{ID: 1, Host: "broker-1", Port: 9092}
+	b2 := kafka.Broker{ID: 2, Host: "broker-2", Port: 9092}
+	brokers := []kafka.Broker{b1, b2}
+
+	healthy := &kafka.MetadataResponse{
+		Brokers: brokers,
+		Topics: []kafka.Topic{{Name: "orders", Partitions: []kafka.Partition{
+			partition(0, b1, b1, b2),
+			partition(1, b2, b2, b1),
+		}}},
+	}
+	if err := New(WithTopic("orders"), WithMinISR(2)).evaluateTopic(healthy, "broker-1:9092"); err != nil {
+		t.Errorf("expected healthy topic, got %v", err)
+	}
+
+	missing := &kafka.MetadataResponse{
+		Brokers: brokers,
+		Topics:  []kafka.Topic{{Name: "orders", Error: kafka.UnknownTopicOrPartition}},
+	}
+	expectDetail(t, New(WithTopic("orders")).evaluateTopic(missing, "broker-1:9092"), "topic_missing")
+
+	noLeader := &kafka.MetadataResponse{
+		Brokers: brokers,
+		Topics: []kafka.Topic{{Name: "orders", Partitions: []kafka.Partition{
+			partition(0, b1, b1, b2),
+			partition(1, kafka.Broker{}, b2),
+		}}},
+	}
+	expectDetail(t, New(WithTopic("orders")).evaluateTopic(noLeader, "broker-1:9092"), "no_leader")
+
+	under := &kafka.MetadataResponse{
+		Brokers: brokers,
+		Topics: []kafka.Topic{{Name: "orders", Partitions: []kafka.Partition{
+			partition(0, b1, b1),
+		}}},
+	}
+	if err := New(WithTopic("orders")).evaluateTopic(under, "broker-1:9092"); err != nil {
+		t.Errorf("expected success without ISR threshold, got %v", err)
+	}
+	expectDetail(t, New(WithTopic("orders"), WithMinISR(2)).evaluateTopic(under, "broker-1:9092"), "under_replicated")
+
+	expectDetail(t, New(WithTopic("orders")).evaluateTopic(&kafka.MetadataResponse{}, "broker-1:9092"), "no_brokers")
+
+	denied := &kafka.MetadataResponse{
+		Brokers: brokers,
+		Topics:  []kafka.Topic{{Name: "orders", Error: kafka.TopicAuthorizationFailed}},
+	}
+	var ce *dephealth.ClassifiedCheckError
+	err := New(WithTopic("orders")).evaluateTopic(denied, "broker-1:9092")
+	if !errors.As(err, &ce) || ce.Category != dephealth.StatusAuthError {
+		t.Errorf("expected auth_error for topic authorization failure, got %v", err)
+	}
+}
+
+func TestChecker_SASLMechanism(t *testing.T) {
+	tests := []struct {
+		mechanism string
+		want      string
+	}{
+		{MechanismPlain, "PLAIN"},
+		{MechanismSCRAMSHA256, "SCRAM-SHA-256"},
+		{"scram-sha-512", "SCRAM-SHA-512"},
+	}
+	for _, tt := range tests {
+		m, err := New(WithSASL(tt.mechanism, "app", "secret")).saslMechanism()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.mechanism, err)
+		}
+		if m.Name() != tt.want {
+			t.Errorf("mechanism = %q, expected %q", m.Name(), tt.want)
+		}
+	}
+
+	if m, err := New().saslMechanism(); m != nil || err != nil {
+		t.Errorf("expected no mechanism without SASL, got %v, %v", m, err)
+	}
+
+	err := New(WithSASL("GSSAPI", "app", "secret")).Check(context.Background(),
+		dephealth.Endpoint{Host: "127.0.0.1", Port: "1"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported SASL mechanism") {
+		t.Errorf("expected unsupported mechanism error, got %v", err)
+	}
+}
+
+func TestChecker_Check_Topic_ConnectionRefused(t *testing.T) {
+	err := New(WithTopic("orders")).Check(context.Background(), dephealth.Endpoint{Host: "127.0.0.1", Port: "1"})
+	if err == nil {
+		t.Error("expected error for closed port, got nil")
+	}
+}
+
+func TestConsumerLag(t *testing.T) {
+	committed := map[string][]kafka.OffsetFetchPartition{
+		"orders": {
+			{Partition: 0, CommittedOffset: 90},
+			{Partition: 1, CommittedOffset: 50},
+			{Partition: 2, CommittedOffset: 200},
+		},
+		"payments": {{Partition: 0, CommittedOffset: 10}},
+	}
+	latest := map[string][]kafka.PartitionOffsets{
+		"orders": {
+			{Partition: 0, LastOffset: 100},
+			{Partition: 1, LastOffset: 80},
+			{Partition: 2, LastOffset: 150}, // retention moved past the commit
+		},
+		"payments": {{Partition: 0, LastOffset: 15}},
+	}
+	lag, err := consumerLag(committed, latest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lag != 45 {
+		t.Errorf("lag = %d, expected 45", lag)
+	}
+
+	latest["payments"][0].Error = kafka.NotLeaderForPartition
+	if _, err := consumerLag(committed, latest); !errors.Is(err, kafka.NotLeaderForPartition) {
+		t.Errorf("expected partition error, got %v", err)
+	}
+}
+
+func TestChecker_EvaluateLag(t *testing.T) {
+	if err := New(WithConsumerGroup("billing", 0)).evaluateLag(1e6, "b:9092"); err != nil {
+		t.Errorf("expected no threshold without maxLag, got %v", err)
+	}
+	if err := New(WithConsumerGroup("billing", 100)).evaluateLag(100, "b:9092"); err != nil {
+		t.Errorf("expected lag at the threshold to pass, got %v", err)
+	}
+
+	err := New(WithConsumerGroup("billing", 100)).evaluateLag(101, "b:9092")
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) || ce.Category != dephealth.StatusUnhealthy || ce.Detail != "consumer_lag" {
+		t.Fatalf("expected unhealthy/consumer_lag, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"billing" lag 101 exceeds 100`) {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	skip := true
+	checker, ok := NewFromConfig(&dephealth.DependencyConfig{
+		KafkaSASLMechanism: MechanismSCRAMSHA512,
+		KafkaUsername:      "app",
+		KafkaPassword:      "secret",
+		KafkaTLSCAFile:     "ca.pem",
+		KafkaTLSCertFile:   "client.pem",
+		KafkaTLSKeyFile:    "client-key.pem",
+		KafkaTLSSkipVerify: &skip,
+		KafkaTopic:         "orders",
+		KafkaMinISR:        2,
+		KafkaConsumerGroup: "billing",
+		KafkaMaxLag:        1000,
+	}).(*Checker)
+	if !ok {
+		t.Fatal("expected *Checker")
+	}
+	if checker.mechanism != MechanismSCRAMSHA512 || checker.username != "app" || checker.password != "secret" {
+		t.Errorf("unexpected SASL config: %q/%q", checker.mechanism, checker.username)
+	}
+	if !checker.useTLS() || !checker.tls.SkipVerify || checker.tls.CertFile != "client.pem" {
+		t.Errorf("unexpected TLS config: %+v", checker.tls)
+	}
+	if checker.topic != "orders" || checker.minISR != 2 {
+		t.Errorf("topic = %q, minISR = %d", checker.topic, checker.minISR)
+	}
+	if checker.group != "billing" || checker.maxLag != 1000 {
+		t.Errorf("group = %q, maxLag = %d", checker.group, checker.maxLag)
+	}
+}
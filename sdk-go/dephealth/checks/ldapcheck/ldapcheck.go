@@ -23,6 +23,7 @@ import (
 	"github.com/go-ldap/ldap/v3"
 
 	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
 )
 
 // defaultDialTimeout is used for standalone LDAP connections to ensure
@@ -75,12 +76,16 @@ type Checker struct {
 	checkMethod   CheckMethod // default: root_dse
 	bindDN        string
 	bindPassword  string
+	passwordProv  dephealth.CredentialProvider
 	baseDN        string
 	searchFilter  string // default: (objectClass=*)
 	searchScope   SearchScope
 	useTLS        bool // true for ldaps:// scheme
 	startTLS      bool // true for StartTLS
 	tlsSkipVerify bool
+	tlsConfig     *tls.Config     // base config from WithTLSConfig
+	tls           tlsutil.Options // CA and client certificate files
+	tlsSource     *tlsutil.Source
 }
 
 // WithConn sets an existing LDAP connection for pool mode.
@@ -111,6 +116,14 @@ func WithBindPassword(password string) Option {
 	}
 }
 
+// WithBindPasswordProvider obtains the bind password from p before every
+// check. It takes precedence over WithBindPassword.
+func WithBindPasswordProvider(p dephealth.CredentialProvider) Option {
+	return func(c *Checker) {
+		c.passwordProv = p
+	}
+}
+
 // WithBaseDN sets the base DN for search method.
 func WithBaseDN(baseDN string) Option {
 	return func(c *Checker) {
@@ -153,6 +166,34 @@ func WithTLSSkipVerify(skip bool) Option {
 	}
 }
 
+// WithTLSConfig sets the base TLS configuration for LDAPS or StartTLS and
+// enables LDAPS unless StartTLS is used. The files of WithTLSCA and
+// WithTLSClientCert are loaded on top of a copy.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithTLSCA sets the PEM CA bundle used to verify the server certificate
+// and enables LDAPS unless StartTLS is used. The file is read again when it
+// changes.
+func WithTLSCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tls.CAFile = caFile
+	}
+}
+
+// WithTLSClientCert sets the client certificate and key for mTLS and
+// enables LDAPS unless StartTLS is used. The files are read again when they
+// change.
+func WithTLSClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tls.CertFile = certFile
+		c.tls.KeyFile = keyFile
+	}
+}
+
 // New creates a new LDAP health checker with the given options.
 func New(opts ...Option) *Checker {
 	c := &Checker{
@@ -163,6 +204,10 @@ func New(opts ...Option) *Checker {
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.tlsSource = tlsutil.NewSource(c.tlsConfig, c.tls)
+	if !c.tlsSource.IsZero() && !c.startTLS {
+		c.useTLS = true
+	}
 	return c
 }
 
@@ -179,6 +224,9 @@ func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
 	if dc.LDAPBindPassword != "" {
 		opts = append(opts, WithBindPassword(dc.LDAPBindPassword))
 	}
+	if dc.LDAPBindPasswordProvider != nil {
+		opts = append(opts, WithBindPasswordProvider(dc.LDAPBindPasswordProvider))
+	}
 	if dc.LDAPBaseDN != "" {
 		opts = append(opts, WithBaseDN(dc.LDAPBaseDN))
 	}
@@ -191,9 +239,21 @@ func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
 	if dc.LDAPStartTLS != nil && *dc.LDAPStartTLS {
 		opts = append(opts, WithStartTLS(true))
 	}
+	if dc.TLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.TLSSkipVerify))
+	}
 	if dc.LDAPTLSSkipVerify != nil && *dc.LDAPTLSSkipVerify {
 		opts = append(opts, WithTLSSkipVerify(true))
 	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
 
 	// Detect ldaps:// scheme from URL.
 	if dc.URL != "" && strings.HasPrefix(strings.ToLower(dc.URL), "ldaps://") {
@@ -205,38 +265,70 @@ func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
 
 // Check performs an LDAP health check against the given endpoint.
 func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
+	password, err := dephealth.ResolveCredential(ctx, c.passwordProv, c.bindPassword)
+	if err != nil {
+		return err
+	}
 	if c.conn != nil {
-		return c.checkWithConn(c.conn)
+		return c.checkWithConn(c.conn, password)
 	}
-	return c.checkStandalone(ctx, endpoint)
+	return c.checkStandalone(ctx, endpoint, password)
 }
 
-func (c *Checker) checkStandalone(ctx context.Context, endpoint dephealth.Endpoint) error {
+func (c *Checker) checkStandalone(ctx context.Context, endpoint dephealth.Endpoint, password string) error {
 	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
 
-	conn, err := c.dial(ctx, addr)
+	var tlsCfg *tls.Config
+	if c.useTLS || c.startTLS {
+		var err error
+		if tlsCfg, err = c.clientTLSConfig(endpoint.Host); err != nil {
+			return fmt.Errorf("ldap %s: %w", addr, err)
+		}
+	}
+
+	conn, err := c.dial(ctx, addr, tlsCfg)
 	if err != nil {
-		return classifyError(err, addr)
+		return classifyError(dephealth.WrapHandshakeEOF(err), addr)
 	}
 	defer func() { _ = conn.Close() }()
 
 	if c.startTLS {
-		tlsCfg := &tls.Config{
-			ServerName:         endpoint.Host,
-			InsecureSkipVerify: c.tlsSkipVerify, //nolint:gosec // user-configurable
-		}
 		if err := conn.StartTLS(tlsCfg); err != nil {
 			return classifyError(err, addr)
 		}
 	}
+	if state, ok := conn.TLSConnectionState(); ok {
+		dephealth.ReportTLSConnectionState(ctx, &state)
+	}
 
-	if err := c.checkWithConn(conn); err != nil {
+	if err := c.checkWithConn(conn, password); err != nil {
 		return classifyError(err, addr)
 	}
 	return nil
 }
 
-func (c *Checker) dial(ctx context.Context, addr string) (*ldap.Conn, error) {
+// clientTLSConfig returns the TLS config for LDAPS or StartTLS, with the
+// current certificate files.
+func (c *Checker) clientTLSConfig(host string) (*tls.Config, error) {
+	if c.tlsSkipVerify {
+		tlsSkipVerifyWarnOnce.Do(func() {
+			slog.Warn("dephealth: LDAP checker has TLS certificate verification disabled (InsecureSkipVerify=true)")
+		})
+	}
+	tlsCfg, err := c.tlsSource.Config()
+	if err != nil {
+		return nil, err
+	}
+	if c.tlsSkipVerify {
+		tlsCfg.InsecureSkipVerify = true //nolint:gosec // user-configurable
+	}
+	if tlsCfg.ServerName == "" {
+		tlsCfg.ServerName = host
+	}
+	return tlsCfg, nil
+}
+
+func (c *Checker) dial(ctx context.Context, addr string, tlsCfg *tls.Config) (*ldap.Conn, error) {
 	// Use a dial timeout shorter than the context timeout for classifiable errors.
 	dialTimeout := defaultDialTimeout
 	if deadline, ok := ctx.Deadline(); ok {
@@ -249,14 +341,6 @@ func (c *Checker) dial(ctx context.Context, addr string) (*ldap.Conn, error) {
 	dialer := &net.Dialer{Timeout: dialTimeout}
 
 	if c.useTLS {
-		if c.tlsSkipVerify {
-			tlsSkipVerifyWarnOnce.Do(func() {
-				slog.Warn("dephealth: LDAP checker has TLS certificate verification disabled (InsecureSkipVerify=true)")
-			})
-		}
-		tlsCfg := &tls.Config{
-			InsecureSkipVerify: c.tlsSkipVerify, //nolint:gosec // user-configurable
-		}
 		return ldap.DialURL("ldaps://"+addr,
 			ldap.DialWithDialer(dialer),
 			ldap.DialWithTLSConfig(tlsCfg),
@@ -268,16 +352,18 @@ func (c *Checker) dial(ctx context.Context, addr string) (*ldap.Conn, error) {
 	)
 }
 
-func (c *Checker) checkWithConn(conn *ldap.Conn) error {
+// checkWithConn runs the configured check method; password is the bind
+// password for simple_bind and search.
+func (c *Checker) checkWithConn(conn *ldap.Conn, password string) error {
 	switch c.checkMethod {
 	case MethodAnonymousBind:
 		return conn.UnauthenticatedBind("")
 	case MethodSimpleBind:
-		return conn.Bind(c.bindDN, c.bindPassword)
+		return conn.Bind(c.bindDN, password)
 	case MethodRootDSE:
 		return c.searchRootDSE(conn)
 	case MethodSearch:
-		return c.searchWithConfig(conn)
+		return c.searchWithConfig(conn, password)
 	default:
 		return c.searchRootDSE(conn)
 	}
@@ -299,10 +385,10 @@ func (c *Checker) searchRootDSE(conn *ldap.Conn) error {
 	return err
 }
 
-func (c *Checker) searchWithConfig(conn *ldap.Conn) error {
+func (c *Checker) searchWithConfig(conn *ldap.Conn, password string) error {
 	// Bind before search if credentials are provided.
 	if c.bindDN != "" {
-		if err := conn.Bind(c.bindDN, c.bindPassword); err != nil {
+		if err := conn.Bind(c.bindDN, password); err != nil {
 			return err
 		}
 	}
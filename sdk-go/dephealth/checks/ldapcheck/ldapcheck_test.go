@@ -2,9 +2,15 @@ package ldapcheck
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/checktest"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlstest"
 )
 
 func TestChecker_Type(t *testing.T) {
@@ -79,6 +85,37 @@ func TestChecker_Check_ConnectionRefused(t *testing.T) {
 	}
 }
 
+func TestChecker_Check_ReportsTLSCertExpiry(t *testing.T) {
+	leaf := tlstest.NewCA(t).Issue(t, time.Now().Add(48*time.Hour).Truncate(time.Second), "127.0.0.1")
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{leaf.TLSCertificate(t)},
+		MinVersion:   tls.VersionTLS12,
+	})
+	if err != nil {
+		t.Fatalf("failed to start TLS listener: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	// Complete the TLS handshake and hang up: the LDAP operation fails, but
+	// the server certificate has already been captured.
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	checker := New(WithTLS(true), WithTLSSkipVerify(true))
+	got := checktest.CertExpiry(t, checker, dephealth.TypeLDAP, dephealth.Endpoint{Host: host, Port: port})
+	if !got.Equal(leaf.Cert.NotAfter) {
+		t.Errorf("TLSCertExpiry = %v, expected %v", got, leaf.Cert.NotAfter)
+	}
+}
+
 func TestChecker_Check_ContextCanceled(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -143,6 +180,26 @@ func TestNewFromConfig_SimpleBind(t *testing.T) {
 	}
 }
 
+func TestChecker_Check_PasswordProviderError(t *testing.T) {
+	failing := dephealth.CredentialFunc(func(context.Context) (string, error) {
+		return "", errors.New("secret not mounted")
+	})
+	dc := &dephealth.DependencyConfig{
+		URL:                      "ldap://127.0.0.1:1",
+		LDAPCheckMethod:          "simple_bind",
+		LDAPBindDN:               "cn=admin,dc=test,dc=local",
+		LDAPBindPasswordProvider: failing,
+	}
+	checker := NewFromConfig(dc)
+
+	// The provider fails before the connection is attempted.
+	err := checker.Check(context.Background(), dephealth.Endpoint{Host: "127.0.0.1", Port: "1"})
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) || ce.Detail != "credential_error" {
+		t.Errorf("expected credential_error, got %v", err)
+	}
+}
+
 func TestNewFromConfig_Search(t *testing.T) {
 	dc := &dephealth.DependencyConfig{
 		URL:              "ldap://localhost:389",
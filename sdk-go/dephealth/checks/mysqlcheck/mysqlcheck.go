@@ -7,17 +7,43 @@ package mysqlcheck
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql" // MySQL driver
+	"github.com/go-sql-driver/mysql"
 
 	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
 )
 
+// Server roles accepted by WithRole.
+const (
+	RolePrimary = "primary"
+	RoleReplica = "replica"
+)
+
+// SSL modes accepted by WithSSLMode. The libpq spellings disable, prefer,
+// require, verify-ca and verify-full are accepted as aliases.
+const (
+	SSLModeDisabled       = "disabled"
+	SSLModePreferred      = "preferred"
+	SSLModeRequired       = "required"
+	SSLModeVerifyCA       = "verify_ca"
+	SSLModeVerifyIdentity = "verify_identity"
+)
+
+// errParseError is the MySQL error number for a syntax error. Servers older
+// than MySQL 8.0.22 reject SHOW REPLICA STATUS with it.
+const errParseError = 1064
+
 var _ dephealth.HealthChecker = (*Checker)(nil)
 
 func init() {
@@ -31,10 +57,31 @@ type Option func(*Checker)
 // Supports two modes:
 //   - Standalone: creates a new connection per check using DSN built from endpoint
 //   - Pool: uses an existing *sql.DB connection pool
+//
+// In standalone mode without WithDSN, the connection is configured per
+// endpoint from its host/port and the configured credentials, database and
+// SSL settings.
+//
+// With WithRole, the check compares @@global.read_only with the expected
+// role. For replicas, and whenever WithMaxReplicationLag is set, it also
+// inspects SHOW REPLICA STATUS.
 type Checker struct {
-	db    *sql.DB // nil = standalone, non-nil = pool mode
-	dsn   string  // custom DSN for standalone mode (overrides endpoint-based DSN)
-	query string  // health check query
+	db     *sql.DB // nil = standalone, non-nil = pool mode
+	dsn    string  // custom DSN for standalone mode (overrides endpoint-based DSN)
+	query  string  // health check query
+	role   string  // expected role, "" = any
+	maxLag time.Duration
+
+	base        *mysql.Config // defaults from the dependency URL, nil = none
+	user        string
+	password    string
+	database    string
+	sslMode     string
+	tlsCAFile   string
+	tlsCertFile string
+	tlsKeyFile  string
+	tlsConfig   *tls.Config // base TLS config, nil = defaults
+	appName     string
 }
 
 // WithDB sets an existing connection pool for pool mode.
@@ -52,6 +99,67 @@ func WithDSN(dsn string) Option {
 	}
 }
 
+// WithCredentials sets the user and password for standalone mode.
+func WithCredentials(user, password string) Option {
+	return func(c *Checker) {
+		c.user = user
+		c.password = password
+	}
+}
+
+// WithDatabase sets the default database for standalone mode.
+func WithDatabase(database string) Option {
+	return func(c *Checker) {
+		c.database = database
+	}
+}
+
+// WithSSLMode sets the TLS mode for standalone mode: SSLModeDisabled,
+// SSLModePreferred, SSLModeRequired (encrypt without verification),
+// SSLModeVerifyCA (verify the chain only) or SSLModeVerifyIdentity (verify the
+// chain and the endpoint host name).
+func WithSSLMode(mode string) Option {
+	return func(c *Checker) {
+		c.sslMode = mode
+	}
+}
+
+// WithTLSCA sets the PEM CA bundle used to verify the server certificate.
+// Without WithSSLMode, it implies SSLModeVerifyIdentity.
+func WithTLSCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tlsCAFile = caFile
+	}
+}
+
+// WithTLSClientCert sets the PEM client certificate and key for mTLS. The
+// files are read on every check, so rotated certificates are picked up.
+// Without WithSSLMode, it implies SSLModeVerifyIdentity.
+func WithTLSClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tlsCertFile = certFile
+		c.tlsKeyFile = keyFile
+	}
+}
+
+// WithTLSConfig sets the base TLS configuration of standalone connections.
+// The SSL mode and the CA and client certificate files take precedence over
+// the corresponding fields of cfg. Without WithSSLMode, it implies
+// SSLModeVerifyIdentity, or SSLModeRequired when cfg skips verification.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithApplicationName sets the program_name connection attribute, shown in
+// performance_schema.session_connect_attrs.
+func WithApplicationName(name string) Option {
+	return func(c *Checker) {
+		c.appName = name
+	}
+}
+
 // WithQuery sets the health check SQL query (default "SELECT 1").
 // The query must be a read-only SELECT statement to prevent unintended side effects.
 func WithQuery(query string) Option {
@@ -60,6 +168,24 @@ func WithQuery(query string) Option {
 	}
 }
 
+// WithRole requires @@global.read_only to match the role: off for
+// RolePrimary, on for RoleReplica (detail wrong_role). A replica must also
+// have replication configured and running (detail replication_stopped).
+func WithRole(role string) Option {
+	return func(c *Checker) {
+		c.role = role
+	}
+}
+
+// WithMaxReplicationLag fails the check with detail replication_lag when
+// Seconds_Behind_Source exceeds d. A server without replica status is not
+// checked unless WithRole(RoleReplica) is set.
+func WithMaxReplicationLag(d time.Duration) Option {
+	return func(c *Checker) {
+		c.maxLag = d
+	}
+}
+
 // New creates a new MySQL health checker with the given options.
 func New(opts ...Option) *Checker {
 	c := &Checker{
@@ -76,15 +202,196 @@ func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
 	var opts []Option
 	if dc.URL != "" {
 		if dsn := URLToDSN(dc.URL); dsn != "" {
-			opts = append(opts, WithDSN(dsn))
+			opts = append(opts, withBaseDSN(dsn))
 		}
 	}
+	if dc.MySQLUser != "" || dc.MySQLPassword != "" {
+		opts = append(opts, withCredentialOverrides(dc.MySQLUser, dc.MySQLPassword))
+	}
+	if dc.MySQLDatabase != "" {
+		opts = append(opts, WithDatabase(dc.MySQLDatabase))
+	}
+	if dc.MySQLSSLMode != "" {
+		opts = append(opts, WithSSLMode(dc.MySQLSSLMode))
+	} else if dc.TLSSkipVerify != nil && *dc.TLSSkipVerify {
+		// Encryption without verification.
+		opts = append(opts, WithSSLMode(SSLModeRequired))
+	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
+	if dc.MySQLTLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.MySQLTLSCAFile))
+	}
+	if dc.MySQLApplicationName != "" {
+		opts = append(opts, WithApplicationName(dc.MySQLApplicationName))
+	}
 	if dc.MySQLQuery != "" {
 		opts = append(opts, WithQuery(dc.MySQLQuery))
 	}
+	if dc.MySQLRole != "" {
+		opts = append(opts, WithRole(dc.MySQLRole))
+	}
+	if dc.MySQLMaxReplicationLag > 0 {
+		opts = append(opts, WithMaxReplicationLag(dc.MySQLMaxReplicationLag))
+	}
 	return New(opts...)
 }
 
+// withBaseDSN takes the credentials, database and parameters of a DSN as
+// defaults for the per-endpoint configuration; its address is ignored. A DSN
+// that cannot be parsed is used verbatim.
+func withBaseDSN(dsn string) Option {
+	return func(c *Checker) {
+		cfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			c.dsn = dsn
+			return
+		}
+		c.base = cfg
+	}
+}
+
+// withCredentialOverrides replaces the user and keeps the URL password
+// unless a password is given.
+func withCredentialOverrides(user, password string) Option {
+	return func(c *Checker) {
+		if user != "" {
+			c.user = user
+		}
+		if password != "" {
+			c.password = password
+		}
+	}
+}
+
+// configFor returns the driver configuration used to check the endpoint in
+// standalone mode.
+func (c *Checker) configFor(endpoint dephealth.Endpoint) (*mysql.Config, error) {
+	cfg := mysql.NewConfig()
+	if c.base != nil {
+		cfg = c.base.Clone()
+	}
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(endpoint.Host, endpoint.Port)
+	if c.user != "" {
+		cfg.User = c.user
+	}
+	if c.password != "" {
+		cfg.Passwd = c.password
+	}
+	if c.database != "" {
+		cfg.DBName = c.database
+	}
+	if c.appName != "" {
+		attr := "program_name:" + c.appName
+		if cfg.ConnectionAttributes != "" {
+			attr = cfg.ConnectionAttributes + "," + attr
+		}
+		cfg.ConnectionAttributes = attr
+	}
+
+	mode, err := normalizeSSLMode(c.sslMode)
+	if err != nil {
+		return nil, err
+	}
+	if mode == "" {
+		mode = c.impliedSSLMode()
+	}
+	opts := tlsutil.Options{CAFile: c.tlsCAFile, CertFile: c.tlsCertFile, KeyFile: c.tlsKeyFile}
+	switch mode {
+	case "":
+		// keep the URL setting
+	case SSLModeDisabled:
+		cfg.TLS, cfg.TLSConfig = nil, "false"
+	case SSLModePreferred:
+		cfg.TLS, cfg.TLSConfig = nil, "preferred"
+	case SSLModeRequired:
+		opts.SkipVerify = true
+		cfg.TLS, err = opts.BuildOn(c.tlsConfig)
+	case SSLModeVerifyCA:
+		cfg.TLS, err = verifyCAConfig(c.tlsConfig, opts)
+	case SSLModeVerifyIdentity:
+		if c.tlsConfig == nil || c.tlsConfig.ServerName == "" {
+			opts.ServerName = endpoint.Host
+		}
+		cfg.TLS, err = opts.BuildOn(c.tlsConfig)
+		if err == nil {
+			cfg.TLS.InsecureSkipVerify = false
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// impliedSSLMode returns the SSL mode implied by the TLS options, "" = none.
+func (c *Checker) impliedSSLMode() string {
+	switch {
+	case c.tlsConfig != nil && c.tlsConfig.InsecureSkipVerify && c.tlsCAFile == "":
+		return SSLModeRequired
+	case c.tlsConfig != nil || c.tlsCAFile != "" || c.tlsCertFile != "":
+		return SSLModeVerifyIdentity
+	default:
+		return ""
+	}
+}
+
+// normalizeSSLMode maps MySQL ssl-mode values and their libpq spellings to
+// the SSLMode constants.
+func normalizeSSLMode(mode string) (string, error) {
+	switch strings.ReplaceAll(strings.ToLower(mode), "-", "_") {
+	case "":
+		return "", nil
+	case "disabled", "disable":
+		return SSLModeDisabled, nil
+	case "preferred", "prefer":
+		return SSLModePreferred, nil
+	case "required", "require":
+		return SSLModeRequired, nil
+	case "verify_ca":
+		return SSLModeVerifyCA, nil
+	case "verify_identity", "verify_full":
+		return SSLModeVerifyIdentity, nil
+	default:
+		return "", fmt.Errorf("unsupported SSL mode %q", mode)
+	}
+}
+
+// verifyCAConfig verifies the server certificate chain against the CA
+// bundle (or the roots of base, or system roots) without checking the host
+// name.
+func verifyCAConfig(base *tls.Config, opts tlsutil.Options) (*tls.Config, error) {
+	opts.SkipVerify = true
+	cfg, err := opts.BuildOn(base)
+	if err != nil {
+		return nil, err
+	}
+	roots := cfg.RootCAs
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("server presented no certificate")
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range cs.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+		return err
+	}
+	return cfg, nil
+}
+
 // Check performs a health check against the MySQL endpoint.
 // In pool mode, uses the existing *sql.DB. In standalone mode, opens a new connection.
 func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
@@ -102,7 +409,10 @@ func (c *Checker) checkPool(ctx context.Context) error {
 	if err != nil {
 		return classifyError(err, "pool")
 	}
-	return rows.Close()
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	return c.checkReplication(ctx, c.db, "pool")
 }
 
 func (c *Checker) checkStandalone(ctx context.Context, endpoint dephealth.Endpoint) error {
@@ -110,12 +420,7 @@ func (c *Checker) checkStandalone(ctx context.Context, endpoint dephealth.Endpoi
 		return err
 	}
 
-	dsn := c.dsn
-	if dsn == "" {
-		dsn = fmt.Sprintf("tcp(%s)/", net.JoinHostPort(endpoint.Host, endpoint.Port))
-	}
-
-	db, err := sql.Open("mysql", dsn)
+	db, err := c.open(endpoint)
 	if err != nil {
 		return fmt.Errorf("mysql open %s: %w", endpoint.Host, err)
 	}
@@ -125,7 +430,168 @@ func (c *Checker) checkStandalone(ctx context.Context, endpoint dephealth.Endpoi
 	if err != nil {
 		return classifyError(err, endpoint.Host)
 	}
-	return rows.Close()
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	return c.checkReplication(ctx, db, endpoint.Host)
+}
+
+// open returns a connection pool for a standalone check of the endpoint.
+func (c *Checker) open(endpoint dephealth.Endpoint) (*sql.DB, error) {
+	if c.dsn != "" {
+		return sql.Open("mysql", c.dsn)
+	}
+	cfg, err := c.configFor(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// replicaStatus holds the fields of one SHOW REPLICA STATUS row (one per
+// replication channel).
+type replicaStatus struct {
+	ioRunning  string
+	sqlRunning string
+	lag        sql.NullInt64 // Seconds_Behind_Source, NULL when not replicating
+}
+
+// checkReplication verifies the read-only state and replica status when
+// configured.
+func (c *Checker) checkReplication(ctx context.Context, db *sql.DB, target string) error {
+	if c.role == "" && c.maxLag <= 0 {
+		return nil
+	}
+
+	if c.role != "" {
+		var readOnly bool
+		if err := db.QueryRowContext(ctx, "SELECT @@global.read_only").Scan(&readOnly); err != nil {
+			return classifyError(err, target)
+		}
+		role := RolePrimary
+		if readOnly {
+			role = RoleReplica
+		}
+		if role != c.role {
+			return &dephealth.ClassifiedCheckError{
+				Category: dephealth.StatusUnhealthy,
+				Detail:   "wrong_role",
+				Cause:    fmt.Errorf("mysql %s: read_only is %t, expected %s", target, readOnly, c.role),
+			}
+		}
+		if role == RolePrimary && c.maxLag <= 0 {
+			return nil
+		}
+	}
+
+	statuses, err := showReplicaStatus(ctx, db)
+	if err != nil {
+		return classifyError(err, target)
+	}
+	return c.evaluateReplicaStatus(ctx, statuses, target)
+}
+
+// evaluateReplicaStatus checks that every replication channel is running and
+// within the lag threshold, and reports the largest lag.
+func (c *Checker) evaluateReplicaStatus(ctx context.Context, statuses []replicaStatus, target string) error {
+	if len(statuses) == 0 {
+		if c.role != RoleReplica {
+			return nil
+		}
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "replication_stopped",
+			Cause:    fmt.Errorf("mysql %s: replication is not configured", target),
+		}
+	}
+
+	var lag time.Duration
+	for _, st := range statuses {
+		if st.ioRunning != "Yes" || st.sqlRunning != "Yes" || !st.lag.Valid {
+			// The lag of a stopped channel is unknown and is not reported.
+			return &dephealth.ClassifiedCheckError{
+				Category: dephealth.StatusUnhealthy,
+				Detail:   "replication_stopped",
+				Cause: fmt.Errorf("mysql %s: replication stopped (IO thread %s, SQL thread %s)",
+					target, st.ioRunning, st.sqlRunning),
+			}
+		}
+		lag = max(lag, time.Duration(st.lag.Int64)*time.Second)
+	}
+
+	dephealth.ReportReplicationLag(ctx, lag)
+	if c.maxLag > 0 && lag > c.maxLag {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "replication_lag",
+			Cause:    fmt.Errorf("mysql %s: replication lag %s exceeds %s", target, lag, c.maxLag),
+		}
+	}
+	return nil
+}
+
+// showReplicaStatus runs SHOW REPLICA STATUS, falling back to SHOW SLAVE
+// STATUS on servers that do not support the new syntax. Columns are looked
+// up by both their current and legacy names.
+func showReplicaStatus(ctx context.Context, db *sql.DB) ([]replicaStatus, error) {
+	rows, err := db.QueryContext(ctx, "SHOW REPLICA STATUS")
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) && myErr.Number == errParseError {
+		rows, err = db.QueryContext(ctx, "SHOW SLAVE STATUS")
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(cols))
+	for i, col := range cols {
+		index[col] = i
+	}
+	column := func(names ...string) int {
+		for _, name := range names {
+			if i, ok := index[name]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+	ioCol := column("Replica_IO_Running", "Slave_IO_Running")
+	sqlCol := column("Replica_SQL_Running", "Slave_SQL_Running")
+	lagCol := column("Seconds_Behind_Source", "Seconds_Behind_Master")
+	if ioCol < 0 || sqlCol < 0 || lagCol < 0 {
+		return nil, fmt.Errorf("unexpected replica status columns %v", cols)
+	}
+
+	var statuses []replicaStatus
+	values := make([]sql.NullString, len(cols))
+	dest := make([]any, len(cols))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		st := replicaStatus{ioRunning: values[ioCol].String, sqlRunning: values[sqlCol].String}
+		if v := values[lagCol]; v.Valid {
+			n, err := strconv.ParseInt(v.String, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid replication lag %q: %w", v.String, err)
+			}
+			st.lag = sql.NullInt64{Int64: n, Valid: true}
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, rows.Err()
 }
 
 // validateQuery checks that the health check query is a SELECT statement.
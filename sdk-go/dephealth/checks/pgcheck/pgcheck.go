@@ -7,16 +7,41 @@ package pgcheck
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"strings"
+	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib" // PostgreSQL driver
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib" // PostgreSQL driver
 
 	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
 )
 
+// Server roles accepted by WithRole.
+const (
+	RolePrimary = "primary"
+	RoleReplica = "replica"
+)
+
+// replicationQuery reports whether the server is a replica and its replay lag
+// in seconds. A streaming replica that has replayed everything it received
+// has no lag, even if the primary has been idle since the last replayed
+// transaction. Without a streaming WAL receiver the lag is the age of the
+// last replayed transaction, so a replica that stopped replicating keeps
+// falling behind. Without pg_read_all_stats the receiver status is hidden
+// and a running receiver counts as streaming.
+const replicationQuery = `SELECT pg_is_in_recovery(),
+	CASE WHEN NOT pg_is_in_recovery() THEN 0
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn()
+		AND EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE COALESCE(status, 'streaming') = 'streaming') THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)::float8 END`
+
 var _ dephealth.HealthChecker = (*Checker)(nil)
 
 func init() {
@@ -30,10 +55,29 @@ type Option func(*Checker)
 // Supports two modes:
 //   - Standalone: creates a new connection per check using DSN built from endpoint
 //   - Pool: uses an existing *sql.DB connection pool
+//
+// In standalone mode without WithDSN, the DSN is built per endpoint from its
+// host/port and the configured credentials, database and SSL settings.
+//
+// With WithRole or WithMaxReplicationLag, the check also queries
+// pg_is_in_recovery() and the replay lag after the health check query.
 type Checker struct {
-	db    *sql.DB // nil = standalone, non-nil = pool mode
-	dsn   string  // custom DSN for standalone mode (overrides endpoint-based DSN)
-	query string  // health check query
+	db     *sql.DB // nil = standalone, non-nil = pool mode
+	dsn    string  // custom DSN for standalone mode (overrides endpoint-based DSN)
+	query  string  // health check query
+	role   string  // expected role, "" = any
+	maxLag time.Duration
+
+	user        string
+	password    string
+	database    string
+	sslMode     string
+	tlsCAFile   string
+	tlsCertFile string
+	tlsKeyFile  string
+	tlsConfig   *tls.Config // base TLS config, nil = built by the driver
+	appName     string
+	baseURL     *url.URL // dependency URL; only its host is replaced per endpoint
 }
 
 // WithDB sets an existing connection pool for pool mode.
@@ -51,6 +95,67 @@ func WithDSN(dsn string) Option {
 	}
 }
 
+// WithCredentials sets the user and password for standalone mode.
+func WithCredentials(user, password string) Option {
+	return func(c *Checker) {
+		c.user = user
+		c.password = password
+	}
+}
+
+// WithDatabase sets the database for standalone mode. It defaults to the
+// path of the dependency URL, or "postgres" when there is no URL.
+func WithDatabase(database string) Option {
+	return func(c *Checker) {
+		c.database = database
+	}
+}
+
+// WithSSLMode sets the libpq sslmode for standalone mode (disable, allow,
+// prefer, require, verify-ca, verify-full).
+func WithSSLMode(mode string) Option {
+	return func(c *Checker) {
+		c.sslMode = mode
+	}
+}
+
+// WithTLSCA sets the PEM CA bundle (sslrootcert) used to verify the server
+// certificate. Without WithSSLMode, it implies sslmode=verify-full.
+func WithTLSCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tlsCAFile = caFile
+	}
+}
+
+// WithTLSClientCert sets the PEM client certificate and key (sslcert,
+// sslkey) for mTLS. The files are read on every check, so rotated
+// certificates are picked up. Without WithSSLMode, it implies
+// sslmode=verify-full.
+func WithTLSClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tlsCertFile = certFile
+		c.tlsKeyFile = keyFile
+	}
+}
+
+// WithTLSConfig sets the base TLS configuration of standalone connections.
+// The verification behaviour of sslmode and the CA and client certificate
+// files take precedence over the corresponding fields of cfg. Without
+// WithSSLMode, it implies sslmode=verify-full, or sslmode=require when cfg
+// skips verification.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithApplicationName sets application_name for standalone connections.
+func WithApplicationName(name string) Option {
+	return func(c *Checker) {
+		c.appName = name
+	}
+}
+
 // WithQuery sets the health check SQL query (default "SELECT 1").
 // The query must be a read-only SELECT statement to prevent unintended side effects.
 func WithQuery(query string) Option {
@@ -59,6 +164,25 @@ func WithQuery(query string) Option {
 	}
 }
 
+// WithRole requires the server to have the given role (RolePrimary or
+// RoleReplica). A mismatch, e.g. after a failover, fails the check with
+// detail wrong_role.
+func WithRole(role string) Option {
+	return func(c *Checker) {
+		c.role = role
+	}
+}
+
+// WithMaxReplicationLag fails the check with detail replication_lag when the
+// server is a replica whose replay lag exceeds d. The measured lag of a
+// replica is reported to the scheduler whenever a role or lag check is
+// configured.
+func WithMaxReplicationLag(d time.Duration) Option {
+	return func(c *Checker) {
+		c.maxLag = d
+	}
+}
+
 // New creates a new PostgreSQL health checker with the given options.
 func New(opts ...Option) *Checker {
 	c := &Checker{
@@ -74,14 +198,188 @@ func New(opts ...Option) *Checker {
 func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
 	var opts []Option
 	if dc.URL != "" {
-		opts = append(opts, WithDSN(dc.URL))
+		opts = append(opts, withURL(dc.URL))
+	}
+	if dc.PostgresUser != "" || dc.PostgresPassword != "" {
+		opts = append(opts, withCredentialOverrides(dc.PostgresUser, dc.PostgresPassword))
+	}
+	if dc.PostgresDatabase != "" {
+		opts = append(opts, WithDatabase(dc.PostgresDatabase))
+	}
+	if dc.PostgresSSLMode != "" {
+		opts = append(opts, WithSSLMode(dc.PostgresSSLMode))
+	} else if dc.TLSSkipVerify != nil && *dc.TLSSkipVerify {
+		// Encryption without verification.
+		opts = append(opts, WithSSLMode("require"))
+	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
+	if dc.PostgresTLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.PostgresTLSCAFile))
+	}
+	if dc.PostgresApplicationName != "" {
+		opts = append(opts, WithApplicationName(dc.PostgresApplicationName))
 	}
 	if dc.PostgresQuery != "" {
 		opts = append(opts, WithQuery(dc.PostgresQuery))
 	}
+	if dc.PostgresRole != "" {
+		opts = append(opts, WithRole(dc.PostgresRole))
+	}
+	if dc.PostgresMaxReplicationLag > 0 {
+		opts = append(opts, WithMaxReplicationLag(dc.PostgresMaxReplicationLag))
+	}
 	return New(opts...)
 }
 
+// withURL makes a postgres:// URL the base of the per-endpoint DSN: only
+// its hosts are replaced by the endpoint, the path and parameters are kept
+// as given. Anything else (a keyword/value DSN, an unparsable URL) is used
+// verbatim as the DSN.
+func withURL(rawURL string) Option {
+	return func(c *Checker) {
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+			c.dsn = rawURL
+			return
+		}
+		if u.User != nil {
+			c.user = u.User.Username()
+			c.password, _ = u.User.Password()
+		}
+		c.baseURL = u
+	}
+}
+
+// withCredentialOverrides replaces the user and keeps the URL password
+// unless a password is given.
+func withCredentialOverrides(user, password string) Option {
+	return func(c *Checker) {
+		if user != "" {
+			c.user = user
+		}
+		if password != "" {
+			c.password = password
+		}
+	}
+}
+
+// dsnFor returns the DSN used to check the endpoint in standalone mode.
+func (c *Checker) dsnFor(endpoint dephealth.Endpoint) string {
+	if c.dsn != "" {
+		return c.dsn
+	}
+
+	// Without a dependency URL the database defaults to "postgres"; a URL
+	// without a path leaves it to the driver (PGDATABASE, then the user).
+	u := &url.URL{Scheme: "postgres", Path: "/postgres"}
+	if c.baseURL != nil {
+		clone := *c.baseURL
+		u = &clone
+	}
+	u.Host = net.JoinHostPort(endpoint.Host, endpoint.Port)
+	if c.database != "" {
+		u.Path = "/" + c.database
+		u.RawPath = ""
+	}
+	switch {
+	case c.user != "" && c.password != "":
+		u.User = url.UserPassword(c.user, c.password)
+	case c.user != "":
+		u.User = url.User(c.user)
+	}
+
+	q := u.Query()
+	set := make(url.Values)
+	if c.sslMode != "" {
+		set.Set("sslmode", c.sslMode)
+	} else if q.Get("sslmode") == "" {
+		if mode := c.impliedSSLMode(); mode != "" {
+			set.Set("sslmode", mode)
+		}
+	}
+	if c.tlsCAFile != "" {
+		set.Set("sslrootcert", c.tlsCAFile)
+	}
+	if c.tlsCertFile != "" {
+		set.Set("sslcert", c.tlsCertFile)
+		set.Set("sslkey", c.tlsKeyFile)
+	}
+	if c.appName != "" {
+		set.Set("application_name", c.appName)
+	}
+	// The URL parameters are passed through untouched unless an option
+	// overrides one of them.
+	if len(set) > 0 {
+		for k, v := range set {
+			q[k] = v
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+// impliedSSLMode returns the sslmode implied by the TLS options, "" = none.
+func (c *Checker) impliedSSLMode() string {
+	switch {
+	case c.tlsConfig != nil && c.tlsConfig.InsecureSkipVerify && c.tlsCAFile == "":
+		return "require"
+	case c.tlsConfig != nil || c.tlsCAFile != "" || c.tlsCertFile != "":
+		return "verify-full"
+	default:
+		return ""
+	}
+}
+
+// open returns a connection pool for the endpoint in standalone mode.
+func (c *Checker) open(endpoint dephealth.Endpoint) (*sql.DB, error) {
+	dsn := c.dsnFor(endpoint)
+	if c.tlsConfig == nil {
+		return sql.Open("pgx", dsn)
+	}
+	connCfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if connCfg.TLSConfig != nil {
+		connCfg.TLSConfig = mergeTLSConfig(c.tlsConfig, connCfg.TLSConfig)
+	}
+	for _, fb := range connCfg.Fallbacks {
+		if fb.TLSConfig != nil {
+			fb.TLSConfig = mergeTLSConfig(c.tlsConfig, fb.TLSConfig)
+		}
+	}
+	return stdlib.OpenDB(*connCfg), nil
+}
+
+// mergeTLSConfig returns a clone of base with the verification settings and
+// certificates that pgx derived from sslmode and the certificate files.
+func mergeTLSConfig(base, derived *tls.Config) *tls.Config {
+	cfg := base.Clone()
+	if cfg.ServerName == "" {
+		cfg.ServerName = derived.ServerName
+	}
+	cfg.InsecureSkipVerify = derived.InsecureSkipVerify //nolint:gosec // follows sslmode
+	cfg.VerifyPeerCertificate = derived.VerifyPeerCertificate
+	if derived.RootCAs != nil {
+		cfg.RootCAs = derived.RootCAs
+	}
+	if len(derived.Certificates) > 0 {
+		cfg.Certificates = derived.Certificates
+	}
+	if derived.NextProtos != nil {
+		cfg.NextProtos = derived.NextProtos
+	}
+	return cfg
+}
+
 // Check performs a health check against the PostgreSQL endpoint.
 // In pool mode, uses the existing *sql.DB. In standalone mode, opens a new connection.
 func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
@@ -99,7 +397,10 @@ func (c *Checker) checkPool(ctx context.Context) error {
 	if err != nil {
 		return classifyError(err, "pool")
 	}
-	return rows.Close()
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	return c.checkReplication(ctx, c.db, "pool")
 }
 
 func (c *Checker) checkStandalone(ctx context.Context, endpoint dephealth.Endpoint) error {
@@ -107,12 +408,7 @@ func (c *Checker) checkStandalone(ctx context.Context, endpoint dephealth.Endpoi
 		return err
 	}
 
-	dsn := c.dsn
-	if dsn == "" {
-		dsn = fmt.Sprintf("postgres://%s/postgres", net.JoinHostPort(endpoint.Host, endpoint.Port))
-	}
-
-	db, err := sql.Open("pgx", dsn)
+	db, err := c.open(endpoint)
 	if err != nil {
 		return fmt.Errorf("postgres open %s: %w", endpoint.Host, err)
 	}
@@ -122,7 +418,49 @@ func (c *Checker) checkStandalone(ctx context.Context, endpoint dephealth.Endpoi
 	if err != nil {
 		return classifyError(err, endpoint.Host)
 	}
-	return rows.Close()
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	return c.checkReplication(ctx, db, endpoint.Host)
+}
+
+// checkReplication verifies the server role and replay lag when configured.
+func (c *Checker) checkReplication(ctx context.Context, db *sql.DB, target string) error {
+	if c.role == "" && c.maxLag <= 0 {
+		return nil
+	}
+
+	var inRecovery bool
+	var lagSeconds float64
+	if err := db.QueryRowContext(ctx, replicationQuery).Scan(&inRecovery, &lagSeconds); err != nil {
+		return classifyError(err, target)
+	}
+
+	role := RolePrimary
+	if inRecovery {
+		role = RoleReplica
+	}
+	if c.role != "" && role != c.role {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "wrong_role",
+			Cause:    fmt.Errorf("postgres %s: server is %s, expected %s", target, role, c.role),
+		}
+	}
+	if !inRecovery {
+		return nil
+	}
+
+	lag := time.Duration(lagSeconds * float64(time.Second))
+	dephealth.ReportReplicationLag(ctx, lag)
+	if c.maxLag > 0 && lag > c.maxLag {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "replication_lag",
+			Cause:    fmt.Errorf("postgres %s: replication lag %s exceeds %s", target, lag.Round(time.Millisecond), c.maxLag),
+		}
+	}
+	return nil
 }
 
 // validateQuery checks that the health check query is a SELECT statement.
@@ -146,6 +484,11 @@ func classifyError(err error, target string) error {
 			Cause:    fmt.Errorf("postgres %s: %w", target, err),
 		}
 	}
+	// The server closed the connection during the startup handshake.
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		err = dephealth.WrapHandshakeEOF(err)
+	}
 	return fmt.Errorf("postgres query %s: %w", target, err)
 }
 
@@ -7,30 +7,43 @@ package redischeck
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 
 	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
 )
 
 // defaultDialTimeout is used for standalone Redis connections to ensure
 // errors are classifiable before the check scheduler's context timeout fires.
 const defaultDialTimeout = 3 * time.Second
 
+var tlsSkipVerifyWarnOnce sync.Once
+
 var _ dephealth.HealthChecker = (*Checker)(nil)
 
 func init() {
 	dephealth.RegisterCheckerFactory(dephealth.TypeRedis, NewFromConfig)
 }
 
+// Deployment modes.
+const (
+	ModeStandalone = "standalone"
+	ModeSentinel   = "sentinel"
+	ModeCluster    = "cluster"
+)
+
 // Option configures the Checker.
 type Option func(*Checker)
 
@@ -38,10 +51,30 @@ type Option func(*Checker)
 // Supports two modes:
 //   - Standalone: creates a new redis client per check
 //   - Pool: uses an existing redis.Cmdable (Client, ClusterClient, etc.)
+//
+// A pooled ClusterClient or Ring routes commands to a node of its own
+// choice, so each endpoint is checked through a client for that node built
+// from the pool's options (credentials, TLS, dialer and timeouts).
+//
+// In standalone mode the endpoint may also be a Sentinel (the master is
+// resolved and pinged) or a Cluster node (CLUSTER INFO must report
+// cluster_state:ok), see WithSentinel and WithCluster.
 type Checker struct {
-	client   redis.Cmdable // nil = standalone, non-nil = pool mode
-	password string        // password for standalone mode
-	db       int           // database number for standalone mode
+	client           redis.Cmdable // nil = standalone, non-nil = pool mode
+	username         string        // ACL username for standalone mode
+	password         string        // password for standalone mode
+	passwordProvider dephealth.CredentialProvider
+	db               int // database number for standalone mode
+	mode             string
+	masterName       string
+	sentinelPassword string
+	tlsEnabled       bool
+	tls              tlsutil.Options
+	tlsConfig        *tls.Config // base config from WithTLSConfig
+	tlsSource        *tlsutil.Source
+
+	mu    sync.Mutex
+	nodes map[string]*redis.Client // pool mode: per-node clients by address
 }
 
 // WithClient sets an existing Redis client for pool mode.
@@ -51,6 +84,14 @@ func WithClient(client redis.Cmdable) Option {
 	}
 }
 
+// WithUsername sets the ACL username for standalone mode connections
+// (Redis 6+). Without a username the password authenticates the default user.
+func WithUsername(username string) Option {
+	return func(c *Checker) {
+		c.username = username
+	}
+}
+
 // WithPassword sets the password for standalone mode connections.
 func WithPassword(password string) Option {
 	return func(c *Checker) {
@@ -65,32 +106,164 @@ func WithDB(db int) Option {
 	}
 }
 
+// WithSentinel treats endpoints as Redis Sentinel instances monitoring the
+// given master. Each check asks the sentinel for the master address and
+// pings the master; a sentinel that knows no master reports no_master.
+func WithSentinel(masterName string) Option {
+	return func(c *Checker) {
+		c.mode = ModeSentinel
+		c.masterName = masterName
+	}
+}
+
+// WithPasswordProvider obtains the standalone mode password from p before
+// every check. It takes precedence over WithPassword.
+func WithPasswordProvider(p dephealth.CredentialProvider) Option {
+	return func(c *Checker) {
+		c.passwordProvider = p
+	}
+}
+
+// WithSentinelPassword sets the password for Sentinel connections
+// (the master uses WithPassword).
+func WithSentinelPassword(password string) Option {
+	return func(c *Checker) {
+		c.sentinelPassword = password
+	}
+}
+
+// WithCluster treats endpoints as Redis Cluster nodes. Each check runs
+// CLUSTER INFO on the node and requires cluster_state:ok.
+func WithCluster() Option {
+	return func(c *Checker) {
+		c.mode = ModeCluster
+	}
+}
+
+// WithTLS enables TLS for standalone mode connections.
+// Enabled automatically for rediss:// URLs.
+func WithTLS(enabled bool) Option {
+	return func(c *Checker) {
+		c.tlsEnabled = enabled
+	}
+}
+
+// WithTLSCA sets the PEM CA bundle used to verify the server certificate.
+// Implies TLS.
+func WithTLSCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tls.CAFile = caFile
+	}
+}
+
+// WithTLSClientCert sets the client certificate and key for mTLS.
+// Implies TLS.
+func WithTLSClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tls.CertFile = certFile
+		c.tls.KeyFile = keyFile
+	}
+}
+
+// WithTLSServerName overrides the SNI and the hostname verified against the
+// server certificate (default: the host being dialed).
+func WithTLSServerName(serverName string) Option {
+	return func(c *Checker) {
+		c.tls.ServerName = serverName
+	}
+}
+
+// WithTLSConfig sets the base TLS configuration and enables TLS. The files
+// of WithTLSCA and WithTLSClientCert are loaded on top of a copy and read
+// again when they change.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithTLSSkipVerify skips TLS certificate verification.
+func WithTLSSkipVerify(skip bool) Option {
+	return func(c *Checker) {
+		c.tls.SkipVerify = skip
+	}
+}
+
 // New creates a new Redis health checker with the given options.
 func New(opts ...Option) *Checker {
 	c := &Checker{}
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.tlsSource = tlsutil.NewSource(c.tlsConfig, c.tls)
 	return c
 }
 
 // NewFromConfig creates a Redis checker from DependencyConfig.
 func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
 	var opts []Option
+	if dc.RedisUsername != "" {
+		opts = append(opts, WithUsername(dc.RedisUsername))
+	}
 	if dc.RedisPassword != "" {
 		opts = append(opts, WithPassword(dc.RedisPassword))
 	}
+	if dc.RedisPasswordProvider != nil {
+		opts = append(opts, WithPasswordProvider(dc.RedisPasswordProvider))
+	}
 	if dc.RedisDB != nil {
 		opts = append(opts, WithDB(*dc.RedisDB))
 	}
-	// Extract password and db from URL if explicit options are not set.
+	switch dc.RedisMode {
+	case ModeSentinel:
+		opts = append(opts, WithSentinel(dc.RedisMasterName))
+	case ModeCluster:
+		opts = append(opts, WithCluster())
+	}
+	if dc.RedisSentinelPassword != "" {
+		opts = append(opts, WithSentinelPassword(dc.RedisSentinelPassword))
+	}
+	if dc.RedisTLS != nil {
+		opts = append(opts, WithTLS(*dc.RedisTLS))
+	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
+	if dc.RedisTLSCAFile != "" {
+		opts = append(opts, WithTLSCA(dc.RedisTLSCAFile))
+	}
+	if dc.RedisTLSCertFile != "" {
+		opts = append(opts, WithTLSClientCert(dc.RedisTLSCertFile, dc.RedisTLSKeyFile))
+	}
+	if dc.RedisTLSServerName != "" {
+		opts = append(opts, WithTLSServerName(dc.RedisTLSServerName))
+	}
+	if dc.TLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.TLSSkipVerify))
+	}
+	if dc.RedisTLSSkipVerify != nil {
+		opts = append(opts, WithTLSSkipVerify(*dc.RedisTLSSkipVerify))
+	}
+	// Extract credentials, db and TLS from URL if explicit options are not set.
 	if dc.URL != "" {
 		u, err := url.Parse(dc.URL)
-		if err == nil && u != nil && u.User != nil && dc.RedisPassword == "" {
-			if p, ok := u.User.Password(); ok {
+		if err == nil && u != nil && u.User != nil {
+			if name := u.User.Username(); name != "" && dc.RedisUsername == "" {
+				opts = append(opts, WithUsername(name))
+			}
+			if p, ok := u.User.Password(); ok && dc.RedisPassword == "" {
 				opts = append(opts, WithPassword(p))
 			}
 		}
+		if err == nil && u != nil && strings.EqualFold(u.Scheme, "rediss") && dc.RedisTLS == nil {
+			opts = append(opts, WithTLS(true))
+		}
 		if err == nil && u != nil && dc.RedisDB == nil {
 			dbStr := strings.TrimPrefix(u.Path, "/")
 			if db, parseErr := strconv.Atoi(dbStr); parseErr == nil {
@@ -105,29 +278,163 @@ func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
 // In pool mode, uses the existing client. In standalone mode, creates a new client.
 func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
 	if c.client != nil {
-		return c.checkPool(ctx)
+		return c.checkPool(ctx, endpoint)
 	}
 	return c.checkStandalone(ctx, endpoint)
 }
 
-func (c *Checker) checkPool(ctx context.Context) error {
+func (c *Checker) checkPool(ctx context.Context, endpoint dephealth.Endpoint) error {
+	switch c.client.(type) {
+	case *redis.ClusterClient:
+		addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
+		return checkClusterState(ctx, c.nodeClient(addr), addr)
+	case *redis.Ring:
+		addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
+		if err := c.nodeClient(addr).Ping(ctx).Err(); err != nil {
+			return classifyError(err, addr)
+		}
+		return nil
+	}
+	if c.mode == ModeCluster {
+		return checkClusterState(ctx, c.client, "pool")
+	}
 	if err := c.client.Ping(ctx).Err(); err != nil {
 		return classifyError(err, "pool")
 	}
 	return nil
 }
 
+// nodeClient returns the client for the node at addr of the pooled
+// ClusterClient or Ring, creating it on first use.
+func (c *Checker) nodeClient(addr string) *redis.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if node, ok := c.nodes[addr]; ok {
+		return node
+	}
+	var opts *redis.Options
+	switch client := c.client.(type) {
+	case *redis.ClusterClient:
+		o := client.Options()
+		opts = &redis.Options{
+			Dialer:                     o.Dialer,
+			Protocol:                   o.Protocol,
+			Username:                   o.Username,
+			Password:                   o.Password,
+			CredentialsProvider:        o.CredentialsProvider,
+			CredentialsProviderContext: o.CredentialsProviderContext,
+			DialTimeout:                o.DialTimeout,
+			ReadTimeout:                o.ReadTimeout,
+			WriteTimeout:               o.WriteTimeout,
+			TLSConfig:                  o.TLSConfig,
+		}
+	case *redis.Ring:
+		o := client.Options()
+		opts = &redis.Options{
+			Dialer:                     o.Dialer,
+			Protocol:                   o.Protocol,
+			Username:                   o.Username,
+			Password:                   o.Password,
+			CredentialsProvider:        o.CredentialsProvider,
+			CredentialsProviderContext: o.CredentialsProviderContext,
+			DB:                         o.DB,
+			DialTimeout:                o.DialTimeout,
+			ReadTimeout:                o.ReadTimeout,
+			WriteTimeout:               o.WriteTimeout,
+			TLSConfig:                  o.TLSConfig,
+		}
+	}
+	opts.Addr = addr
+	opts.PoolSize = 1
+	opts.MaxRetries = -1 // No retries; the check scheduler retries.
+	node := redis.NewClient(opts)
+	if c.nodes == nil {
+		c.nodes = make(map[string]*redis.Client)
+	}
+	c.nodes[addr] = node
+	return node
+}
+
+// CloseEndpoint closes the per-node client of the endpoint, if any.
+// The scheduler calls it when the endpoint is removed or the scheduler stops.
+func (c *Checker) CloseEndpoint(endpoint dephealth.Endpoint) {
+	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
+	c.mu.Lock()
+	node, ok := c.nodes[addr]
+	delete(c.nodes, addr)
+	c.mu.Unlock()
+	if ok {
+		_ = node.Close()
+	}
+}
+
+// Close closes the per-node clients of all endpoints. The pooled client
+// itself is owned by the caller and stays open.
+func (c *Checker) Close() error {
+	c.mu.Lock()
+	nodes := c.nodes
+	c.nodes = nil
+	c.mu.Unlock()
+	for _, node := range nodes {
+		_ = node.Close()
+	}
+	return nil
+}
+
 func (c *Checker) checkStandalone(ctx context.Context, endpoint dephealth.Endpoint) error {
 	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
-	client := redis.NewClient(&redis.Options{
+
+	password, err := dephealth.ResolveCredential(ctx, c.passwordProvider, c.password)
+	if err != nil {
+		return err
+	}
+
+	var tlsCfg *tls.Config
+	if c.useTLS() {
+		if c.tls.SkipVerify {
+			tlsSkipVerifyWarnOnce.Do(func() {
+				slog.Warn("dephealth: Redis checker has TLS certificate verification disabled (InsecureSkipVerify=true)")
+			})
+		}
+		if tlsCfg, err = c.tlsSource.Config(); err != nil {
+			return fmt.Errorf("redis %s: %w", addr, err)
+		}
+	}
+
+	switch c.mode {
+	case ModeSentinel:
+		return c.checkSentinel(ctx, addr, password, tlsCfg)
+	case ModeCluster:
+		client := redis.NewClient(c.clientOptions(addr, c.username, password, 0, tlsCfg))
+		defer func() { _ = client.Close() }()
+		return checkClusterState(ctx, client, addr)
+	}
+	return c.ping(ctx, addr, password, tlsCfg)
+}
+
+func (c *Checker) useTLS() bool {
+	return c.tlsEnabled || c.tlsConfig != nil || c.tls.CAFile != "" || c.tls.CertFile != ""
+}
+
+// clientOptions returns options for a short-lived single-attempt client.
+func (c *Checker) clientOptions(addr, username, password string, db int, tlsCfg *tls.Config) *redis.Options {
+	return &redis.Options{
 		Addr:         addr,
-		Password:     c.password,
-		DB:           c.db,
+		Username:     username,
+		Password:     password,
+		DB:           db,
+		TLSConfig:    tlsCfg,
 		MaxRetries:   0,                  // Single attempt; retries are handled by the check scheduler.
 		DialTimeout:  defaultDialTimeout, // Shorter than the check timeout to get a classifiable net error.
 		ReadTimeout:  defaultDialTimeout,
 		WriteTimeout: defaultDialTimeout,
-	})
+	}
+}
+
+// ping connects to addr with the configured user, the given password and the
+// configured database and runs PING.
+func (c *Checker) ping(ctx context.Context, addr, password string, tlsCfg *tls.Config) error {
+	client := redis.NewClient(c.clientOptions(addr, c.username, password, c.db, tlsCfg))
 	defer func() { _ = client.Close() }()
 
 	if err := client.Ping(ctx).Err(); err != nil {
@@ -136,6 +443,49 @@ func (c *Checker) checkStandalone(ctx context.Context, endpoint dephealth.Endpoi
 	return nil
 }
 
+// checkSentinel resolves the master through the sentinel at addr and pings
+// it with the master password.
+func (c *Checker) checkSentinel(ctx context.Context, addr, password string, tlsCfg *tls.Config) error {
+	sentinel := redis.NewSentinelClient(c.clientOptions(addr, "", c.sentinelPassword, 0, tlsCfg))
+	defer func() { _ = sentinel.Close() }()
+
+	master, err := sentinel.GetMasterAddrByName(ctx, c.masterName).Result()
+	if errors.Is(err, redis.Nil) || (err == nil && len(master) != 2) {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "no_master",
+			Cause:    fmt.Errorf("redis sentinel %s: no master for %q", addr, c.masterName),
+		}
+	}
+	if err != nil {
+		return classifyError(err, addr)
+	}
+	return c.ping(ctx, net.JoinHostPort(master[0], master[1]), password, tlsCfg)
+}
+
+// checkClusterState runs CLUSTER INFO and requires cluster_state:ok.
+func checkClusterState(ctx context.Context, client redis.Cmdable, target string) error {
+	info, err := client.ClusterInfo(ctx).Result()
+	if err != nil {
+		return classifyError(err, target)
+	}
+	state := ""
+	for _, line := range strings.Split(info, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "cluster_state:"); ok {
+			state = v
+			break
+		}
+	}
+	if state != "ok" {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusUnhealthy,
+			Detail:   "cluster_fail",
+			Cause:    fmt.Errorf("redis cluster %s: cluster_state:%s", target, state),
+		}
+	}
+	return nil
+}
+
 // classifyError wraps Redis errors with appropriate classification.
 func classifyError(err error, target string) error {
 	msg := err.Error()
@@ -190,7 +540,9 @@ func classifyError(err error, target string) error {
 		}
 	}
 
-	return fmt.Errorf("redis ping %s: %w", target, err)
+	// go-redis returns io.EOF when the server drops the connection
+	// (e.g. over maxclients); a reply is never truncated otherwise.
+	return fmt.Errorf("redis ping %s: %w", target, dephealth.WrapHandshakeEOF(err))
 }
 
 // Type returns the dependency type for this checker.
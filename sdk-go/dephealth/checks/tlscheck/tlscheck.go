@@ -0,0 +1,292 @@
+// Package tlscheck provides a TLS handshake health checker for dephealth.
+//
+// Import this package to register the TLS checker factory:
+//
+//	import _ "github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/tlscheck"
+package tlscheck
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
+)
+
+// defaultDialTimeout bounds the TCP connect so that connection errors are
+// classifiable before the check scheduler's context timeout fires.
+const defaultDialTimeout = 3 * time.Second
+
+var tlsSkipVerifyWarnOnce sync.Once
+
+var _ dephealth.HealthChecker = (*Checker)(nil)
+
+func init() {
+	dephealth.RegisterCheckerFactory(dephealth.TypeTLS, NewFromConfig)
+}
+
+// Option configures the Checker.
+type Option func(*Checker)
+
+// Checker performs health checks by completing a TLS handshake with the
+// endpoint. The check succeeds if the certificate chain verifies against the
+// configured roots, the certificate matches the server name, the negotiated
+// protocol is not older than the minimum version and, when pins are
+// configured, the leaf certificate fingerprint matches one of them.
+// No application data is sent.
+type Checker struct {
+	tls        tlsutil.Options
+	tlsConfig  *tls.Config // base config from WithTLSConfig
+	tlsSource  *tlsutil.Source
+	rootCAs    *x509.CertPool
+	minVersion uint16
+	pins       [][]byte
+	pinErr     error
+}
+
+// WithServerName sets the SNI and the hostname verified against the
+// certificate (default: endpoint host).
+func WithServerName(serverName string) Option {
+	return func(c *Checker) {
+		c.tls.ServerName = serverName
+	}
+}
+
+// WithMinVersion sets the minimum accepted protocol version
+// (default tls.VersionTLS12).
+func WithMinVersion(version uint16) Option {
+	return func(c *Checker) {
+		c.minVersion = version
+	}
+}
+
+// WithPinnedCert adds an expected SHA-256 fingerprint of the leaf certificate
+// (hex, colons and a "sha256:" prefix are allowed). When at least one pin is
+// configured, the check fails unless the leaf certificate matches one of them.
+func WithPinnedCert(sha256Fingerprint string) Option {
+	return func(c *Checker) {
+		pin, err := parseFingerprint(sha256Fingerprint)
+		if err != nil {
+			c.pinErr = err
+			return
+		}
+		c.pins = append(c.pins, pin)
+	}
+}
+
+// WithCA sets the PEM CA bundle used to verify the server certificate.
+func WithCA(caFile string) Option {
+	return func(c *Checker) {
+		c.tls.CAFile = caFile
+	}
+}
+
+// WithRootCAs sets the pool used to verify the server certificate.
+// Takes precedence over WithCA.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(c *Checker) {
+		c.rootCAs = pool
+	}
+}
+
+// WithClientCert sets the client certificate and key for mTLS.
+func WithClientCert(certFile, keyFile string) Option {
+	return func(c *Checker) {
+		c.tls.CertFile = certFile
+		c.tls.KeyFile = keyFile
+	}
+}
+
+// WithTLSConfig sets the base TLS configuration of the handshake. The
+// other options are applied to a copy; the files of WithCA and
+// WithClientCert are read again when they change.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Checker) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithSkipVerify skips chain and hostname verification.
+// Pinned fingerprints are still enforced.
+func WithSkipVerify(skip bool) Option {
+	return func(c *Checker) {
+		c.tls.SkipVerify = skip
+	}
+}
+
+// New creates a new TLS health checker with the given options.
+func New(opts ...Option) *Checker {
+	c := &Checker{
+		minVersion: tls.VersionTLS12,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.tlsSource = tlsutil.NewSource(c.tlsConfig, c.tls)
+	return c
+}
+
+// NewFromConfig creates a TLS checker from DependencyConfig.
+func NewFromConfig(dc *dephealth.DependencyConfig) dephealth.HealthChecker {
+	var opts []Option
+	if dc.TLSServerName != "" {
+		opts = append(opts, WithServerName(dc.TLSServerName))
+	}
+	if v, ok := versions[dc.TLSMinVersion]; ok {
+		opts = append(opts, WithMinVersion(v))
+	}
+	for _, pin := range dc.TLSPinnedCerts {
+		opts = append(opts, WithPinnedCert(pin))
+	}
+	if dc.TLSConfig != nil {
+		opts = append(opts, WithTLSConfig(dc.TLSConfig))
+	}
+	if dc.TLSCAFile != "" {
+		opts = append(opts, WithCA(dc.TLSCAFile))
+	}
+	if dc.TLSCertFile != "" {
+		opts = append(opts, WithClientCert(dc.TLSCertFile, dc.TLSKeyFile))
+	}
+	if dc.TLSSkipVerify != nil {
+		opts = append(opts, WithSkipVerify(*dc.TLSSkipVerify))
+	}
+	return New(opts...)
+}
+
+// versions maps configuration strings to TLS protocol versions.
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Check performs a TLS handshake with the endpoint and closes the connection.
+func (c *Checker) Check(ctx context.Context, endpoint dephealth.Endpoint) error {
+	addr := net.JoinHostPort(endpoint.Host, endpoint.Port)
+
+	if c.pinErr != nil {
+		return fmt.Errorf("tls %s: %w", addr, c.pinErr)
+	}
+
+	cfg, err := c.tlsSource.Config()
+	if err != nil {
+		return fmt.Errorf("tls %s: %w", addr, err)
+	}
+	cfg.MinVersion = c.minVersion
+	if c.rootCAs != nil {
+		cfg.RootCAs = c.rootCAs
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = endpoint.Host
+	}
+	if c.tls.SkipVerify {
+		tlsSkipVerifyWarnOnce.Do(func() {
+			slog.Warn("dephealth: TLS checker has certificate verification disabled (InsecureSkipVerify=true)")
+		})
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: defaultDialTimeout},
+		Config:    cfg,
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return classifyError(ctx, err, addr)
+	}
+	defer func() { _ = conn.Close() }()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	dephealth.ReportTLSConnectionState(ctx, &state)
+
+	if len(c.pins) > 0 && !c.pinned(state.PeerCertificates[0]) {
+		return &dephealth.ClassifiedCheckError{
+			Category: dephealth.StatusTLSError,
+			Detail:   "tls_pin_mismatch",
+			Cause: fmt.Errorf("tls %s: leaf certificate fingerprint %s does not match any pinned fingerprint",
+				addr, hex.EncodeToString(fingerprint(state.PeerCertificates[0]))),
+		}
+	}
+	return nil
+}
+
+// pinned reports whether the certificate matches one of the pinned fingerprints.
+func (c *Checker) pinned(cert *x509.Certificate) bool {
+	fp := fingerprint(cert)
+	for _, pin := range c.pins {
+		if bytes.Equal(fp, pin) {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprint returns the SHA-256 digest of the DER-encoded certificate.
+func fingerprint(cert *x509.Certificate) []byte {
+	sum := sha256.Sum256(cert.Raw)
+	return sum[:]
+}
+
+// parseFingerprint decodes a hex SHA-256 fingerprint, accepting an optional
+// "sha256:" prefix and colon separators.
+func parseFingerprint(s string) ([]byte, error) {
+	fp := strings.ReplaceAll(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "sha256:"), ":", "")
+	b, err := hex.DecodeString(fp)
+	if err != nil || len(b) != sha256.Size {
+		return nil, fmt.Errorf("invalid pinned certificate %q: expected a hex SHA-256 fingerprint", s)
+	}
+	return b, nil
+}
+
+// classifyError maps handshake failures onto tls_error with a granular detail.
+// Connection errors are returned unclassified for the core classifier.
+func classifyError(ctx context.Context, err error, target string) error {
+	// Report the expiry of a certificate that failed verification as well,
+	// so that an already expired certificate stays visible in metrics.
+	var verifyErr *tls.CertificateVerificationError
+	if errors.As(err, &verifyErr) && len(verifyErr.UnverifiedCertificates) > 0 {
+		dephealth.ReportTLSCertExpiry(ctx, verifyErr.UnverifiedCertificates[0].NotAfter)
+	}
+
+	detail := ""
+	var invalidErr x509.CertificateInvalidError
+	var hostErr x509.HostnameError
+	var authErr x509.UnknownAuthorityError
+	var alertErr tls.AlertError
+	var recordErr tls.RecordHeaderError
+	switch {
+	case errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired:
+		detail = "tls_expired"
+	case errors.As(err, &hostErr):
+		detail = "tls_hostname_mismatch"
+	case errors.As(err, &authErr):
+		detail = "tls_unknown_authority"
+	case verifyErr != nil, errors.As(err, &alertErr), errors.As(err, &recordErr),
+		strings.Contains(err.Error(), "tls:"):
+		detail = "tls_error"
+	default:
+		return fmt.Errorf("tls dial %s: %w", target, dephealth.WrapHandshakeEOF(err))
+	}
+
+	return &dephealth.ClassifiedCheckError{
+		Category: dephealth.StatusTLSError,
+		Detail:   detail,
+		Cause:    fmt.Errorf("tls handshake %s: %w", target, err),
+	}
+}
+
+// Type returns the dependency type for this checker.
+func (c *Checker) Type() string {
+	return string(dephealth.TypeTLS)
+}
@@ -0,0 +1,220 @@
+package tlscheck
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlstest"
+)
+
+// startServer starts a TLS listener that completes handshakes and closes
+// connections. It returns the endpoint to check.
+func startServer(t *testing.T, cfg *tls.Config) dephealth.Endpoint {
+	t.Helper()
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_ = conn.(*tls.Conn).Handshake()
+				_ = conn.Close()
+			}()
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	return dephealth.Endpoint{Host: host, Port: port}
+}
+
+func serverConfig(t *testing.T, leaf *tlstest.Leaf) *tls.Config {
+	t.Helper()
+	return &tls.Config{Certificates: []tls.Certificate{leaf.TLSCertificate(t)}}
+}
+
+func expectDetail(t *testing.T, err error, detail string) {
+	t.Helper()
+	var ce *dephealth.ClassifiedCheckError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected ClassifiedCheckError, got %v", err)
+	}
+	if ce.Category != dephealth.StatusTLSError {
+		t.Errorf("category = %s, expected %s", ce.Category, dephealth.StatusTLSError)
+	}
+	if ce.Detail != detail {
+		t.Errorf("detail = %q, expected %q (%v)", ce.Detail, detail, err)
+	}
+}
+
+func TestChecker_Check_Success(t *testing.T) {
+	ca := tlstest.NewCA(t)
+	leaf := ca.Issue(t, time.Now().Add(time.Hour), "127.0.0.1")
+	ep := startServer(t, serverConfig(t, leaf))
+
+	caFile := tlstest.WriteFile(t, t.TempDir(), "ca.pem", ca.PEM)
+	if err := New(WithCA(caFile)).Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success, got: %v", err)
+	}
+}
+
+func TestChecker_Check_UnknownAuthority(t *testing.T) {
+	ca := tlstest.NewCA(t)
+	leaf := ca.Issue(t, time.Now().Add(time.Hour), "127.0.0.1")
+	ep := startServer(t, serverConfig(t, leaf))
+
+	expectDetail(t, New().Check(context.Background(), ep), "tls_unknown_authority")
+}
+
+func TestChecker_Check_HostnameMismatch(t *testing.T) {
+	ca := tlstest.NewCA(t)
+	leaf := ca.Issue(t, time.Now().Add(time.Hour), "db.example.com")
+	ep := startServer(t, serverConfig(t, leaf))
+
+	err := New(WithRootCAs(ca.Pool()), WithServerName("other.example.com")).Check(context.Background(), ep)
+	expectDetail(t, err, "tls_hostname_mismatch")
+
+	err = New(WithRootCAs(ca.Pool()), WithServerName("db.example.com")).Check(context.Background(), ep)
+	if err != nil {
+		t.Errorf("expected success with matching server name, got: %v", err)
+	}
+}
+
+func TestChecker_Check_Expired(t *testing.T) {
+	ca := tlstest.NewCA(t)
+	leaf := ca.Issue(t, time.Now().Add(-time.Hour), "127.0.0.1")
+	ep := startServer(t, serverConfig(t, leaf))
+
+	expectDetail(t, New(WithRootCAs(ca.Pool())).Check(context.Background(), ep), "tls_expired")
+}
+
+func TestChecker_Check_Pinning(t *testing.T) {
+	ca := tlstest.NewCA(t)
+	leaf := ca.Issue(t, time.Now().Add(time.Hour), "127.0.0.1")
+	ep := startServer(t, serverConfig(t, leaf))
+
+	fp := fingerprint(leaf.Cert)
+	colons := strings.ToUpper(hex.EncodeToString(fp[:1]))
+	for _, b := range fp[1:] {
+		colons += ":" + strings.ToUpper(hex.EncodeToString([]byte{b}))
+	}
+
+	// Pinning works without chain verification.
+	checker := New(WithSkipVerify(true), WithPinnedCert(colons))
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success with matching pin, got: %v", err)
+	}
+
+	other := strings.Repeat("ab", 32)
+	checker = New(WithRootCAs(ca.Pool()), WithPinnedCert(other))
+	expectDetail(t, checker.Check(context.Background(), ep), "tls_pin_mismatch")
+
+	// Any of several pins is accepted (certificate rotation).
+	checker = New(WithRootCAs(ca.Pool()), WithPinnedCert(other), WithPinnedCert("sha256:"+hex.EncodeToString(fp)))
+	if err := checker.Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success with one matching pin, got: %v", err)
+	}
+}
+
+func TestChecker_Check_InvalidPin(t *testing.T) {
+	err := New(WithPinnedCert("not-a-fingerprint")).Check(context.Background(),
+		dephealth.Endpoint{Host: "127.0.0.1", Port: "1"})
+	if err == nil || !strings.Contains(err.Error(), "SHA-256") {
+		t.Errorf("expected invalid pin error, got %v", err)
+	}
+}
+
+func TestChecker_Check_MinVersion(t *testing.T) {
+	ca := tlstest.NewCA(t)
+	leaf := ca.Issue(t, time.Now().Add(time.Hour), "127.0.0.1")
+	cfg := serverConfig(t, leaf)
+	cfg.MaxVersion = tls.VersionTLS12
+	ep := startServer(t, cfg)
+
+	err := New(WithRootCAs(ca.Pool()), WithMinVersion(tls.VersionTLS13)).Check(context.Background(), ep)
+	expectDetail(t, err, "tls_error")
+
+	if err := New(WithRootCAs(ca.Pool())).Check(context.Background(), ep); err != nil {
+		t.Errorf("expected success with default min version, got: %v", err)
+	}
+}
+
+func TestChecker_Check_PlainTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("220 smtp.example.com ESMTP ready\r\n"))
+		_ = conn.Close()
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	err = New().Check(context.Background(), dephealth.Endpoint{Host: host, Port: port})
+	expectDetail(t, err, "tls_error")
+}
+
+func TestChecker_Check_ConnectionRefused(t *testing.T) {
+	err := New().Check(context.Background(), dephealth.Endpoint{Host: "127.0.0.1", Port: "1"})
+	if err == nil {
+		t.Fatal("expected error for closed port, got nil")
+	}
+	var ce *dephealth.ClassifiedCheckError
+	if errors.As(err, &ce) {
+		t.Errorf("expected plain error for the core classifier, got classified %s", ce.Detail)
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	skip := true
+	dc := &dephealth.DependencyConfig{
+		TLSServerName:  "smtp.example.com",
+		TLSMinVersion:  "1.3",
+		TLSPinnedCerts: []string{strings.Repeat("0", 64)},
+		TLSCAFile:      "ca.pem",
+		TLSCertFile:    "client.pem",
+		TLSKeyFile:     "client-key.pem",
+		TLSSkipVerify:  &skip,
+	}
+	checker, ok := NewFromConfig(dc).(*Checker)
+	if !ok {
+		t.Fatal("expected *Checker")
+	}
+	if checker.tls.ServerName != "smtp.example.com" {
+		t.Errorf("server name = %q", checker.tls.ServerName)
+	}
+	if checker.minVersion != tls.VersionTLS13 {
+		t.Errorf("min version = %x, expected TLS 1.3", checker.minVersion)
+	}
+	if len(checker.pins) != 1 {
+		t.Errorf("expected 1 pin, got %d", len(checker.pins))
+	}
+	if checker.tls.CAFile != "ca.pem" || checker.tls.CertFile != "client.pem" || checker.tls.KeyFile != "client-key.pem" {
+		t.Errorf("unexpected TLS files: %+v", checker.tls)
+	}
+	if !checker.tls.SkipVerify {
+		t.Error("expected skip verify")
+	}
+}
+
+func TestChecker_Type(t *testing.T) {
+	if got := New().Type(); got != "tls" {
+		t.Errorf("Type() = %q, expected %q", got, "tls")
+	}
+}
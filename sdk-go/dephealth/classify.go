@@ -3,19 +3,80 @@ package dephealth
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
+	"io"
 	"net"
+	"net/url"
 	"strings"
+	"sync"
 	"syscall"
 )
 
+// ErrorClassifier maps a check error to a CheckResult. It returns false
+// when it does not recognize the error, so the next classifier in the
+// chain is consulted. Classifiers let custom checkers return driver errors
+// (e.g. a pgx error code or a gRPC status) without wrapping them in
+// ClassifiedCheckError.
+type ErrorClassifier func(err error) (CheckResult, bool)
+
+// errorClassifiers is the registry of global and per-type error classifiers.
+var (
+	errorClassifiers     []ErrorClassifier
+	typeErrorClassifiers = map[DependencyType][]ErrorClassifier{}
+	errorClassifiersMu   sync.RWMutex
+)
+
+// RegisterErrorClassifier registers an error classifier for all
+// dependencies. Classifiers are consulted in registration order; a nil
+// classifier is ignored.
+// Safe for concurrent use; typically called from init() functions.
+func RegisterErrorClassifier(c ErrorClassifier) {
+	if c == nil {
+		return
+	}
+	errorClassifiersMu.Lock()
+	defer errorClassifiersMu.Unlock()
+	errorClassifiers = append(errorClassifiers, c)
+}
+
+// RegisterTypeErrorClassifier registers an error classifier for the
+// dependencies of the specified type. It is consulted before the
+// classifiers registered with RegisterErrorClassifier; a nil classifier is
+// ignored.
+// Safe for concurrent use; typically called from init() functions.
+func RegisterTypeErrorClassifier(depType DependencyType, c ErrorClassifier) {
+	if c == nil {
+		return
+	}
+	errorClassifiersMu.Lock()
+	defer errorClassifiersMu.Unlock()
+	typeErrorClassifiers[depType] = append(typeErrorClassifiers[depType], c)
+}
+
+// errorClassifiersFor returns the classifiers for dep, most specific first:
+// per-dependency (WithErrorClassifier), per-type, then global.
+func errorClassifiersFor(dep Dependency) []ErrorClassifier {
+	errorClassifiersMu.RLock()
+	defer errorClassifiersMu.RUnlock()
+	typed := typeErrorClassifiers[dep.Type]
+	if len(dep.Config.ErrorClassifiers)+len(typed)+len(errorClassifiers) == 0 {
+		return nil
+	}
+	classifiers := make([]ErrorClassifier, 0, len(dep.Config.ErrorClassifiers)+len(typed)+len(errorClassifiers))
+	classifiers = append(classifiers, dep.Config.ErrorClassifiers...)
+	classifiers = append(classifiers, typed...)
+	return append(classifiers, errorClassifiers...)
+}
+
 // classifyError determines the CheckResult for a health check outcome.
 // It follows the classification chain defined in the specification:
 // 1. ClassifiedError interface
-// 2. Sentinel errors
-// 3. Platform error detection
-// 4. Fallback → error/error
-func classifyError(err error) CheckResult {
+// 2. Registered error classifiers, in the given order
+// 3. Sentinel errors
+// 4. Platform error detection
+// 5. Fallback → error/error
+func classifyError(err error, classifiers ...ErrorClassifier) CheckResult {
 	if err == nil {
 		return CheckResult{Category: StatusOK, Detail: "ok"}
 	}
@@ -26,7 +87,14 @@ func classifyError(err error) CheckResult {
 		return CheckResult{Category: ce.StatusCategory(), Detail: ce.StatusDetail()}
 	}
 
-	// 2. Sentinel errors.
+	// 2. Registered error classifiers.
+	for _, c := range classifiers {
+		if result, ok := c(err); ok {
+			return result
+		}
+	}
+
+	// 3. Sentinel errors.
 	if errors.Is(err, ErrTimeout) {
 		return CheckResult{Category: StatusTimeout, Detail: "timeout"}
 	}
@@ -36,58 +104,159 @@ func classifyError(err error) CheckResult {
 	if errors.Is(err, ErrUnhealthy) {
 		return CheckResult{Category: StatusUnhealthy, Detail: "unhealthy"}
 	}
+	if errors.Is(err, ErrConnectionClosed) {
+		return CheckResult{Category: StatusConnectionError, Detail: "connection_closed"}
+	}
 
-	// 3. Platform error detection.
+	// 4. Platform error detection.
 
 	// context.DeadlineExceeded — timeout.
 	if errors.Is(err, context.DeadlineExceeded) {
 		return CheckResult{Category: StatusTimeout, Detail: "timeout"}
 	}
 
-	// DNS errors.
+	// DNS errors: NXDOMAIN, a failing server (SERVFAIL) or other.
 	var dnsErr *net.DNSError
 	if errors.As(err, &dnsErr) {
+		switch {
+		case dnsErr.IsNotFound:
+			return CheckResult{Category: StatusDNSError, Detail: "dns_nxdomain"}
+		case dnsErr.IsTemporary && !dnsErr.IsTimeout:
+			return CheckResult{Category: StatusDNSError, Detail: "dns_servfail"}
+		}
 		return CheckResult{Category: StatusDNSError, Detail: "dns_error"}
 	}
 
-	// Connection refused / network unreachable via OpError.
+	// Connection refused, reset or unreachable, as reported by the OS.
+	if detail := connectionErrnoDetail(err); detail != "" {
+		return CheckResult{Category: StatusConnectionError, Detail: detail}
+	}
+
+	// Timeout inside OpError.
 	var opErr *net.OpError
-	if errors.As(err, &opErr) {
-		if isSyscallConnectionRefused(opErr.Err) {
-			return CheckResult{Category: StatusConnectionError, Detail: "connection_refused"}
-		}
-		// Timeout inside OpError.
-		if opErr.Timeout() {
-			return CheckResult{Category: StatusTimeout, Detail: "timeout"}
-		}
+	if errors.As(err, &opErr) && opErr.Timeout() {
+		return CheckResult{Category: StatusTimeout, Detail: "timeout"}
 	}
 
-	// TLS errors.
-	var certErr *tls.CertificateVerificationError
-	if errors.As(err, &certErr) {
-		return CheckResult{Category: StatusTLSError, Detail: "tls_error"}
+	// The server rejects new connections.
+	if isTooManyConnections(err) {
+		return CheckResult{Category: StatusConnectionError, Detail: "too_many_connections"}
 	}
+
+	// TLS certificate errors.
+	if detail := tlsCertificateDetail(err); detail != "" {
+		return CheckResult{Category: StatusTLSError, Detail: detail}
+	}
+
+	// The peer closed the connection before answering (see isConnectionEOF).
+	if isConnectionEOF(err) {
+		return CheckResult{Category: StatusConnectionError, Detail: "connection_closed"}
+	}
+
 	// Fallback: detect TLS errors by common error message patterns
 	// when the error type is not a concrete *tls.CertificateVerificationError.
 	if isTLSError(err) {
 		return CheckResult{Category: StatusTLSError, Detail: "tls_error"}
 	}
 
-	// 4. Fallback.
+	// 5. Fallback.
 	return CheckResult{Category: StatusError, Detail: "error"}
 }
 
-// isSyscallConnectionRefused checks if the error is ECONNREFUSED.
-func isSyscallConnectionRefused(err error) bool {
-	var sysErr *syscall.Errno
-	if errors.As(err, &sysErr) {
-		return *sysErr == syscall.ECONNREFUSED
+// connectionErrnoDetails maps the errno of a failed connection to its detail.
+var connectionErrnoDetails = []struct {
+	errno  syscall.Errno
+	detail string
+}{
+	{syscall.ECONNREFUSED, "connection_refused"},
+	{syscall.ECONNRESET, "connection_reset"},
+	{syscall.EHOSTUNREACH, "host_unreachable"},
+	{syscall.ENETUNREACH, "network_unreachable"},
+}
+
+// connectionErrnoDetail returns the detail for a connection errno in the
+// chain of err, or "" if there is none.
+func connectionErrnoDetail(err error) string {
+	for _, e := range connectionErrnoDetails {
+		if errors.Is(err, e.errno) {
+			return e.detail
+		}
 	}
-	// Also check for direct syscall.Errno value.
-	if errors.Is(err, syscall.ECONNREFUSED) {
+	return ""
+}
+
+// isConnectionEOF reports whether err is an EOF read from the connection
+// itself: io.EOF or io.ErrUnexpectedEOF returned as is (net.Conn reads and
+// TLS handshakes return them unwrapped), or wrapped in *net.OpError or in
+// *url.Error (an HTTP transport error, never a body decoding error). An
+// EOF wrapped otherwise usually comes from decoding a truncated response
+// and falls through to error; checkers mark EOFs of their dial or
+// handshake with WrapHandshakeEOF instead.
+func isConnectionEOF(err error) bool {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
 		return true
 	}
-	return false
+	if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return false
+	}
+	var opErr *net.OpError
+	var urlErr *url.Error
+	return errors.As(err, &opErr) || errors.As(err, &urlErr)
+}
+
+// tooManyConnectionsMessages are the messages (matched case-insensitively
+// as substrings) of servers that reject a connection over their limit:
+//
+//   - "too many connections": MySQL and MariaDB error 1040
+//     ("Too many connections");
+//   - "too many clients": PostgreSQL SQLSTATE 53300
+//     ("sorry, too many clients already");
+//   - "max number of clients reached": Redis maxclients
+//     ("ERR max number of clients reached").
+//
+// The PostgreSQL code "SQLSTATE 53300" is matched as well, for drivers that
+// report the code with a localized message.
+var tooManyConnectionsMessages = []string{
+	"too many connections",
+	"too many clients",
+	"max number of clients reached",
+}
+
+// isTooManyConnections checks if the error message indicates that the
+// server's connection limit is reached (see tooManyConnectionsMessages).
+func isTooManyConnections(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, m := range tooManyConnectionsMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return strings.Contains(msg, "sqlstate 53300")
+}
+
+// tlsCertificateDetail returns the detail for a certificate verification
+// failure in the chain of err, or "" if there is none. Drivers that flatten
+// the error into a message are recognized by the standard x509 texts.
+func tlsCertificateDetail(err error) string {
+	var invalidErr x509.CertificateInvalidError
+	var hostErr x509.HostnameError
+	var authErr x509.UnknownAuthorityError
+	var verifyErr *tls.CertificateVerificationError
+	msg := err.Error()
+	switch {
+	case errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired,
+		strings.Contains(msg, "certificate has expired"):
+		return "tls_expired"
+	case errors.As(err, &hostErr),
+		strings.Contains(msg, "certificate is valid for"):
+		return "tls_hostname_mismatch"
+	case errors.As(err, &authErr),
+		strings.Contains(msg, "certificate signed by unknown authority"):
+		return "tls_unknown_authority"
+	case errors.As(err, &verifyErr):
+		return "tls_error"
+	}
+	return ""
 }
 
 // isTLSError checks if the error message indicates a TLS error.
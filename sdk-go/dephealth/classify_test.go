@@ -2,9 +2,16 @@ package dephealth
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/url"
+	"os"
+	"strings"
 	"syscall"
 	"testing"
 )
@@ -152,3 +159,220 @@ func TestClassifyError_ClassifiedError_TakesPriority(t *testing.T) {
 		t.Errorf("ClassifiedError should take priority, got %s", r.Category)
 	}
 }
+
+func TestClassifyError_Details(t *testing.T) {
+	opErr := func(errno syscall.Errno) error {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: &os.SyscallError{Syscall: "connect", Err: errno}}
+	}
+	verifyErr := func(err error) error {
+		return fmt.Errorf("tls handshake: %w", &tls.CertificateVerificationError{Err: err})
+	}
+	tests := []struct {
+		name     string
+		err      error
+		category StatusCategory
+		detail   string
+	}{
+		{"nxdomain", &net.DNSError{Err: "no such host", Name: "db.svc", IsNotFound: true},
+			StatusDNSError, "dns_nxdomain"},
+		{"servfail", &net.DNSError{Err: "server misbehaving", Name: "db.svc", IsTemporary: true},
+			StatusDNSError, "dns_servfail"},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", Name: "db.svc", IsTimeout: true, IsTemporary: true},
+			StatusDNSError, "dns_error"},
+		{"connection reset", fmt.Errorf("read: %w", opErr(syscall.ECONNRESET)),
+			StatusConnectionError, "connection_reset"},
+		{"host unreachable", opErr(syscall.EHOSTUNREACH), StatusConnectionError, "host_unreachable"},
+		{"network unreachable", opErr(syscall.ENETUNREACH), StatusConnectionError, "network_unreachable"},
+		{"bare errno refused", fmt.Errorf("driver: %w", syscall.ECONNREFUSED),
+			StatusConnectionError, "connection_refused"},
+		{"postgres too many clients", errors.New("FATAL: sorry, too many clients already"),
+			StatusConnectionError, "too_many_connections"},
+		{"postgres sqlstate 53300", errors.New("FATAL: trop de clients déjà connectés (SQLSTATE 53300)"),
+			StatusConnectionError, "too_many_connections"},
+		{"mysql too many connections", errors.New("Error 1040: Too many connections"),
+			StatusConnectionError, "too_many_connections"},
+		{"mariadb too many connections", errors.New("Error 1040 (08004): Too many connections"),
+			StatusConnectionError, "too_many_connections"},
+		{"redis maxclients", errors.New("ERR max number of clients reached"),
+			StatusConnectionError, "too_many_connections"},
+		{"tls expired", verifyErr(x509.CertificateInvalidError{Reason: x509.Expired}),
+			StatusTLSError, "tls_expired"},
+		{"tls unknown authority", verifyErr(x509.UnknownAuthorityError{}),
+			StatusTLSError, "tls_unknown_authority"},
+		{"tls hostname", verifyErr(x509.HostnameError{Certificate: &x509.Certificate{}, Host: "db.svc"}),
+			StatusTLSError, "tls_hostname_mismatch"},
+		{"tls other verification", verifyErr(x509.CertificateInvalidError{Reason: x509.NotAuthorizedToSign}),
+			StatusTLSError, "tls_error"},
+		{"tls expired by message", errors.New("failed to connect: tls: failed to verify certificate: x509: certificate has expired or is not yet valid"),
+			StatusTLSError, "tls_expired"},
+		{"tls unknown authority by message", errors.New("x509: certificate signed by unknown authority"),
+			StatusTLSError, "tls_unknown_authority"},
+		{"bare eof", io.EOF, StatusConnectionError, "connection_closed"},
+		{"bare unexpected eof", io.ErrUnexpectedEOF, StatusConnectionError, "connection_closed"},
+		{"http transport eof", fmt.Errorf("http check: %w", &url.Error{Op: "Get", URL: "https://api.svc", Err: io.EOF}),
+			StatusConnectionError, "connection_closed"},
+		{"net read eof", fmt.Errorf("read reply: %w", &net.OpError{Op: "read", Net: "tcp", Err: io.EOF}),
+			StatusConnectionError, "connection_closed"},
+		{"handshake eof", fmt.Errorf("read startup message: %w", WrapHandshakeEOF(io.ErrUnexpectedEOF)),
+			StatusConnectionError, "connection_closed"},
+		{"decoding eof", fmt.Errorf("decode health response: %w", io.EOF),
+			StatusError, "error"},
+		{"truncated frame", fmt.Errorf("read frame header: %w", io.ErrUnexpectedEOF),
+			StatusError, "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := classifyError(tt.err)
+			if r.Category != tt.category || r.Detail != tt.detail {
+				t.Errorf("classifyError(%v) = %s/%s, expected %s/%s", tt.err, r.Category, r.Detail, tt.category, tt.detail)
+			}
+		})
+	}
+}
+
+func TestClassifyError_ConnectionClosed_Network(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			// Read the ClientHello, then close without answering.
+			_, _ = conn.Read(make([]byte, 64<<10))
+			_ = conn.Close()
+		}
+	}()
+
+	d := tls.Dialer{Config: &tls.Config{InsecureSkipVerify: true}} //nolint:gosec // test server
+	_, err = d.DialContext(context.Background(), "tcp", ln.Addr().String())
+	if err == nil {
+		t.Fatal("expected a handshake error")
+	}
+	if r := classifyError(err); r.Category != StatusConnectionError || r.Detail != "connection_closed" {
+		t.Errorf("classifyError(%v) = %s/%s, expected connection_error/connection_closed", err, r.Category, r.Detail)
+	}
+}
+
+func TestClassifyError_DecodingEOF(t *testing.T) {
+	// An empty body decoded by the checker is not a closed connection.
+	var v struct{}
+	err := json.NewDecoder(strings.NewReader("")).Decode(&v)
+	if r := classifyError(fmt.Errorf("decode body: %w", err)); r.Detail == "connection_closed" {
+		t.Errorf("classifyError(%v) = %s/%s, expected the decoding EOF not to be connection_closed", err, r.Category, r.Detail)
+	}
+}
+
+func TestWrapHandshakeEOF(t *testing.T) {
+	if err := WrapHandshakeEOF(nil); err != nil {
+		t.Errorf("WrapHandshakeEOF(nil) = %v, expected nil", err)
+	}
+	other := errors.New("tls: bad certificate")
+	if err := WrapHandshakeEOF(other); err != other {
+		t.Errorf("WrapHandshakeEOF(%v) = %v, expected the error unchanged", other, err)
+	}
+	err := WrapHandshakeEOF(fmt.Errorf("receive message: %w", io.ErrUnexpectedEOF))
+	if !errors.Is(err, ErrConnectionClosed) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("WrapHandshakeEOF = %v, expected ErrConnectionClosed wrapping the EOF", err)
+	}
+}
+
+// driverError is a driver-specific error recognized by a test classifier.
+type driverError struct{ code string }
+
+func (e *driverError) Error() string { return "driver error " + e.code }
+
+// driverCodeClassifier classifies driverError with the given code.
+func driverCodeClassifier(code string, result CheckResult) ErrorClassifier {
+	return func(err error) (CheckResult, bool) {
+		var de *driverError
+		if errors.As(err, &de) && de.code == code {
+			return result, true
+		}
+		return CheckResult{}, false
+	}
+}
+
+func TestClassifyError_Classifiers(t *testing.T) {
+	authFailed := driverCodeClassifier("28P01", CheckResult{Category: StatusAuthError, Detail: "auth_error"})
+	tooMany := driverCodeClassifier("53300", CheckResult{Category: StatusConnectionError, Detail: "too_many_connections"})
+
+	tests := []struct {
+		name         string
+		err          error
+		wantCategory StatusCategory
+		wantDetail   string
+	}{
+		{"recognized", &driverError{code: "28P01"}, StatusAuthError, "auth_error"},
+		{"second classifier", fmt.Errorf("ping: %w", &driverError{code: "53300"}), StatusConnectionError, "too_many_connections"},
+		{"not recognized", &driverError{code: "42P01"}, StatusError, "error"},
+		{"before sentinels", fmt.Errorf("%w: %w", ErrTimeout, &driverError{code: "28P01"}), StatusAuthError, "auth_error"},
+		{"after ClassifiedError", &ClassifiedCheckError{Category: StatusUnhealthy, Detail: "unhealthy", Cause: &driverError{code: "28P01"}}, StatusUnhealthy, "unhealthy"},
+		{"nil error", nil, StatusOK, "ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := classifyError(tt.err, authFailed, tooMany)
+			if r.Category != tt.wantCategory || r.Detail != tt.wantDetail {
+				t.Errorf("classifyError(%v) = %s/%s, expected %s/%s", tt.err, r.Category, r.Detail, tt.wantCategory, tt.wantDetail)
+			}
+		})
+	}
+}
+
+// resetErrorClassifiers restores the global classifier registries after the test.
+func resetErrorClassifiers(t *testing.T) {
+	t.Helper()
+	errorClassifiersMu.Lock()
+	oldGlobal, oldTyped := errorClassifiers, typeErrorClassifiers
+	errorClassifiers, typeErrorClassifiers = nil, map[DependencyType][]ErrorClassifier{}
+	errorClassifiersMu.Unlock()
+	t.Cleanup(func() {
+		errorClassifiersMu.Lock()
+		errorClassifiers, typeErrorClassifiers = oldGlobal, oldTyped
+		errorClassifiersMu.Unlock()
+	})
+}
+
+func TestErrorClassifiersFor_ScopeOrder(t *testing.T) {
+	resetErrorClassifiers(t)
+
+	classifier := func(detail string) ErrorClassifier {
+		return func(error) (CheckResult, bool) {
+			return CheckResult{Category: StatusError, Detail: detail}, true
+		}
+	}
+	RegisterErrorClassifier(classifier("global"))
+	RegisterErrorClassifier(nil)
+	RegisterTypeErrorClassifier(TypePostgres, classifier("postgres"))
+	RegisterTypeErrorClassifier(TypeRedis, classifier("redis"))
+
+	err := errors.New("boom")
+	tests := []struct {
+		name string
+		dep  Dependency
+		want string
+	}{
+		{"global only", Dependency{Type: TypeHTTP}, "global"},
+		{"type before global", Dependency{Type: TypePostgres}, "postgres"},
+		{"dependency before type", Dependency{
+			Type:   TypePostgres,
+			Config: CheckConfig{ErrorClassifiers: []ErrorClassifier{classifier("dependency")}},
+		}, "dependency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(err, errorClassifiersFor(tt.dep)...).Detail; got != tt.want {
+				t.Errorf("detail = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+
+	if n := len(errorClassifiersFor(Dependency{Type: TypePostgres})); n != 2 {
+		t.Errorf("expected 2 classifiers for postgres, got %d", n)
+	}
+}
@@ -0,0 +1,28 @@
+// Package clickhousedb provides dephealth integration with *sql.DB pools
+// opened by clickhouse.OpenDB (ClickHouse). It is kept apart from
+// contrib/sqldb so that PostgreSQL and MySQL users do not pull in the
+// ClickHouse driver.
+package clickhousedb
+
+import (
+	"database/sql"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/clickhousecheck"
+)
+
+// FromDB creates an Option for monitoring ClickHouse via an existing
+// *sql.DB (clickhouse.OpenDB). dephealth.WithClickHouseMaxReplicaDelay is
+// honoured; connection options (auth, protocol, TLS) are taken from the pool.
+// The user must provide FromURL or FromParams to determine metric labels.
+func FromDB(name string, db *sql.DB, opts ...dephealth.DependencyOption) dephealth.Option {
+	dc := &dephealth.DependencyConfig{}
+	for _, o := range opts {
+		o(dc)
+	}
+	checker := clickhousecheck.New(
+		clickhousecheck.WithDB(db),
+		clickhousecheck.WithMaxReplicaDelay(dc.ClickHouseMaxReplicaDelay),
+	)
+	return dephealth.AddDependency(name, dephealth.TypeClickHouse, checker, opts...)
+}
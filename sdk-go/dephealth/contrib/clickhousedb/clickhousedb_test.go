@@ -0,0 +1,52 @@
+package clickhousedb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+)
+
+func TestFromDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	reg := prometheus.NewRegistry()
+	dh, err := dephealth.New("test-app", "test-group",
+		dephealth.WithRegisterer(reg),
+		FromDB("clickhouse-main", db,
+			dephealth.WithClickHouseMaxReplicaDelay(time.Minute),
+			dephealth.FromParams("clickhouse.svc", "9000"),
+			dephealth.Critical(false),
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to create DepHealth: %v", err)
+	}
+	_ = dh
+}
+
+func TestFromDB_MissingAddr(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	reg := prometheus.NewRegistry()
+	_, err = dephealth.New("test-app", "test-group",
+		dephealth.WithRegisterer(reg),
+		FromDB("clickhouse-main", db, dephealth.Critical(false)),
+	)
+	if err == nil {
+		t.Fatal("expected error when address is missing")
+	}
+}
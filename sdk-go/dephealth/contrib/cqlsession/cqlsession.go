@@ -0,0 +1,34 @@
+// Package cqlsession provides dephealth integration with *gocql.Session (Cassandra).
+// It allows using an existing service session for health checks.
+package cqlsession
+
+import (
+	"strings"
+
+	"github.com/gocql/gocql"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/checks/cassandracheck"
+)
+
+// FromSession creates an Option for monitoring Cassandra via an existing *gocql.Session.
+// dephealth.WithCassandraConsistency is honoured (an invalid level makes
+// dephealth.New fail); authentication and TLS are taken from the session.
+// The user must provide FromURL or FromParams to determine metric labels.
+func FromSession(name string, session *gocql.Session, opts ...dephealth.DependencyOption) dephealth.Option {
+	dc := &dephealth.DependencyConfig{}
+	for _, o := range opts {
+		o(dc)
+	}
+
+	checkerOpts := []cassandracheck.Option{cassandracheck.WithSession(session)}
+	if dc.CassandraConsistency != "" {
+		// An invalid level is reported by AddDependency.
+		if consistency, err := gocql.ParseConsistencyWrapper(strings.ToUpper(dc.CassandraConsistency)); err == nil {
+			checkerOpts = append(checkerOpts, cassandracheck.WithConsistency(consistency))
+		}
+	}
+
+	checker := cassandracheck.New(checkerOpts...)
+	return dephealth.AddDependency(name, dephealth.TypeCassandra, checker, opts...)
+}
@@ -0,0 +1,60 @@
+package cqlsession
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+)
+
+func TestFromSession(t *testing.T) {
+	// The session is not used until checks start, so a zero value is enough
+	// to verify registration.
+	session := &gocql.Session{}
+
+	reg := prometheus.NewRegistry()
+	dh, err := dephealth.New("test-app", "test-group",
+		dephealth.WithRegisterer(reg),
+		FromSession("cassandra-main", session,
+			dephealth.FromParams("cassandra.svc", "9042"),
+			dephealth.WithCassandraConsistency("LOCAL_ONE"),
+			dephealth.Critical(true),
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to create DepHealth: %v", err)
+	}
+	_ = dh
+}
+
+func TestFromSession_MissingAddr(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	_, err := dephealth.New("test-app", "test-group",
+		dephealth.WithRegisterer(reg),
+		FromSession("cassandra-main", &gocql.Session{}, dephealth.Critical(true)),
+	)
+	if err == nil {
+		t.Fatal("expected error when address is missing")
+	}
+}
+
+func TestFromSession_InvalidConsistency(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	_, err := dephealth.New("test-app", "test-group",
+		dephealth.WithRegisterer(reg),
+		FromSession("cassandra-main", &gocql.Session{},
+			dephealth.FromParams("cassandra.svc", "9042"),
+			dephealth.WithCassandraConsistency("SOMETIMES"),
+			dephealth.Critical(true),
+		),
+	)
+	if err == nil {
+		t.Fatal("expected error for invalid consistency")
+	}
+	if !strings.Contains(err.Error(), "invalid Cassandra consistency") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
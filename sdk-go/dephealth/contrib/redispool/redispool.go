@@ -1,9 +1,13 @@
-// Package redispool provides dephealth integration with *redis.Client.
+// Package redispool provides dephealth integration with go-redis clients
+// (*redis.Client, *redis.ClusterClient and redis.UniversalClient).
 // It automatically extracts host:port from the client for metric labels.
 package redispool
 
 import (
+	"maps"
 	"net"
+	"slices"
+	"strings"
 
 	"github.com/redis/go-redis/v9"
 
@@ -33,3 +37,43 @@ func FromClient(name string, client *redis.Client, opts ...dephealth.DependencyO
 
 	return dephealth.AddDependency(name, dephealth.TypeRedis, checker, allOpts...)
 }
+
+// FromClusterClient creates an Option for monitoring a Redis Cluster via an
+// existing *redis.ClusterClient. Each address in client.Options().Addrs
+// becomes an endpoint, checked by its own connection to that node (built
+// from the client's credentials, TLS and timeouts): CLUSTER INFO must report
+// cluster_state:ok, so a node that is down is reported on its own endpoint.
+func FromClusterClient(name string, client *redis.ClusterClient, opts ...dephealth.DependencyOption) dephealth.Option {
+	checker := redischeck.New(redischeck.WithClient(client))
+	return dephealth.AddDependency(name, dephealth.TypeRedis, checker, withAddrs(client.Options().Addrs, opts)...)
+}
+
+// FromUniversalClient creates an Option for monitoring Redis via a
+// redis.UniversalClient, dispatching on the concrete client type. The
+// shards of a *redis.Ring are checked one by one, like cluster nodes.
+// A failover (Sentinel) client does not expose its sentinel addresses:
+// pass dephealth.FromURL or dephealth.FromParams to set the endpoints.
+func FromUniversalClient(name string, client redis.UniversalClient, opts ...dephealth.DependencyOption) dephealth.Option {
+	switch c := client.(type) {
+	case *redis.ClusterClient:
+		return FromClusterClient(name, c, opts...)
+	case *redis.Client:
+		return FromClient(name, c, opts...)
+	case *redis.Ring:
+		addrs := slices.Sorted(maps.Values(c.Options().Addrs))
+		checker := redischeck.New(redischeck.WithClient(c))
+		return dephealth.AddDependency(name, dephealth.TypeRedis, checker, withAddrs(addrs, opts)...)
+	default:
+		checker := redischeck.New(redischeck.WithClient(client))
+		return dephealth.AddDependency(name, dephealth.TypeRedis, checker, opts...)
+	}
+}
+
+// withAddrs prepends a multi-host URL built from addrs to opts (user can override).
+func withAddrs(addrs []string, opts []dephealth.DependencyOption) []dephealth.DependencyOption {
+	allOpts := make([]dephealth.DependencyOption, 0, len(opts)+1)
+	if len(addrs) > 0 {
+		allOpts = append(allOpts, dephealth.FromURL("redis://"+strings.Join(addrs, ",")))
+	}
+	return append(allOpts, opts...)
+}
@@ -107,3 +107,57 @@ func TestFromClient_AutoExtractsAddr(t *testing.T) {
 
 	dh.Stop()
 }
+
+func TestFromClusterClient_Endpoints(t *testing.T) {
+	client := redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs: []string{"10.0.0.1:7000", "10.0.0.2:7001"},
+	})
+	defer func() { _ = client.Close() }()
+
+	dh, err := dephealth.New("test-app", "test-group",
+		dephealth.WithRegisterer(prometheus.NewRegistry()),
+		FromClusterClient("redis-cluster", client, dephealth.Critical(true)),
+	)
+	if err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+	if err := dh.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer dh.Stop()
+
+	details := dh.HealthDetails()
+	for _, key := range []string{"redis-cluster:10.0.0.1:7000", "redis-cluster:10.0.0.2:7001"} {
+		if _, ok := details[key]; !ok {
+			t.Errorf("expected endpoint %s, got %v", key, details)
+		}
+	}
+}
+
+func TestFromUniversalClient(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	client := redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs: []string{mr.Addr()},
+	})
+	defer func() { _ = client.Close() }()
+
+	dh, err := dephealth.New("test-app", "test-group",
+		dephealth.WithRegisterer(prometheus.NewRegistry()),
+		FromUniversalClient("redis-cache", client, dephealth.Critical(false)),
+	)
+	if err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+	if err := dh.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer dh.Stop()
+
+	time.Sleep(200 * time.Millisecond)
+
+	key := "redis-cache:" + mr.Host() + ":" + mr.Port()
+	if healthy, ok := dh.Health()[key]; !ok || !healthy {
+		t.Errorf("expected healthy endpoint %s, got %v", key, dh.Health())
+	}
+}
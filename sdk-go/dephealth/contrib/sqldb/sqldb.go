@@ -1,5 +1,6 @@
 // Package sqldb provides dephealth integration with *sql.DB (PostgreSQL, MySQL).
 // It allows using an existing service connection pool for health checks.
+// ClickHouse pools are supported by contrib/clickhousedb.
 package sqldb
 
 import (
@@ -11,15 +12,35 @@ import (
 )
 
 // FromDB creates an Option for monitoring PostgreSQL via an existing *sql.DB.
+// dephealth.WithPostgresRole and dephealth.WithPostgresMaxReplicationLag are
+// honoured; connection options are taken from the pool.
 // The user must provide FromURL or FromParams to determine metric labels.
 func FromDB(name string, db *sql.DB, opts ...dephealth.DependencyOption) dephealth.Option {
-	checker := pgcheck.New(pgcheck.WithDB(db))
+	dc := &dephealth.DependencyConfig{}
+	for _, o := range opts {
+		o(dc)
+	}
+	checker := pgcheck.New(
+		pgcheck.WithDB(db),
+		pgcheck.WithRole(dc.PostgresRole),
+		pgcheck.WithMaxReplicationLag(dc.PostgresMaxReplicationLag),
+	)
 	return dephealth.AddDependency(name, dephealth.TypePostgres, checker, opts...)
 }
 
 // FromMySQLDB creates an Option for monitoring MySQL via an existing *sql.DB.
+// dephealth.WithMySQLRole and dephealth.WithMySQLMaxReplicationLag are
+// honoured; connection options are taken from the pool.
 // The user must provide FromURL or FromParams to determine metric labels.
 func FromMySQLDB(name string, db *sql.DB, opts ...dephealth.DependencyOption) dephealth.Option {
-	checker := mysqlcheck.New(mysqlcheck.WithDB(db))
+	dc := &dephealth.DependencyConfig{}
+	for _, o := range opts {
+		o(dc)
+	}
+	checker := mysqlcheck.New(
+		mysqlcheck.WithDB(db),
+		mysqlcheck.WithRole(dc.MySQLRole),
+		mysqlcheck.WithMaxReplicationLag(dc.MySQLMaxReplicationLag),
+	)
 	return dephealth.AddDependency(name, dephealth.TypeMySQL, checker, opts...)
 }
@@ -1,7 +1,10 @@
 package sqldb
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/prometheus/client_golang/prometheus"
@@ -35,6 +38,66 @@ func TestFromDB(t *testing.T) {
 	_ = dh
 }
 
+func TestFromDB_Replication(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// The pool is a replica although the dependency expects a primary.
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	mock.ExpectQuery("SELECT pg_is_in_recovery").
+		WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery", "lag"}).AddRow(true, 2.5))
+
+	reg := prometheus.NewRegistry()
+	dh, err := dephealth.New("test-app", "test-group",
+		dephealth.WithRegisterer(reg),
+		FromDB("pg-main", db,
+			dephealth.FromParams("pg.svc", "5432"),
+			dephealth.WithPostgresRole("primary"),
+			dephealth.WithPostgresMaxReplicationLag(time.Second),
+			dephealth.Critical(true),
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to create DepHealth: %v", err)
+	}
+	if err := dh.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer dh.Stop()
+
+	es := waitForCheck(t, dh, "pg-main:pg.svc:5432")
+	if es.Detail != "wrong_role" {
+		t.Errorf("Detail = %q, expected %q", es.Detail, "wrong_role")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestFromDB_InvalidRole(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	reg := prometheus.NewRegistry()
+	_, err = dephealth.New("test-app", "test-group",
+		dephealth.WithRegisterer(reg),
+		FromDB("pg-main", db,
+			dephealth.FromParams("pg.svc", "5432"),
+			dephealth.WithPostgresRole("standby"),
+			dephealth.Critical(true),
+		),
+	)
+	if err == nil || !strings.Contains(err.Error(), "invalid role") {
+		t.Fatalf("expected invalid role error, got: %v", err)
+	}
+}
+
 func TestFromDB_MissingAddr(t *testing.T) {
 	db, _, err := sqlmock.New()
 	if err != nil {
@@ -74,3 +137,56 @@ func TestFromMySQLDB(t *testing.T) {
 	}
 	_ = dh
 }
+
+func TestFromMySQLDB_Replication(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// The pool is writable although the dependency expects a replica.
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	mock.ExpectQuery("SELECT @@global.read_only").
+		WillReturnRows(sqlmock.NewRows([]string{"@@global.read_only"}).AddRow(false))
+
+	reg := prometheus.NewRegistry()
+	dh, err := dephealth.New("test-app", "test-group",
+		dephealth.WithRegisterer(reg),
+		FromMySQLDB("mysql-replica", db,
+			dephealth.FromParams("mysql.svc", "3306"),
+			dephealth.WithMySQLRole("replica"),
+			dephealth.WithMySQLMaxReplicationLag(time.Minute),
+			dephealth.Critical(false),
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to create DepHealth: %v", err)
+	}
+	if err := dh.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer dh.Stop()
+
+	es := waitForCheck(t, dh, "mysql-replica:mysql.svc:3306")
+	if es.Detail != "wrong_role" {
+		t.Errorf("Detail = %q, expected %q", es.Detail, "wrong_role")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// waitForCheck polls HealthDetails until the first check of key completes.
+func waitForCheck(t *testing.T, dh *dephealth.DepHealth, key string) dephealth.EndpointStatus {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if es, ok := dh.HealthDetails()[key]; ok && es.Healthy != nil {
+			return es
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("no check result for %s", key)
+	return dephealth.EndpointStatus{}
+}
@@ -0,0 +1,206 @@
+package dephealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialProvider supplies a secret (a bearer token or a password) to a
+// checker. Checkers call Credential before every check instead of capturing
+// the secret once, so a rotated secret takes effect without restarting the
+// service or re-creating the checker.
+//
+// Credential is called concurrently for the endpoints of a dependency and
+// must be safe for concurrent use.
+type CredentialProvider interface {
+	Credential(ctx context.Context) (string, error)
+}
+
+// CredentialFunc adapts an ordinary function to a CredentialProvider.
+type CredentialFunc func(ctx context.Context) (string, error)
+
+// Credential calls f(ctx).
+func (f CredentialFunc) Credential(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// ResolveCredential returns the secret of p, or fallback when p is nil. A
+// provider failure is returned as a ClassifiedCheckError with category
+// error and detail credential_error, so checkers can return it as is.
+func ResolveCredential(ctx context.Context, p CredentialProvider, fallback string) (string, error) {
+	if p == nil {
+		return fallback, nil
+	}
+	secret, err := p.Credential(ctx)
+	if err != nil {
+		return "", &ClassifiedCheckError{
+			Category: StatusError,
+			Detail:   "credential_error",
+			Cause:    fmt.Errorf("credential provider: %w", err),
+		}
+	}
+	return secret, nil
+}
+
+// EnvCredential returns a provider that reads the environment variable name
+// on every call. An unset or empty variable is an error.
+func EnvCredential(name string) CredentialProvider {
+	return CredentialFunc(func(context.Context) (string, error) {
+		v := os.Getenv(name)
+		if v == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return v, nil
+	})
+}
+
+// FileCredential returns a provider that reads the secret from a file, such
+// as a Kubernetes secret mount. The file is read again when its size or
+// modification time changes; leading and trailing whitespace is trimmed.
+func FileCredential(path string) CredentialProvider {
+	return &fileCredential{path: path}
+}
+
+type fileCredential struct {
+	path string
+
+	mu      sync.Mutex
+	loaded  bool
+	size    int64
+	modTime int64 // UnixNano
+	secret  string
+}
+
+func (f *fileCredential) Credential(context.Context) (string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.loaded && info.Size() == f.size && info.ModTime().UnixNano() == f.modTime {
+		return f.secret, nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", err
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("credential file %s is empty", f.path)
+	}
+	f.loaded, f.size, f.modTime, f.secret = true, info.Size(), info.ModTime().UnixNano(), secret
+	return secret, nil
+}
+
+// oauth2ExpiryMargin is how long before its expiry a cached OAuth2 token is
+// refreshed, so that it does not expire during a check. For short-lived
+// tokens it is clamped to a quarter of the lifetime (see oauth2RefreshMargin).
+const oauth2ExpiryMargin = 30 * time.Second
+
+// oauth2RefreshMargin returns the refresh margin for a token valid for
+// lifetime: oauth2ExpiryMargin, but at most a quarter of the lifetime, so
+// that a token shorter-lived than the margin is still reused between checks.
+func oauth2RefreshMargin(lifetime time.Duration) time.Duration {
+	return min(oauth2ExpiryMargin, lifetime/4)
+}
+
+// OAuth2ClientCredentials returns a provider that obtains an access token
+// from tokenURL with the OAuth 2.0 client credentials grant (RFC 6749,
+// section 4.4), authenticating with HTTP Basic. The token is cached until
+// shortly before expires_in elapses; a token without expires_in is cached
+// indefinitely. Use it as a bearer token provider.
+func OAuth2ClientCredentials(tokenURL, clientID, clientSecret string, scopes ...string) CredentialProvider {
+	return &oauth2Credential{
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+		client:       http.DefaultClient,
+		now:          time.Now,
+	}
+}
+
+type oauth2Credential struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	client       *http.Client
+	now          func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time // zero = does not expire
+}
+
+// oauth2TokenResponse is the token endpoint response (RFC 6749, section 5).
+type oauth2TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Error       string `json:"error"`
+}
+
+func (o *oauth2Credential) Credential(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.token != "" && (o.expiry.IsZero() || o.now().Before(o.expiry)) {
+		return o.token, nil
+	}
+
+	token, expiresIn, err := o.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	o.token, o.expiry = token, time.Time{}
+	if expiresIn > 0 {
+		lifetime := time.Duration(expiresIn) * time.Second
+		o.expiry = o.now().Add(lifetime - oauth2RefreshMargin(lifetime))
+	}
+	return token, nil
+}
+
+// fetch requests a new token from the token endpoint.
+func (o *oauth2Credential) fetch(ctx context.Context) (string, int64, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(o.scopes) > 0 {
+		form.Set("scope", strings.Join(o.scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("oauth2 token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(o.clientID), url.QueryEscape(o.clientSecret))
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("oauth2 token request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var tr oauth2TokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&tr); err != nil && resp.StatusCode == http.StatusOK {
+		return "", 0, fmt.Errorf("oauth2 token response: %w", err)
+	}
+	switch {
+	case resp.StatusCode != http.StatusOK && tr.Error != "":
+		return "", 0, fmt.Errorf("oauth2 token endpoint returned %d: %s", resp.StatusCode, tr.Error)
+	case resp.StatusCode != http.StatusOK:
+		return "", 0, fmt.Errorf("oauth2 token endpoint returned %d", resp.StatusCode)
+	case tr.AccessToken == "":
+		return "", 0, errors.New("oauth2 token response has no access_token")
+	}
+	return tr.AccessToken, tr.ExpiresIn, nil
+}
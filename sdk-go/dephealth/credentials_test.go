@@ -0,0 +1,224 @@
+package dephealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestResolveCredential(t *testing.T) {
+	got, err := ResolveCredential(context.Background(), nil, "static")
+	if err != nil || got != "static" {
+		t.Errorf("nil provider: got %q, %v; expected the fallback", got, err)
+	}
+
+	p := CredentialFunc(func(context.Context) (string, error) { return "dynamic", nil })
+	got, err = ResolveCredential(context.Background(), p, "static")
+	if err != nil || got != "dynamic" {
+		t.Errorf("provider: got %q, %v; expected %q", got, err, "dynamic")
+	}
+
+	cause := errors.New("vault sealed")
+	failing := CredentialFunc(func(context.Context) (string, error) { return "", cause })
+	_, err = ResolveCredential(context.Background(), failing, "static")
+	if !errors.Is(err, cause) {
+		t.Fatalf("expected the provider error to be wrapped, got %v", err)
+	}
+	if got := classifyError(err); got.Category != StatusError || got.Detail != "credential_error" {
+		t.Errorf("classification = %+v, expected error/credential_error", got)
+	}
+}
+
+func TestEnvCredential(t *testing.T) {
+	t.Setenv("DEPHEALTH_TEST_TOKEN", "first")
+	p := EnvCredential("DEPHEALTH_TEST_TOKEN")
+	if got, err := p.Credential(context.Background()); err != nil || got != "first" {
+		t.Errorf("got %q, %v; expected %q", got, err, "first")
+	}
+
+	t.Setenv("DEPHEALTH_TEST_TOKEN", "rotated")
+	if got, _ := p.Credential(context.Background()); got != "rotated" {
+		t.Errorf("got %q after change, expected %q", got, "rotated")
+	}
+
+	t.Setenv("DEPHEALTH_TEST_TOKEN", "")
+	if _, err := p.Credential(context.Background()); err == nil {
+		t.Error("expected error for an empty variable")
+	}
+}
+
+func TestFileCredential(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	write := func(content string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	write("first\n", now)
+	p := FileCredential(path)
+	if got, err := p.Credential(context.Background()); err != nil || got != "first" {
+		t.Fatalf("got %q, %v; expected %q", got, err, "first")
+	}
+
+	write("rotated\n", now.Add(time.Minute))
+	if got, _ := p.Credential(context.Background()); got != "rotated" {
+		t.Errorf("got %q after rotation, expected %q", got, "rotated")
+	}
+
+	write("  \n", now.Add(2*time.Minute))
+	if _, err := p.Credential(context.Background()); err == nil {
+		t.Error("expected error for an empty file")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Credential(context.Background()); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
+
+// startTokenServer starts an OAuth2 token endpoint that issues numbered
+// tokens valid for expiresIn seconds.
+func startTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var issued atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "client" || pass != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		n := issued.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d-%s", n, r.PostForm.Get("scope")),
+			"token_type":   "Bearer",
+			"expires_in":   expiresIn,
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &issued
+}
+
+func TestOAuth2ClientCredentials_CachesToken(t *testing.T) {
+	srv, issued := startTokenServer(t, 3600)
+	p := OAuth2ClientCredentials(srv.URL, "client", "s3cret", "health.read", "metrics").(*oauth2Credential)
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	for range 3 {
+		got, err := p.Credential(context.Background())
+		if err != nil {
+			t.Fatalf("Credential: %v", err)
+		}
+		if got != "token-1-health.read metrics" {
+			t.Errorf("token = %q, expected %q", got, "token-1-health.read metrics")
+		}
+	}
+	if n := issued.Load(); n != 1 {
+		t.Errorf("token endpoint called %d times, expected 1", n)
+	}
+
+	// Shortly before expiry the token is refreshed.
+	now = now.Add(time.Hour - oauth2ExpiryMargin)
+	if got, _ := p.Credential(context.Background()); !strings.HasPrefix(got, "token-2") {
+		t.Errorf("token = %q, expected a refreshed token", got)
+	}
+}
+
+func TestOAuth2ClientCredentials_ShortLivedToken(t *testing.T) {
+	// expires_in (20s) is shorter than oauth2ExpiryMargin: the margin is
+	// clamped to a quarter of the lifetime instead of refreshing every call.
+	srv, issued := startTokenServer(t, 20)
+	p := OAuth2ClientCredentials(srv.URL, "client", "s3cret").(*oauth2Credential)
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	for _, elapsed := range []time.Duration{0, 5 * time.Second, 14 * time.Second} {
+		now = now.Add(elapsed)
+		if _, err := p.Credential(context.Background()); err != nil {
+			t.Fatalf("Credential: %v", err)
+		}
+		now = now.Add(-elapsed)
+	}
+	if n := issued.Load(); n != 1 {
+		t.Errorf("token endpoint called %d times, expected 1", n)
+	}
+
+	now = now.Add(15 * time.Second)
+	if got, _ := p.Credential(context.Background()); !strings.HasPrefix(got, "token-2") {
+		t.Errorf("token = %q, expected a refreshed token", got)
+	}
+}
+
+func TestOAuth2RefreshMargin(t *testing.T) {
+	tests := []struct {
+		lifetime time.Duration
+		want     time.Duration
+	}{
+		{time.Hour, oauth2ExpiryMargin},
+		{2 * time.Minute, oauth2ExpiryMargin},
+		{time.Minute, 15 * time.Second},
+		{4 * time.Second, time.Second},
+	}
+	for _, tt := range tests {
+		if got := oauth2RefreshMargin(tt.lifetime); got != tt.want {
+			t.Errorf("oauth2RefreshMargin(%v) = %v, expected %v", tt.lifetime, got, tt.want)
+		}
+	}
+}
+
+func TestOAuth2ClientCredentials_Errors(t *testing.T) {
+	srv, _ := startTokenServer(t, 3600)
+
+	_, err := OAuth2ClientCredentials(srv.URL, "client", "wrong").Credential(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("expected invalid_client error, got %v", err)
+	}
+
+	empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"token_type":"Bearer"}`))
+	}))
+	defer empty.Close()
+	_, err = OAuth2ClientCredentials(empty.URL, "client", "s3cret").Credential(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "access_token") {
+		t.Errorf("expected missing access_token error, got %v", err)
+	}
+}
+
+func TestNew_LDAPSimpleBindPasswordProvider(t *testing.T) {
+	registerMockFactory(t, TypeLDAP, &mockChecker{})
+	_, err := New("test-app", "test-group",
+		WithRegisterer(prometheus.NewRegistry()),
+		LDAP("directory", FromURL("ldap://ldap.svc:389"), Critical(true),
+			WithLDAPCheckMethod("simple_bind"),
+			WithLDAPBindDN("cn=monitor,dc=example,dc=org"),
+			WithLDAPBindPasswordProvider(FileCredential("/var/run/secrets/ldap")),
+		),
+	)
+	if err != nil {
+		t.Errorf("expected a password provider to satisfy simple_bind, got %v", err)
+	}
+}
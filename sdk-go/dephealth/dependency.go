@@ -31,19 +31,37 @@ const (
 	TypeKafka DependencyType = "kafka"
 	// TypeLDAP represents an LDAP dependency.
 	TypeLDAP DependencyType = "ldap"
+	// TypeEtcd represents an etcd dependency.
+	TypeEtcd DependencyType = "etcd"
+	// TypeConsul represents a Consul dependency.
+	TypeConsul DependencyType = "consul"
+	// TypeClickHouse represents a ClickHouse dependency.
+	TypeClickHouse DependencyType = "clickhouse"
+	// TypeCassandra represents a Cassandra (CQL) dependency.
+	TypeCassandra DependencyType = "cassandra"
+	// TypeTLS represents a TLS-only endpoint checked by a handshake.
+	TypeTLS DependencyType = "tls"
+	// TypeDNS represents a DNS resolver checked by a direct query.
+	TypeDNS DependencyType = "dns"
 )
 
 // ValidTypes contains all valid dependency types.
 var ValidTypes = map[DependencyType]bool{
-	TypeHTTP:     true,
-	TypeGRPC:     true,
-	TypeTCP:      true,
-	TypePostgres: true,
-	TypeMySQL:    true,
-	TypeRedis:    true,
-	TypeAMQP:     true,
-	TypeKafka:    true,
-	TypeLDAP:     true,
+	TypeHTTP:       true,
+	TypeGRPC:       true,
+	TypeTCP:        true,
+	TypePostgres:   true,
+	TypeMySQL:      true,
+	TypeRedis:      true,
+	TypeAMQP:       true,
+	TypeKafka:      true,
+	TypeLDAP:       true,
+	TypeEtcd:       true,
+	TypeConsul:     true,
+	TypeClickHouse: true,
+	TypeCassandra:  true,
+	TypeTLS:        true,
+	TypeDNS:        true,
 }
 
 // Default and boundary values for health check scheduling (from specification).
@@ -106,6 +124,20 @@ type CheckConfig struct {
 	InitialDelay     time.Duration
 	FailureThreshold int
 	SuccessThreshold int
+
+	// TLSExpiryWarning is the window before certificate expiry in which a
+	// successful check reports detail "tls_expiring". Zero disables the warning.
+	TLSExpiryWarning time.Duration
+
+	// LatencySLO is the latency above which a successful check counts as
+	// slow. FailureThreshold consecutive slow checks set the status to
+	// "degraded" (detail "latency_slo_exceeded"), SuccessThreshold
+	// consecutive fast ones clear it. Zero disables the SLO.
+	LatencySLO time.Duration
+
+	// ErrorClassifiers are consulted, in order, before the per-type and
+	// global classifiers when a check fails (see WithErrorClassifier).
+	ErrorClassifiers []ErrorClassifier
 }
 
 // DefaultCheckConfig returns CheckConfig with default values from specification.
@@ -139,6 +171,15 @@ func (c CheckConfig) Validate() error {
 	if c.SuccessThreshold < MinThreshold || c.SuccessThreshold > MaxThreshold {
 		return fmt.Errorf("successThreshold %d out of range [%d, %d]", c.SuccessThreshold, MinThreshold, MaxThreshold)
 	}
+	if c.TLSExpiryWarning < 0 {
+		return fmt.Errorf("tlsExpiryWarning %s must not be negative", c.TLSExpiryWarning)
+	}
+	if c.LatencySLO < 0 {
+		return fmt.Errorf("latencySLO %s must not be negative", c.LatencySLO)
+	}
+	if c.LatencySLO > 0 && c.LatencySLO >= c.Timeout {
+		return fmt.Errorf("latencySLO %s must be less than timeout %s", c.LatencySLO, c.Timeout)
+	}
 	return nil
 }
 
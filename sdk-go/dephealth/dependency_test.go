@@ -155,6 +155,28 @@ func TestCheckConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "latency SLO >= timeout",
+			config: CheckConfig{
+				Interval:         15 * time.Second,
+				Timeout:          5 * time.Second,
+				FailureThreshold: 1,
+				SuccessThreshold: 1,
+				LatencySLO:       5 * time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative latency SLO",
+			config: CheckConfig{
+				Interval:         15 * time.Second,
+				Timeout:          5 * time.Second,
+				FailureThreshold: 1,
+				SuccessThreshold: 1,
+				LatencySLO:       -time.Second,
+			},
+			wantErr: true,
+		},
 		{
 			name: "custom valid config",
 			config: CheckConfig{
@@ -87,6 +87,7 @@ func New(name string, group string, opts ...Option) (*DepHealth, error) {
 	if cfg.timeout > 0 {
 		globalCfg.Timeout = cfg.timeout
 	}
+	globalCfg.TLSExpiryWarning = cfg.tlsWarning
 
 	// Create Scheduler.
 	var schedOpts []SchedulerOption
@@ -145,14 +146,17 @@ func (dh *DepHealth) Health() map[string]bool {
 }
 
 // HealthDetails returns the detailed health state of all endpoints.
-// Key is "dependency:host:port", value is EndpointStatus with 11 fields.
+// Key is "dependency:host:port", value is EndpointStatus.
 // Unlike Health(), UNKNOWN endpoints (before first check) are included.
 func (dh *DepHealth) HealthDetails() map[string]EndpointStatus {
 	return dh.scheduler.HealthDetails()
 }
 
 // AddEndpoint dynamically adds a new health-checked endpoint at runtime.
-// The endpoint inherits the global check interval and timeout configured on the DepHealth instance.
+// The endpoint inherits the check configuration of the dependency registered
+// under depName and depType (interval, timeout, latency SLO, error
+// classifiers), or the global check interval and timeout configured on the
+// DepHealth instance when there is none.
 // If the endpoint already exists (same depName:host:port), the call is a no-op.
 func (dh *DepHealth) AddEndpoint(depName string, depType DependencyType, critical bool, ep Endpoint, checker HealthChecker) error {
 	if err := ValidateName(depName); err != nil {
@@ -182,7 +186,8 @@ func (dh *DepHealth) RemoveEndpoint(depName, host, port string) error {
 
 // UpdateEndpoint atomically replaces an existing endpoint with a new one.
 // The old endpoint's goroutine is cancelled and its metrics are deleted;
-// a new goroutine is started for the new endpoint.
+// a new goroutine is started for the new endpoint with the same check
+// configuration.
 // Returns ErrEndpointNotFound if the old endpoint does not exist.
 func (dh *DepHealth) UpdateEndpoint(depName, oldHost, oldPort string, newEp Endpoint, checker HealthChecker) error {
 	if newEp.Host == "" {
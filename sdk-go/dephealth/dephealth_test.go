@@ -2,6 +2,7 @@ package dephealth
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"strings"
 	"testing"
@@ -427,6 +428,97 @@ func TestNew_GlobalTimeout(t *testing.T) {
 	}
 }
 
+func TestNew_TLSExpiryWarning(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	registerMockFactory(t, TypeHTTP, &mockChecker{})
+
+	dh, err := New("test-app", "test-group",
+		WithRegisterer(reg),
+		WithTLSExpiryWarning(14*24*time.Hour),
+		HTTP("web-api", FromURL("https://api.svc:8443"), Critical(false)),
+		HTTP("auth-api", FromURL("https://auth.svc:8443"), Critical(false),
+			TLSExpiryWarning(72*time.Hour)),
+	)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if got := dh.scheduler.deps[0].dep.Config.TLSExpiryWarning; got != 14*24*time.Hour {
+		t.Errorf("global warning: expected 336h, got %v", got)
+	}
+	if got := dh.scheduler.deps[1].dep.Config.TLSExpiryWarning; got != 72*time.Hour {
+		t.Errorf("per-dep warning should override global: expected 72h, got %v", got)
+	}
+}
+
+func TestNew_TLSExpiryWarning_Negative(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	registerMockFactory(t, TypeHTTP, &mockChecker{})
+
+	_, err := New("test-app", "test-group",
+		WithRegisterer(reg),
+		HTTP("web-api", FromURL("https://api.svc:8443"), Critical(false),
+			TLSExpiryWarning(-time.Hour)),
+	)
+	if err == nil {
+		t.Fatal("expected error for negative TLS expiry warning")
+	}
+}
+
+func TestNew_LatencySLO(t *testing.T) {
+	registerMockFactory(t, TypeHTTP, &mockChecker{})
+
+	dh, err := New("test-app", "test-group",
+		WithRegisterer(prometheus.NewRegistry()),
+		WithTimeout(3*time.Second),
+		HTTP("web-api", FromURL("http://api.svc:8080"), Critical(false),
+			LatencySLO(500*time.Millisecond)),
+	)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if got := dh.scheduler.deps[0].dep.Config.LatencySLO; got != 500*time.Millisecond {
+		t.Errorf("expected latency SLO 500ms, got %v", got)
+	}
+
+	for _, slo := range []time.Duration{-time.Second, 3 * time.Second} {
+		_, err := New("test-app", "test-group",
+			WithRegisterer(prometheus.NewRegistry()),
+			WithTimeout(3*time.Second),
+			HTTP("web-api", FromURL("http://api.svc:8080"), Critical(false),
+				LatencySLO(slo)),
+		)
+		if err == nil {
+			t.Errorf("expected error for latency SLO %v with timeout 3s", slo)
+		}
+	}
+}
+
+func TestNew_WithErrorClassifier(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	registerMockFactory(t, TypeHTTP, &mockChecker{})
+
+	classifier := func(error) (CheckResult, bool) { return CheckResult{}, false }
+	dh, err := New("test-app", "test-group",
+		WithRegisterer(reg),
+		HTTP("web-api", FromURL("http://api.svc:8080"), Critical(false)),
+		HTTP("auth-api", FromURL("http://auth.svc:8080"), Critical(false),
+			WithErrorClassifier(classifier),
+			WithErrorClassifier(nil),
+			WithErrorClassifier(classifier)),
+	)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if n := len(dh.scheduler.deps[0].dep.Config.ErrorClassifiers); n != 0 {
+		t.Errorf("expected no classifiers for web-api, got %d", n)
+	}
+	if n := len(dh.scheduler.deps[1].dep.Config.ErrorClassifiers); n != 2 {
+		t.Errorf("expected 2 classifiers for auth-api, got %d", n)
+	}
+}
+
 func TestNew_HTTPSAutoTLS(t *testing.T) {
 	reg := prometheus.NewRegistry()
 
@@ -458,6 +550,345 @@ func TestNew_HTTPSAutoTLS(t *testing.T) {
 	}
 }
 
+func TestNew_RedisSentinelURL(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	var gotConfig *DependencyConfig
+	old := checkerFactories[TypeRedis]
+	checkerFactories[TypeRedis] = func(dc *DependencyConfig) HealthChecker {
+		gotConfig = dc
+		return &mockChecker{}
+	}
+	t.Cleanup(func() {
+		if old != nil {
+			checkerFactories[TypeRedis] = old
+		} else {
+			delete(checkerFactories, TypeRedis)
+		}
+	})
+
+	dh, err := New("test-app", "test-group",
+		WithRegisterer(reg),
+		Redis("cache", FromURL("redis-sentinel://s1.svc:26379,s2.svc:26379#mymaster"), Critical(true)),
+	)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if gotConfig == nil || gotConfig.RedisMode != "sentinel" || gotConfig.RedisMasterName != "mymaster" {
+		t.Errorf("expected sentinel mode with master from fragment, got %+v", gotConfig)
+	}
+	if got := len(dh.scheduler.deps[0].dep.Endpoints); got != 2 {
+		t.Errorf("expected 2 sentinel endpoints, got %d", got)
+	}
+}
+
+func TestNew_RedissAutoTLS(t *testing.T) {
+	var gotConfig *DependencyConfig
+	old := checkerFactories[TypeRedis]
+	checkerFactories[TypeRedis] = func(dc *DependencyConfig) HealthChecker {
+		gotConfig = dc
+		return &mockChecker{}
+	}
+	t.Cleanup(func() {
+		if old != nil {
+			checkerFactories[TypeRedis] = old
+		} else {
+			delete(checkerFactories, TypeRedis)
+		}
+	})
+
+	_, err := New("test-app", "test-group",
+		WithRegisterer(prometheus.NewRegistry()),
+		Redis("cache", FromURL("rediss://redis.svc:6380/0"), Critical(false)),
+	)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if gotConfig == nil || gotConfig.RedisTLS == nil || !*gotConfig.RedisTLS {
+		t.Error("expected automatic TLS enablement for rediss:// URL")
+	}
+}
+
+func TestNew_RedisConfigValidation(t *testing.T) {
+	registerMockFactory(t, TypeRedis, &mockChecker{})
+
+	tests := []struct {
+		name string
+		opts []DependencyOption
+	}{
+		{"sentinel URL without master", []DependencyOption{FromURL("redis-sentinel://s1.svc:26379")}},
+		{"sentinel option without master", []DependencyOption{FromParams("s1.svc", "26379"), WithRedisSentinel("")}},
+		{"client cert without key", []DependencyOption{FromParams("redis.svc", "6379"), WithRedisTLSClientCert("client.pem", "")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append(tt.opts, Critical(true))
+			_, err := New("test-app", "test-group",
+				WithRegisterer(prometheus.NewRegistry()),
+				Redis("cache", opts...),
+			)
+			if err == nil {
+				t.Error("expected configuration error, got nil")
+			}
+		})
+	}
+}
+
+func TestNew_KafkaConfigValidation(t *testing.T) {
+	registerMockFactory(t, TypeKafka, &mockChecker{})
+
+	tests := []struct {
+		name    string
+		opts    []DependencyOption
+		wantErr string
+	}{
+		{"valid", []DependencyOption{WithKafkaSASL("SCRAM-SHA-256", "app", "secret"), WithKafkaTopic("orders"), WithKafkaMinISR(2)}, ""},
+		{"unknown mechanism", []DependencyOption{WithKafkaSASL("GSSAPI", "app", "secret")}, "invalid kafka SASL mechanism"},
+		{"missing username", []DependencyOption{WithKafkaSASL("PLAIN", "", "secret")}, "requires a username"},
+		{"min ISR without topic", []DependencyOption{WithKafkaMinISR(2)}, "requires a topic"},
+		{"negative min ISR", []DependencyOption{WithKafkaTopic("orders"), WithKafkaMinISR(-1)}, "must not be negative"},
+		{"client cert without key", []DependencyOption{WithKafkaTLSClientCert("client.pem", "")}, "must be specified together"},
+		{"consumer group", []DependencyOption{WithKafkaConsumerGroup("billing", 1000)}, ""},
+		{"max lag without group", []DependencyOption{WithKafkaConsumerGroup("", 1000)}, "requires a consumer group"},
+		{"negative max lag", []DependencyOption{WithKafkaConsumerGroup("billing", -1)}, "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]DependencyOption{FromURL("kafka://broker.svc:9092"), Critical(true)}, tt.opts...)
+			_, err := New("test-app", "test-group",
+				WithRegisterer(prometheus.NewRegistry()),
+				Kafka("events", opts...),
+			)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNew_AMQPConfigValidation(t *testing.T) {
+	registerMockFactory(t, TypeAMQP, &mockChecker{})
+
+	tests := []struct {
+		name    string
+		opts    []DependencyOption
+		wantErr string
+	}{
+		{"valid", []DependencyOption{WithAMQPQueues("orders"), WithAMQPMaxQueueDepth(1000), WithAMQPMinConsumers(1)}, ""},
+		{"channel only", []DependencyOption{WithAMQPChannel()}, ""},
+		{"depth without queues", []DependencyOption{WithAMQPMaxQueueDepth(1000)}, "require queues"},
+		{"consumers without queues", []DependencyOption{WithAMQPMinConsumers(1)}, "require queues"},
+		{"negative depth", []DependencyOption{WithAMQPQueues("orders"), WithAMQPMaxQueueDepth(-1)}, "must not be negative"},
+		{"empty queue name", []DependencyOption{WithAMQPQueues("")}, "must not be empty"},
+		{"client cert without key", []DependencyOption{WithAMQPTLSClientCert("client.pem", "")}, "must be specified together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]DependencyOption{FromURL("amqp://rabbit.svc:5672/"), Critical(true)}, tt.opts...)
+			_, err := New("test-app", "test-group",
+				WithRegisterer(prometheus.NewRegistry()),
+				AMQP("rabbitmq", opts...),
+			)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNew_HTTPAssertionValidation(t *testing.T) {
+	registerMockFactory(t, TypeHTTP, &mockChecker{})
+
+	tests := []struct {
+		name    string
+		opts    []DependencyOption
+		wantErr string
+	}{
+		{"valid", []DependencyOption{
+			WithHTTPMethod("POST"), WithHTTPRequestBody("application/json", []byte(`{}`)),
+			WithHTTPExpectedStatus(200, 204), WithHTTPMaxRedirects(0), WithHTTPMaxBodySize(4096),
+			WithHTTPBodyContains("UP"), WithHTTPBodyRegex(`"status":\s*"UP"`), WithHTTPBodyJSONPath("$.status", "UP"),
+		}, ""},
+		{"invalid method", []DependencyOption{WithHTTPMethod("GET /")}, "invalid HTTP method"},
+		{"invalid status", []DependencyOption{WithHTTPExpectedStatus(200, 999)}, "must be 100-599"},
+		{"negative redirects", []DependencyOption{WithHTTPMaxRedirects(-1)}, "must not be negative"},
+		{"negative body size", []DependencyOption{WithHTTPMaxBodySize(-1)}, "must not be negative"},
+		{"invalid regex", []DependencyOption{WithHTTPBodyRegex("(")}, "invalid HTTP body regex"},
+		{"invalid JSONPath", []DependencyOption{WithHTTPBodyJSONPath("status", "UP")}, "invalid JSONPath"},
+		{"response format", []DependencyOption{WithHTTPResponseFormat("spring")}, ""},
+		{"invalid response format", []DependencyOption{WithHTTPResponseFormat("xml")}, "invalid HTTP response format"},
+		{"topology depth", []DependencyOption{WithHTTPTopologyDepth(2)}, ""},
+		{"negative topology depth", []DependencyOption{WithHTTPTopologyDepth(-1)}, "HTTP topology depth must not be negative"},
+		{"topology depth with spring", []DependencyOption{WithHTTPResponseFormat("spring"), WithHTTPTopologyDepth(1)}, "requires the dephealth or auto response format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]DependencyOption{FromURL("http://api.svc:8080/health"), Critical(true)}, tt.opts...)
+			_, err := New("test-app", "test-group",
+				WithRegisterer(prometheus.NewRegistry()),
+				HTTP("api", opts...),
+			)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNew_PostgresReplicationValidation(t *testing.T) {
+	registerMockFactory(t, TypePostgres, &mockChecker{})
+
+	tests := []struct {
+		name    string
+		opts    []DependencyOption
+		wantErr string
+	}{
+		{"replica with lag", []DependencyOption{WithPostgresRole("replica"), WithPostgresMaxReplicationLag(time.Minute)}, ""},
+		{"primary", []DependencyOption{WithPostgresRole("primary")}, ""},
+		{"unknown role", []DependencyOption{WithPostgresRole("standby")}, "invalid role"},
+		{"negative lag", []DependencyOption{WithPostgresMaxReplicationLag(-time.Second)}, "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]DependencyOption{FromURL("postgres://pg.svc:5432/app"), Critical(true)}, tt.opts...)
+			_, err := New("test-app", "test-group",
+				WithRegisterer(prometheus.NewRegistry()),
+				Postgres("pg-replica", opts...),
+			)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNew_DatabaseCredentialsFromEnv(t *testing.T) {
+	var gotConfig *DependencyConfig
+	old := checkerFactories[TypePostgres]
+	checkerFactories[TypePostgres] = func(dc *DependencyConfig) HealthChecker {
+		gotConfig = dc
+		return &mockChecker{}
+	}
+	t.Cleanup(func() {
+		if old != nil {
+			checkerFactories[TypePostgres] = old
+		} else {
+			delete(checkerFactories, TypePostgres)
+		}
+	})
+
+	t.Setenv("DEPHEALTH_PG_MAIN_USER", "env-user")
+	t.Setenv("DEPHEALTH_PG_MAIN_PASSWORD", "env-secret")
+	t.Setenv("DEPHEALTH_PG_MAIN_DATABASE", "orders")
+	t.Setenv("DEPHEALTH_PG_MAIN_SSLMODE", "verify-full")
+	t.Setenv("DEPHEALTH_PG_MAIN_TLS_CA", "/etc/pg/ca.pem")
+	t.Setenv("DEPHEALTH_PG_MAIN_APPLICATION_NAME", "billing")
+
+	_, err := New("test-app", "test-group",
+		WithRegisterer(prometheus.NewRegistry()),
+		Postgres("pg-main",
+			FromURL("postgres://pg-0.svc:5432,pg-1.svc:5432/app"),
+			Critical(true),
+			WithPostgresCredentials("api-user", ""),
+		),
+	)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if gotConfig.PostgresUser != "api-user" {
+		t.Errorf("PostgresUser = %q, expected API value to win", gotConfig.PostgresUser)
+	}
+	if gotConfig.PostgresPassword != "env-secret" || gotConfig.PostgresDatabase != "orders" ||
+		gotConfig.PostgresSSLMode != "verify-full" || gotConfig.PostgresTLSCAFile != "/etc/pg/ca.pem" ||
+		gotConfig.PostgresApplicationName != "billing" {
+		t.Errorf("unexpected env config: %+v", gotConfig)
+	}
+}
+
+func TestNew_DatabaseSSLModeValidation(t *testing.T) {
+	registerMockFactory(t, TypePostgres, &mockChecker{})
+	registerMockFactory(t, TypeMySQL, &mockChecker{})
+
+	tests := []struct {
+		name    string
+		opt     Option
+		wantErr string
+	}{
+		{"postgres verify-full", Postgres("pg", FromURL("postgres://pg.svc:5432/app"), Critical(true), WithPostgresSSLMode("verify-full")), ""},
+		{"postgres invalid", Postgres("pg", FromURL("postgres://pg.svc:5432/app"), Critical(true), WithPostgresSSLMode("verify_identity")), "invalid postgres sslmode"},
+		{"mysql verify_identity", MySQL("db", FromURL("mysql://db.svc:3306/app"), Critical(true), WithMySQLSSLMode("VERIFY_IDENTITY")), ""},
+		{"mysql libpq spelling", MySQL("db", FromURL("mysql://db.svc:3306/app"), Critical(true), WithMySQLSSLMode("verify-full")), ""},
+		{"mysql invalid", MySQL("db", FromURL("mysql://db.svc:3306/app"), Critical(true), WithMySQLSSLMode("strict")), "invalid mysql SSL mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New("test-app", "test-group", WithRegisterer(prometheus.NewRegistry()), tt.opt)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNew_MySQLReplicationValidation(t *testing.T) {
+	registerMockFactory(t, TypeMySQL, &mockChecker{})
+
+	_, err := New("test-app", "test-group",
+		WithRegisterer(prometheus.NewRegistry()),
+		MySQL("mysql-replica",
+			FromURL("mysql://mysql.svc:3306/app"),
+			Critical(true),
+			WithMySQLRole("replica"),
+			WithMySQLMaxReplicationLag(time.Minute),
+		),
+	)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	_, err = New("test-app", "test-group",
+		WithRegisterer(prometheus.NewRegistry()),
+		MySQL("mysql-replica",
+			FromURL("mysql://mysql.svc:3306/app"),
+			Critical(true),
+			WithMySQLRole("source"),
+		),
+	)
+	if err == nil || !strings.Contains(err.Error(), "invalid role") {
+		t.Errorf("expected invalid role error, got %v", err)
+	}
+}
+
 func TestNew_AddDependency(t *testing.T) {
 	reg := prometheus.NewRegistry()
 	checker := &mockChecker{}
@@ -573,6 +1004,34 @@ func TestNew_HTTPAuthConflict_BasicAndHeader(t *testing.T) {
 	}
 }
 
+func TestNew_HTTPAuthConflict_Providers(t *testing.T) {
+	token := FileCredential("/var/run/secrets/token")
+	tests := []struct {
+		name string
+		opts []DependencyOption
+	}{
+		{"provider and bearer", []DependencyOption{WithHTTPBearerTokenProvider(token), WithHTTPBearerToken("token")}},
+		{"provider and basic", []DependencyOption{WithHTTPBearerTokenProvider(token), WithHTTPBasicAuthProvider("user", token)}},
+		{"basic provider and header", []DependencyOption{
+			WithHTTPBasicAuthProvider("user", token),
+			WithHTTPHeaders(map[string]string{"Authorization": "custom"}),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registerMockFactory(t, TypeHTTP, &mockChecker{})
+			opts := append([]DependencyOption{FromURL("http://api.svc:8080"), Critical(true)}, tt.opts...)
+			_, err := New("test-app", "test-group",
+				WithRegisterer(prometheus.NewRegistry()),
+				HTTP("web-api", opts...),
+			)
+			if err == nil || !strings.Contains(err.Error(), "conflicting auth methods") {
+				t.Errorf("expected 'conflicting auth methods' error, got: %v", err)
+			}
+		})
+	}
+}
+
 func TestNew_HTTPAuth_SingleMethod_OK(t *testing.T) {
 	reg := prometheus.NewRegistry()
 	registerMockFactory(t, TypeHTTP, &mockChecker{})
@@ -723,6 +1182,296 @@ func TestNew_GRPCAuth_SingleMethod_OK(t *testing.T) {
 	}
 }
 
+func TestNew_EtcdAndConsul(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	registerMockFactory(t, TypeEtcd, &mockChecker{})
+	registerMockFactory(t, TypeConsul, &mockChecker{})
+
+	dh, err := New("test-app", "test-group",
+		WithRegisterer(reg),
+		Etcd("etcd-main",
+			FromURL("etcd://etcd-0:2379,etcd-1:2379,etcd-2:2379"),
+			Critical(true),
+			WithEtcdAuth("root", "secret"),
+			WithEtcdTLSClientCert("client.pem", "client-key.pem"),
+		),
+		Consul("consul",
+			FromURL("consul://consul.svc"),
+			Critical(false),
+			WithConsulToken("token"),
+		),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dh.scheduler.deps) != 2 {
+		t.Fatalf("expected 2 dependencies, got %d", len(dh.scheduler.deps))
+	}
+	if got := len(dh.scheduler.deps[0].dep.Endpoints); got != 3 {
+		t.Errorf("expected 3 etcd endpoints, got %d", got)
+	}
+	if got := dh.scheduler.deps[1].dep.Endpoints[0].Port; got != "8500" {
+		t.Errorf("expected consul default port 8500, got %q", got)
+	}
+}
+
+func TestNew_EtcdClientCertWithoutKey(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	registerMockFactory(t, TypeEtcd, &mockChecker{})
+
+	_, err := New("test-app", "test-group",
+		WithRegisterer(reg),
+		Etcd("etcd-main",
+			FromParams("etcd.svc", "2379"),
+			Critical(true),
+			WithEtcdTLSClientCert("client.pem", ""),
+		),
+	)
+	if err == nil || !strings.Contains(err.Error(), "together") {
+		t.Errorf("expected cert/key pairing error, got %v", err)
+	}
+}
+
+func TestNew_TLS(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	var gotConfig *DependencyConfig
+	old := checkerFactories[TypeTLS]
+	checkerFactories[TypeTLS] = func(dc *DependencyConfig) HealthChecker {
+		gotConfig = dc
+		return &mockChecker{}
+	}
+	t.Cleanup(func() {
+		if old != nil {
+			checkerFactories[TypeTLS] = old
+		} else {
+			delete(checkerFactories, TypeTLS)
+		}
+	})
+
+	_, err := New("test-app", "test-group",
+		WithRegisterer(reg),
+		TLS("smtp",
+			FromURL("tls://smtp.example.com:465"),
+			Critical(true),
+			WithTLSServerName("mail.example.com"),
+			WithTLSMinVersion("1.3"),
+			WithTLSPinnedCert(strings.Repeat("ab:", 31)+"ab"),
+		),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotConfig.TLSServerName != "mail.example.com" || gotConfig.TLSMinVersion != "1.3" {
+		t.Errorf("unexpected TLS config: %+v", gotConfig)
+	}
+	if len(gotConfig.TLSPinnedCerts) != 1 {
+		t.Errorf("expected 1 pinned certificate, got %d", len(gotConfig.TLSPinnedCerts))
+	}
+}
+
+func TestNew_TLSInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  DependencyOption
+		want string
+	}{
+		{"min version", WithTLSMinVersion("1.4"), "min version"},
+		{"pin", WithTLSPinnedCert("deadbeef"), "pinned certificate"},
+		{"client cert", WithTLSClientCert("client.pem", ""), "together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registerMockFactory(t, TypeTLS, &mockChecker{})
+			_, err := New("test-app", "test-group",
+				WithRegisterer(prometheus.NewRegistry()),
+				TLS("smtp", FromParams("smtp.example.com", "465"), Critical(true), tt.opt),
+			)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNew_SharedTLS(t *testing.T) {
+	var gotConfig *DependencyConfig
+	old := checkerFactories[TypeRedis]
+	checkerFactories[TypeRedis] = func(dc *DependencyConfig) HealthChecker {
+		gotConfig = dc
+		return &mockChecker{}
+	}
+	t.Cleanup(func() {
+		if old != nil {
+			checkerFactories[TypeRedis] = old
+		} else {
+			delete(checkerFactories, TypeRedis)
+		}
+	})
+
+	base := &tls.Config{MinVersion: tls.VersionTLS13}
+	_, err := New("test-app", "test-group",
+		WithRegisterer(prometheus.NewRegistry()),
+		Redis("cache", FromParams("redis.svc", "6380"), Critical(false),
+			WithTLSConfig(base),
+			WithTLSFiles("ca.pem", "client.pem", "client-key.pem"),
+		),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotConfig == nil || gotConfig.TLSConfig != base {
+		t.Fatal("expected the shared TLS config to reach the checker factory")
+	}
+	if gotConfig.TLSCAFile != "ca.pem" || gotConfig.TLSCertFile != "client.pem" || gotConfig.TLSKeyFile != "client-key.pem" {
+		t.Errorf("unexpected TLS files: %q, %q, %q", gotConfig.TLSCAFile, gotConfig.TLSCertFile, gotConfig.TLSKeyFile)
+	}
+
+	_, err = New("test-app", "test-group",
+		WithRegisterer(prometheus.NewRegistry()),
+		Redis("cache", FromParams("redis.svc", "6380"), Critical(false),
+			WithTLSFiles("", "client.pem", ""),
+		),
+	)
+	if err == nil || !strings.Contains(err.Error(), "together") {
+		t.Errorf("expected client certificate error, got %v", err)
+	}
+}
+
+func TestNew_SharedTLSRejectedWithoutTLS(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  DependencyOption
+	}{
+		{"config", WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12})},
+		{"ca", WithTLSCA("ca.pem")},
+		{"client cert", WithTLSClientCert("client.pem", "client-key.pem")},
+		{"skip verify", WithTLSSkipVerify(true)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New("test-app", "test-group",
+				WithRegisterer(prometheus.NewRegistry()),
+				TCP("socket", FromParams("tcp.svc", "9000"), Critical(false), tt.opt),
+			)
+			if err == nil || !strings.Contains(err.Error(), "not supported for tcp") {
+				t.Errorf("expected TLS options to be rejected, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNew_DNS(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	registerMockFactory(t, TypeDNS, &mockChecker{})
+
+	dh, err := New("test-app", "test-group",
+		WithRegisterer(reg),
+		DNS("cluster-dns",
+			FromURL("dns://10.96.0.10"),
+			Critical(true),
+			WithDNSQuery("kubernetes.default.svc.cluster.local", "A"),
+			WithDNSExpected("10.96.0.1"),
+		),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := dh.scheduler.deps[0].dep.Endpoints[0].Port; got != "53" {
+		t.Errorf("expected default port 53, got %q", got)
+	}
+}
+
+func TestNew_DNSInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []DependencyOption
+		want string
+	}{
+		{"missing name", nil, "DNS name is required"},
+		{"record type", []DependencyOption{WithDNSQuery("example.com", "MX")}, "record type"},
+		{"protocol", []DependencyOption{WithDNSQuery("example.com", "A"), WithDNSProtocol("doh")}, "protocol"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registerMockFactory(t, TypeDNS, &mockChecker{})
+			opts := append([]DependencyOption{FromParams("10.96.0.10", "53"), Critical(true)}, tt.opts...)
+			_, err := New("test-app", "test-group",
+				WithRegisterer(prometheus.NewRegistry()),
+				DNS("cluster-dns", opts...),
+			)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNew_ClickHouseAndCassandra(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	registerMockFactory(t, TypeClickHouse, &mockChecker{})
+	registerMockFactory(t, TypeCassandra, &mockChecker{})
+
+	dh, err := New("test-app", "test-group",
+		WithRegisterer(reg),
+		ClickHouse("clickhouse",
+			FromURL("clickhouse://ch.svc"),
+			Critical(false),
+			WithClickHouseProtocol("native"),
+			WithClickHouseMaxReplicaDelay(30*time.Second),
+		),
+		Cassandra("cassandra",
+			FromURL("cassandra://cass-0,cass-1"),
+			Critical(true),
+			WithCassandraConsistency("local_quorum"),
+		),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := dh.scheduler.deps[0].dep.Endpoints[0].Port; got != "9000" {
+		t.Errorf("expected clickhouse default port 9000, got %q", got)
+	}
+	if got := len(dh.scheduler.deps[1].dep.Endpoints); got != 2 {
+		t.Errorf("expected 2 cassandra endpoints, got %d", got)
+	}
+}
+
+func TestNew_ClickHouseAndCassandraValidation(t *testing.T) {
+	registerMockFactory(t, TypeClickHouse, &mockChecker{})
+	registerMockFactory(t, TypeCassandra, &mockChecker{})
+
+	tests := []struct {
+		name string
+		opt  Option
+		want string
+	}{
+		{
+			name: "invalid clickhouse protocol",
+			opt:  ClickHouse("ch", FromParams("ch.svc", "9000"), Critical(true), WithClickHouseProtocol("grpc")),
+			want: "invalid clickhouse protocol",
+		},
+		{
+			name: "negative replica delay",
+			opt:  ClickHouse("ch", FromParams("ch.svc", "9000"), Critical(true), WithClickHouseMaxReplicaDelay(-time.Second)),
+			want: "clickhouse max replica delay must not be negative",
+		},
+		{
+			name: "invalid cassandra consistency",
+			opt:  Cassandra("cass", FromParams("cass.svc", "9042"), Critical(true), WithCassandraConsistency("MOST")),
+			want: "invalid Cassandra consistency",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New("test-app", "test-group", WithRegisterer(prometheus.NewRegistry()), tt.opt)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
 func TestNew_CheckerWrappers(t *testing.T) {
 	reg := prometheus.NewRegistry()
 
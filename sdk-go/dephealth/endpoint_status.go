@@ -3,10 +3,15 @@ package dephealth
 import (
 	"encoding/json"
 	"time"
+	"unicode/utf8"
 )
 
+// maxErrorLength is the maximum length in bytes of EndpointStatus.Error.
+const maxErrorLength = 512
+
 // EndpointStatus represents the detailed health check state for a single endpoint.
-// It is returned by HealthDetails() and contains all 11 fields defined in the specification.
+// It is returned by HealthDetails() and contains all 11 fields defined in the specification,
+// plus optional extensions that are omitted from JSON when not set.
 type EndpointStatus struct {
 	Healthy       *bool             `json:"healthy"`
 	Status        StatusCategory    `json:"status"`
@@ -19,6 +24,44 @@ type EndpointStatus struct {
 	Critical      bool              `json:"critical"`
 	LastCheckedAt time.Time         `json:"last_checked_at"`
 	Labels        map[string]string `json:"labels"`
+
+	// TLSCertExpiry is the NotAfter of the dependency's leaf certificate,
+	// as reported by the last TLS handshake. Zero if not available.
+	TLSCertExpiry time.Time `json:"-"`
+
+	// Error is the message of the last failed check, redacted (see Redact)
+	// and truncated to 512 bytes. Empty after a successful check.
+	Error string `json:"error,omitempty"`
+
+	// ConsecutiveFailures and ConsecutiveSuccesses count the checks with
+	// the same outcome in a row, as used for the failure and success
+	// thresholds. One of them is always zero.
+	ConsecutiveFailures  int `json:"consecutive_failures"`
+	ConsecutiveSuccesses int `json:"consecutive_successes"`
+
+	// LastSuccessAt and LastFailureAt are the times of the last successful
+	// and failed checks. Zero if there was none yet.
+	LastSuccessAt time.Time `json:"-"`
+	LastFailureAt time.Time `json:"-"`
+
+	// Components holds the named sub-components reported by the dependency's
+	// own health response in the last check (e.g. Spring Boot Actuator
+	// components), keyed by name. Nil when the checker reports none.
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+
+	// Dependencies holds the transitive edges of the dependency: the
+	// HealthDetails of a downstream dephealth service, keyed as in its own
+	// response and nested down to the configured topology depth. Nil when
+	// the checker reports none.
+	Dependencies map[string]EndpointStatus `json:"dependencies,omitempty"`
+}
+
+// ComponentStatus is the state of a sub-component of a dependency, as
+// reported by the dependency's own health endpoint.
+type ComponentStatus struct {
+	Status  string `json:"status"`           // as reported, e.g. "DOWN" or "fail"
+	Healthy bool   `json:"healthy"`          // UP/pass or WARN/warn
+	Detail  string `json:"detail,omitempty"` // error or output text, if any
 }
 
 // LatencyMillis returns the latency in milliseconds as a float64.
@@ -39,31 +82,58 @@ type endpointStatusJSON struct {
 	Critical      bool              `json:"critical"`
 	LastCheckedAt *time.Time        `json:"last_checked_at"`
 	Labels        map[string]string `json:"labels"`
+	TLSCertExpiry *time.Time        `json:"tls_cert_expiry,omitempty"`
+
+	Error                string     `json:"error,omitempty"`
+	ConsecutiveFailures  int        `json:"consecutive_failures"`
+	ConsecutiveSuccesses int        `json:"consecutive_successes"`
+	LastSuccessAt        *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt        *time.Time `json:"last_failure_at,omitempty"`
+
+	Components   map[string]ComponentStatus `json:"components,omitempty"`
+	Dependencies map[string]EndpointStatus  `json:"dependencies,omitempty"`
 }
 
 // MarshalJSON implements custom JSON marshaling.
 // Latency is serialized as latency_ms (milliseconds float).
 // LastCheckedAt is serialized as null when zero (before first check).
+// TLSCertExpiry, Error, LastSuccessAt, LastFailureAt, Components and
+// Dependencies are omitted when empty.
 func (es EndpointStatus) MarshalJSON() ([]byte, error) {
 	j := endpointStatusJSON{
-		Healthy:   es.Healthy,
-		Status:    es.Status,
-		Detail:    es.Detail,
-		LatencyMs: es.LatencyMillis(),
-		Type:      es.Type,
-		Name:      es.Name,
-		Host:      es.Host,
-		Port:      es.Port,
-		Critical:  es.Critical,
-		Labels:    es.Labels,
-	}
-	if !es.LastCheckedAt.IsZero() {
-		t := es.LastCheckedAt.UTC()
-		j.LastCheckedAt = &t
+		Healthy:      es.Healthy,
+		Status:       es.Status,
+		Detail:       es.Detail,
+		LatencyMs:    es.LatencyMillis(),
+		Type:         es.Type,
+		Name:         es.Name,
+		Host:         es.Host,
+		Port:         es.Port,
+		Critical:     es.Critical,
+		Labels:       es.Labels,
+		Components:   es.Components,
+		Dependencies: es.Dependencies,
+
+		Error:                es.Error,
+		ConsecutiveFailures:  es.ConsecutiveFailures,
+		ConsecutiveSuccesses: es.ConsecutiveSuccesses,
 	}
+	j.LastCheckedAt = utcOrNil(es.LastCheckedAt)
+	j.TLSCertExpiry = utcOrNil(es.TLSCertExpiry)
+	j.LastSuccessAt = utcOrNil(es.LastSuccessAt)
+	j.LastFailureAt = utcOrNil(es.LastFailureAt)
 	return json.Marshal(j)
 }
 
+// utcOrNil returns t in UTC, or nil when t is zero.
+func utcOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
 // UnmarshalJSON implements custom JSON unmarshaling.
 func (es *EndpointStatus) UnmarshalJSON(data []byte) error {
 	var j endpointStatusJSON
@@ -80,8 +150,35 @@ func (es *EndpointStatus) UnmarshalJSON(data []byte) error {
 	es.Port = j.Port
 	es.Critical = j.Critical
 	es.Labels = j.Labels
-	if j.LastCheckedAt != nil {
-		es.LastCheckedAt = *j.LastCheckedAt
-	}
+	es.Components = j.Components
+	es.Dependencies = j.Dependencies
+	es.Error = j.Error
+	es.ConsecutiveFailures = j.ConsecutiveFailures
+	es.ConsecutiveSuccesses = j.ConsecutiveSuccesses
+	es.LastCheckedAt = timeOrZero(j.LastCheckedAt)
+	es.TLSCertExpiry = timeOrZero(j.TLSCertExpiry)
+	es.LastSuccessAt = timeOrZero(j.LastSuccessAt)
+	es.LastFailureAt = timeOrZero(j.LastFailureAt)
 	return nil
 }
+
+// timeOrZero returns *t, or the zero time when t is nil.
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// truncateError shortens msg to maxErrorLength bytes, cutting at a rune
+// boundary and marking the cut with "...".
+func truncateError(msg string) string {
+	if len(msg) <= maxErrorLength {
+		return msg
+	}
+	cut := maxErrorLength - len("...")
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+	return msg[:cut] + "..."
+}
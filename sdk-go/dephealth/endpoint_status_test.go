@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -462,6 +465,141 @@ func TestEndpointStatus_JSON_Unknown(t *testing.T) {
 	if m["last_checked_at"] != nil {
 		t.Errorf("last_checked_at: expected null, got %v", m["last_checked_at"])
 	}
+	if _, ok := m["tls_cert_expiry"]; ok {
+		t.Errorf("tls_cert_expiry: expected omitted, got %v", m["tls_cert_expiry"])
+	}
+}
+
+func TestEndpointStatus_JSON_TLSCertExpiry(t *testing.T) {
+	notAfter := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	es := EndpointStatus{
+		Status:        StatusOK,
+		Detail:        "tls_expiring",
+		Type:          TypeHTTP,
+		Name:          "api-gw",
+		Host:          "api.svc",
+		Port:          "443",
+		TLSCertExpiry: notAfter,
+	}
+
+	data, err := json.Marshal(es)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if m["tls_cert_expiry"] != "2027-01-01T00:00:00Z" {
+		t.Errorf("tls_cert_expiry: expected %q, got %v", "2027-01-01T00:00:00Z", m["tls_cert_expiry"])
+	}
+
+	var decoded EndpointStatus
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !decoded.TLSCertExpiry.Equal(notAfter) {
+		t.Errorf("TLSCertExpiry: expected %v, got %v", notAfter, decoded.TLSCertExpiry)
+	}
+}
+
+func TestEndpointStatus_JSON_Components(t *testing.T) {
+	es := EndpointStatus{
+		Status: StatusUnhealthy,
+		Detail: "health_down",
+		Type:   TypeHTTP,
+		Name:   "orders",
+		Components: map[string]ComponentStatus{
+			"db":        {Status: "DOWN", Detail: "connection refused"},
+			"diskSpace": {Status: "UP", Healthy: true},
+		},
+	}
+
+	data, err := json.Marshal(es)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	db, _ := m["components"].(map[string]any)["db"].(map[string]any)
+	if db["status"] != "DOWN" || db["healthy"] != false || db["detail"] != "connection refused" {
+		t.Errorf("components.db = %v", db)
+	}
+
+	var decoded EndpointStatus
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if len(decoded.Components) != 2 || !decoded.Components["diskSpace"].Healthy {
+		t.Errorf("Components: got %v", decoded.Components)
+	}
+
+	// Omitted when empty, so existing consumers see the same JSON.
+	data, _ = json.Marshal(EndpointStatus{Status: StatusOK})
+	var empty map[string]any
+	if err := json.Unmarshal(data, &empty); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if _, ok := empty["components"]; ok {
+		t.Error("expected components to be omitted when empty")
+	}
+}
+
+func TestEndpointStatus_JSON_Dependencies(t *testing.T) {
+	healthy := false
+	es := EndpointStatus{
+		Status: StatusUnhealthy,
+		Detail: "health_down",
+		Type:   TypeHTTP,
+		Name:   "orders",
+		Dependencies: map[string]EndpointStatus{
+			"postgres:pg.svc:5432": {
+				Healthy:  &healthy,
+				Status:   StatusConnectionError,
+				Detail:   "connection_refused",
+				Type:     TypePostgres,
+				Name:     "postgres",
+				Host:     "pg.svc",
+				Port:     "5432",
+				Critical: true,
+				Latency:  3 * time.Millisecond,
+			},
+		},
+	}
+
+	data, err := json.Marshal(es)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	pg, _ := m["dependencies"].(map[string]any)["postgres:pg.svc:5432"].(map[string]any)
+	if pg["detail"] != "connection_refused" || pg["latency_ms"] != 3.0 || pg["last_checked_at"] != nil {
+		t.Errorf("dependencies.postgres = %v", pg)
+	}
+
+	var decoded EndpointStatus
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	got := decoded.Dependencies["postgres:pg.svc:5432"]
+	if !got.Critical || got.Healthy == nil || *got.Healthy || got.Latency != 3*time.Millisecond {
+		t.Errorf("Dependencies: got %+v", got)
+	}
+
+	data, _ = json.Marshal(EndpointStatus{Status: StatusOK})
+	var empty map[string]any
+	if err := json.Unmarshal(data, &empty); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if _, ok := empty["dependencies"]; ok {
+		t.Error("expected dependencies to be omitted when empty")
+	}
 }
 
 func TestEndpointStatus_JSON_Roundtrip(t *testing.T) {
@@ -515,3 +653,124 @@ func TestEndpointStatus_LatencyMillis(t *testing.T) {
 		t.Errorf("LatencyMillis: expected 2.5, got %v", es.LatencyMillis())
 	}
 }
+
+func TestHealthDetails_ErrorAndCounters(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	var failing atomic.Bool
+	failing.Store(true)
+	checker := &mockChecker{
+		checkFunc: func(_ context.Context, _ Endpoint) error {
+			if failing.Load() {
+				return errors.New("dial postgres://app:s3cret@db:5432: connection refused")
+			}
+			return nil
+		},
+	}
+	dep := testDep("test-dep", 50*time.Millisecond, 50*time.Millisecond, 0)
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	time.Sleep(130 * time.Millisecond)
+	es := sched.HealthDetails()["test-dep:127.0.0.1:1234"]
+	if es.Error != "dial postgres://REDACTED@db:5432: connection refused" {
+		t.Errorf("Error = %q, expected the redacted check error", es.Error)
+	}
+	if es.ConsecutiveFailures < 2 || es.ConsecutiveSuccesses != 0 {
+		t.Errorf("counters = %d/%d, expected >= 2 failures and 0 successes",
+			es.ConsecutiveFailures, es.ConsecutiveSuccesses)
+	}
+	if es.LastFailureAt.IsZero() || !es.LastSuccessAt.IsZero() {
+		t.Errorf("LastFailureAt = %v, LastSuccessAt = %v", es.LastFailureAt, es.LastSuccessAt)
+	}
+	lastFailure := es.LastFailureAt
+
+	failing.Store(false)
+	time.Sleep(80 * time.Millisecond)
+	es = sched.HealthDetails()["test-dep:127.0.0.1:1234"]
+	if es.Error != "" {
+		t.Errorf("Error = %q after success, expected empty", es.Error)
+	}
+	if es.ConsecutiveSuccesses < 1 || es.ConsecutiveFailures != 0 {
+		t.Errorf("counters = %d/%d, expected 0 failures and >= 1 success",
+			es.ConsecutiveFailures, es.ConsecutiveSuccesses)
+	}
+	if es.LastSuccessAt.IsZero() || !es.LastFailureAt.Equal(lastFailure) {
+		t.Errorf("LastSuccessAt = %v, LastFailureAt = %v (was %v)", es.LastSuccessAt, es.LastFailureAt, lastFailure)
+	}
+}
+
+func TestEndpointStatus_JSON_ErrorAndCounters(t *testing.T) {
+	success := time.Date(2026, 2, 14, 10, 29, 0, 0, time.UTC)
+	failure := time.Date(2026, 2, 14, 10, 30, 0, 0, time.FixedZone("MSK", 3*3600))
+	es := EndpointStatus{
+		Status:              StatusConnectionError,
+		Detail:              "connection_refused",
+		Error:               "tcp dial db:5432: connection refused",
+		ConsecutiveFailures: 3,
+		LastSuccessAt:       success,
+		LastFailureAt:       failure,
+	}
+
+	data, err := json.Marshal(es)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if m["error"] != "tcp dial db:5432: connection refused" {
+		t.Errorf("error: got %v", m["error"])
+	}
+	if m["consecutive_failures"] != 3.0 || m["consecutive_successes"] != 0.0 {
+		t.Errorf("counters: got %v/%v", m["consecutive_failures"], m["consecutive_successes"])
+	}
+	if m["last_success_at"] != "2026-02-14T10:29:00Z" || m["last_failure_at"] != "2026-02-14T07:30:00Z" {
+		t.Errorf("timestamps: got %v/%v", m["last_success_at"], m["last_failure_at"])
+	}
+
+	var decoded EndpointStatus
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if decoded.Error != es.Error || decoded.ConsecutiveFailures != 3 ||
+		!decoded.LastSuccessAt.Equal(success) || !decoded.LastFailureAt.Equal(failure) {
+		t.Errorf("roundtrip mismatch: %+v", decoded)
+	}
+
+	// The fields are omitted when empty, and older payloads decode.
+	data, _ = json.Marshal(EndpointStatus{Status: StatusOK})
+	m = nil
+	_ = json.Unmarshal(data, &m)
+	for _, key := range []string{"error", "last_success_at", "last_failure_at"} {
+		if _, ok := m[key]; ok {
+			t.Errorf("%s: expected omitted, got %v", key, m[key])
+		}
+	}
+	var old EndpointStatus
+	if err := json.Unmarshal([]byte(`{"healthy":true,"status":"ok","detail":"ok","latency_ms":1}`), &old); err != nil {
+		t.Fatalf("Unmarshal of a payload without the new fields: %v", err)
+	}
+	if old.Error != "" || old.ConsecutiveFailures != 0 || !old.LastSuccessAt.IsZero() {
+		t.Errorf("unexpected values from an old payload: %+v", old)
+	}
+}
+
+func TestTruncateError(t *testing.T) {
+	short := "connection refused"
+	if got := truncateError(short); got != short {
+		t.Errorf("truncateError(%q) = %q", short, got)
+	}
+
+	long := strings.Repeat("a", maxErrorLength-4) + "ёёё"
+	got := truncateError(long)
+	if len(got) > maxErrorLength || !strings.HasSuffix(got, "...") {
+		t.Errorf("len = %d, suffix = %q; expected at most %d bytes ending in ...",
+			len(got), got[len(got)-3:], maxErrorLength)
+	}
+	if !utf8.ValidString(got) {
+		t.Errorf("truncated message is not valid UTF-8: %q", got[len(got)-8:])
+	}
+}
@@ -0,0 +1,47 @@
+// Package checktest runs dephealth checkers through a scheduler in tests of
+// the built-in checkers.
+package checktest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth"
+)
+
+// CertExpiry runs checker against ep through a dephealth scheduler and
+// returns the TLS certificate expiry recorded by its first check. The check
+// itself may fail; only the captured certificate matters.
+func CertExpiry(t testing.TB, checker dephealth.HealthChecker, depType dephealth.DependencyType, ep dephealth.Endpoint) time.Time {
+	t.Helper()
+	metrics, err := dephealth.NewMetricsExporter("test-app", "test-group",
+		dephealth.WithMetricsRegisterer(prometheus.NewRegistry()))
+	if err != nil {
+		t.Fatalf("checktest: metrics exporter: %v", err)
+	}
+	cfg := dephealth.DefaultCheckConfig()
+	cfg.InitialDelay = 0
+	sched := dephealth.NewScheduler(metrics, dephealth.WithGlobalCheckConfig(cfg))
+	if err := sched.Start(context.Background()); err != nil {
+		t.Fatalf("checktest: start scheduler: %v", err)
+	}
+	defer sched.Stop()
+
+	if err := sched.AddEndpoint("tls-dep", depType, false, ep, checker); err != nil {
+		t.Fatalf("checktest: add endpoint: %v", err)
+	}
+
+	key := "tls-dep:" + ep.Host + ":" + ep.Port
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if es := sched.HealthDetails()[key]; !es.LastCheckedAt.IsZero() {
+			return es.TLSCertExpiry
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("checktest: endpoint was not checked in time")
+	return time.Time{}
+}
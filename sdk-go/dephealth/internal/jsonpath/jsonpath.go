@@ -0,0 +1,113 @@
+// Package jsonpath evaluates the small JSONPath subset used by dephealth
+// body assertions: the root "$", dotted member names ($.a.b), bracketed
+// member names ($['a-b'] or $["a"]) and array indexes ($.items[0]).
+// Filters, wildcards and recursive descent are not supported.
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path is a parsed JSONPath expression.
+type Path struct {
+	expr     string
+	segments []segment
+}
+
+// segment selects a member (name) or an array element (index).
+type segment struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// Parse parses a JSONPath expression such as "$.status" or "$.checks[0].ok".
+func Parse(expr string) (Path, error) {
+	rest := strings.TrimSpace(expr)
+	if !strings.HasPrefix(rest, "$") {
+		return Path{}, fmt.Errorf("invalid JSONPath %q: must start with $", expr)
+	}
+	rest = rest[1:]
+
+	p := Path{expr: expr}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return Path{}, fmt.Errorf("invalid JSONPath %q: empty member name", expr)
+			}
+			p.segments = append(p.segments, segment{name: name})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return Path{}, fmt.Errorf("invalid JSONPath %q: unclosed bracket", expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				p.segments = append(p.segments, segment{name: inner[1 : len(inner)-1]})
+				continue
+			}
+			idx, err := strconv.Atoi(inner)
+			if err != nil || idx < 0 {
+				return Path{}, fmt.Errorf("invalid JSONPath %q: unsupported selector [%s]", expr, inner)
+			}
+			p.segments = append(p.segments, segment{index: idx, isIndex: true})
+		default:
+			return Path{}, fmt.Errorf("invalid JSONPath %q: unexpected %q", expr, rest[0])
+		}
+	}
+	return p, nil
+}
+
+// String returns the original expression.
+func (p Path) String() string {
+	return p.expr
+}
+
+// Lookup returns the value selected by the path in a document decoded by
+// encoding/json into any, and whether it exists.
+func (p Path) Lookup(doc any) (any, bool) {
+	cur := doc
+	for _, s := range p.segments {
+		if s.isIndex {
+			arr, ok := cur.([]any)
+			if !ok || s.index >= len(arr) {
+				return nil, false
+			}
+			cur = arr[s.index]
+			continue
+		}
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = obj[s.name]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// Format renders a looked-up value for comparison with an expected string:
+// strings as-is, other values as compact JSON (true, 42, null, {"a":1}).
+func Format(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
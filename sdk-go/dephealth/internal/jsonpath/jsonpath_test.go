@@ -0,0 +1,58 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "status", "$.", "$..a", "$[", "$[x]", "$[-1]", "$status"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q): expected error, got nil", expr)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(`{
+		"status": "UP",
+		"components": {"db": {"status": "DOWN", "details": {"pool-size": 10}}},
+		"checks": [{"ok": true}, {"ok": false}],
+		"empty": null
+	}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr  string
+		want  string
+		found bool
+	}{
+		{"$", `{"checks":[{"ok":true},{"ok":false}],"components":{"db":{"details":{"pool-size":10},"status":"DOWN"}},"empty":null,"status":"UP"}`, true},
+		{"$.status", "UP", true},
+		{"$.components.db.status", "DOWN", true},
+		{"$['components'][\"db\"].details['pool-size']", "10", true},
+		{"$.checks[0].ok", "true", true},
+		{"$.checks[1].ok", "false", true},
+		{"$.empty", "null", true},
+		{"$.checks[2].ok", "", false},
+		{"$.missing", "", false},
+		{"$.status.inner", "", false},
+		{"$.components[0]", "", false},
+	}
+	for _, tt := range tests {
+		p, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+		v, found := p.Lookup(doc)
+		if found != tt.found {
+			t.Errorf("%s: found = %v, expected %v", tt.expr, found, tt.found)
+			continue
+		}
+		if found && Format(v) != tt.want {
+			t.Errorf("%s = %s, expected %s", tt.expr, Format(v), tt.want)
+		}
+	}
+}
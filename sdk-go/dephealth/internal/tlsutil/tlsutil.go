@@ -0,0 +1,180 @@
+// Package tlsutil builds *tls.Config values for dephealth checkers from
+// file-based settings (CA bundle, client certificate and key).
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+// Options describes the TLS settings of a checker connection.
+type Options struct {
+	CAFile     string // PEM bundle with trusted CAs; empty = system roots
+	CertFile   string // PEM client certificate for mTLS
+	KeyFile    string // PEM private key for CertFile
+	ServerName string // overrides SNI and the verified hostname
+	SkipVerify bool   // disables certificate verification
+}
+
+// IsZero reports whether no TLS setting is configured.
+func (o Options) IsZero() bool {
+	return o == Options{}
+}
+
+// Validate checks that the client certificate and key are configured together.
+func (o Options) Validate() error {
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return errors.New("TLS client certificate and key must be specified together")
+	}
+	return nil
+}
+
+// Build loads the configured files and returns a new *tls.Config.
+func (o Options) Build() (*tls.Config, error) {
+	return o.BuildOn(nil)
+}
+
+// BuildOn is like Build but starts from a clone of base, so settings that
+// Options does not cover (cipher suites, GetClientCertificate, ...) are
+// kept. Non-empty options override the corresponding fields of base.
+func (o Options) BuildOn(base *tls.Config) (*tls.Config, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	var cfg *tls.Config
+	if base != nil {
+		cfg = base.Clone()
+		if o.ServerName != "" {
+			cfg.ServerName = o.ServerName
+		}
+		if o.SkipVerify {
+			cfg.InsecureSkipVerify = true
+		}
+	} else {
+		cfg = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			ServerName:         o.ServerName,
+			InsecureSkipVerify: o.SkipVerify, //nolint:gosec // configurable by user
+		}
+	}
+
+	if o.CAFile != "" {
+		pool, err := LoadCertPool(o.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+
+	if o.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load TLS client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// Source provides the TLS config of a checker built from a base config and
+// Options. The CA, certificate and key files are read again when their size
+// or modification time changes, so rotated certificates (e.g. by
+// cert-manager) are used by the next connection without a restart.
+// A nil *Source behaves like one with no settings.
+type Source struct {
+	base *tls.Config
+	opts Options
+
+	mu         sync.Mutex
+	cfg        *tls.Config
+	stamps     []fileStamp
+	generation uint64
+}
+
+// fileStamp identifies a version of a file on disk.
+type fileStamp struct {
+	size    int64
+	modTime int64 // UnixNano
+}
+
+// NewSource returns a Source for base (may be nil) and opts.
+func NewSource(base *tls.Config, opts Options) *Source {
+	return &Source{base: base, opts: opts}
+}
+
+// IsZero reports whether the source has neither a base config nor options.
+func (s *Source) IsZero() bool {
+	return s == nil || (s.base == nil && s.opts.IsZero())
+}
+
+// HasFiles reports whether the source reads a CA bundle or client certificate.
+func (s *Source) HasFiles() bool {
+	return s != nil && (s.opts.CAFile != "" || s.opts.CertFile != "")
+}
+
+// Config returns a copy of the current config, reloading the files first
+// if they have changed since the last call. The copy may be modified.
+func (s *Source) Config() (*tls.Config, error) {
+	cfg, _, err := s.Load()
+	return cfg, err
+}
+
+// Load is like Config but also returns a generation counter that changes
+// every time the files are loaded, so holders of long-lived connections
+// can tell when to reconnect.
+func (s *Source) Load() (*tls.Config, uint64, error) {
+	if s == nil {
+		cfg, err := Options{}.Build()
+		return cfg, 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stamps := s.stat()
+	if s.cfg == nil || !slices.Equal(stamps, s.stamps) {
+		cfg, err := s.opts.BuildOn(s.base)
+		if err != nil {
+			return nil, 0, err
+		}
+		s.cfg, s.stamps = cfg, stamps
+		s.generation++
+	}
+	return s.cfg.Clone(), s.generation, nil
+}
+
+// stat returns the current stamps of the configured files. A file that
+// cannot be stat'ed gets a zero stamp; reading it then reports the error.
+func (s *Source) stat() []fileStamp {
+	var stamps []fileStamp
+	for _, path := range []string{s.opts.CAFile, s.opts.CertFile, s.opts.KeyFile} {
+		if path == "" {
+			continue
+		}
+		var st fileStamp
+		if fi, err := os.Stat(path); err == nil {
+			st = fileStamp{size: fi.Size(), modTime: fi.ModTime().UnixNano()}
+		}
+		stamps = append(stamps, st)
+	}
+	return stamps
+}
+
+// LoadCertPool reads a PEM bundle and returns a pool with its certificates.
+func LoadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile) //nolint:gosec // path is configured by user
+	if err != nil {
+		return nil, fmt.Errorf("read TLS CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("TLS CA file %s contains no PEM certificates", caFile)
+	}
+	return pool, nil
+}
@@ -0,0 +1,152 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlstest"
+)
+
+func TestOptions_Build_Empty(t *testing.T) {
+	cfg, err := Options{}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RootCAs != nil {
+		t.Error("expected system roots (nil RootCAs)")
+	}
+	if len(cfg.Certificates) != 0 {
+		t.Error("expected no client certificates")
+	}
+	if cfg.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %x, expected TLS 1.2", cfg.MinVersion)
+	}
+}
+
+func TestOptions_Build_Files(t *testing.T) {
+	dir := t.TempDir()
+	ca := tlstest.NewCA(t)
+	leaf := ca.Issue(t, time.Now().Add(time.Hour), "client")
+
+	opts := Options{
+		CAFile:     tlstest.WriteFile(t, dir, "ca.pem", ca.PEM),
+		CertFile:   tlstest.WriteFile(t, dir, "tls.crt", leaf.CertPEM),
+		KeyFile:    tlstest.WriteFile(t, dir, "tls.key", leaf.KeyPEM),
+		ServerName: "etcd.svc",
+	}
+	cfg, err := opts.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RootCAs == nil {
+		t.Error("expected custom RootCAs")
+	}
+	if len(cfg.Certificates) != 1 {
+		t.Errorf("expected 1 client certificate, got %d", len(cfg.Certificates))
+	}
+	if cfg.ServerName != "etcd.svc" {
+		t.Errorf("ServerName = %q, expected %q", cfg.ServerName, "etcd.svc")
+	}
+}
+
+func TestOptions_Build_CertWithoutKey(t *testing.T) {
+	_, err := Options{CertFile: "tls.crt"}.Build()
+	if err == nil || !strings.Contains(err.Error(), "together") {
+		t.Errorf("expected cert/key pairing error, got %v", err)
+	}
+}
+
+func TestOptions_Build_MissingCAFile(t *testing.T) {
+	_, err := Options{CAFile: "/nonexistent/ca.pem"}.Build()
+	if err == nil {
+		t.Error("expected error for missing CA file")
+	}
+}
+
+func TestLoadCertPool_NoPEM(t *testing.T) {
+	path := tlstest.WriteFile(t, t.TempDir(), "ca.pem", []byte("not a certificate"))
+	if _, err := LoadCertPool(path); err == nil {
+		t.Error("expected error for file without PEM certificates")
+	}
+}
+
+func TestOptions_IsZero(t *testing.T) {
+	if !(Options{}).IsZero() {
+		t.Error("expected empty options to be zero")
+	}
+	if (Options{SkipVerify: true}).IsZero() {
+		t.Error("expected options with SkipVerify to be non-zero")
+	}
+}
+
+func TestOptions_BuildOn_KeepsBase(t *testing.T) {
+	base := &tls.Config{MinVersion: tls.VersionTLS13, ServerName: "base.svc"}
+	cfg, err := Options{SkipVerify: true}.BuildOn(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.MinVersion != tls.VersionTLS13 || cfg.ServerName != "base.svc" || !cfg.InsecureSkipVerify {
+		t.Errorf("cfg = MinVersion %x, ServerName %q, InsecureSkipVerify %v", cfg.MinVersion, cfg.ServerName, cfg.InsecureSkipVerify)
+	}
+	if base.InsecureSkipVerify {
+		t.Error("BuildOn modified the base config")
+	}
+}
+
+func TestSource_ReloadsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	ca := tlstest.NewCA(t)
+	first := ca.Issue(t, time.Now().Add(time.Hour), "client")
+	certFile := tlstest.WriteFile(t, dir, "tls.crt", first.CertPEM)
+	keyFile := tlstest.WriteFile(t, dir, "tls.key", first.KeyPEM)
+
+	src := NewSource(nil, Options{CertFile: certFile, KeyFile: keyFile})
+	cfg, gen, err := src.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Certificates[0].Leaf.Equal(first.Cert) {
+		t.Fatal("expected the first certificate")
+	}
+
+	// Unchanged files are not reloaded.
+	if _, again, err := src.Load(); err != nil || again != gen {
+		t.Errorf("generation changed without a file change (err %v)", err)
+	}
+
+	// Rotate the certificate, as cert-manager does.
+	second := ca.Issue(t, time.Now().Add(2*time.Hour), "client")
+	tlstest.WriteFile(t, dir, "tls.crt", second.CertPEM)
+	tlstest.WriteFile(t, dir, "tls.key", second.KeyPEM)
+	future := time.Now().Add(time.Minute)
+	for _, f := range []string{certFile, keyFile} {
+		if err := os.Chtimes(f, future, future); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+
+	cfg, rotated, err := src.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Certificates[0].Leaf.Equal(second.Cert) {
+		t.Error("expected the rotated certificate")
+	}
+	if rotated == gen {
+		t.Error("expected a new generation after the rotation")
+	}
+}
+
+func TestSource_Nil(t *testing.T) {
+	var src *Source
+	cfg, err := src.Config()
+	if err != nil || cfg == nil {
+		t.Fatalf("Config() = %v, %v", cfg, err)
+	}
+	if !src.IsZero() || src.HasFiles() {
+		t.Error("expected a nil source to be empty")
+	}
+}
@@ -14,6 +14,10 @@ const (
 	latencyHelp      = "Latency of dependency health check in seconds"
 	statusHelp       = "Category of the last check result"
 	statusDetailHelp = "Detailed reason of the last check result"
+	tlsExpiryHelp    = "Expiry time of the dependency TLS leaf certificate as a Unix timestamp"
+	consumerLagHelp  = "Total lag in messages of the Kafka consumer group checked by the dependency"
+	replLagHelp      = "Replication lag of the database replica in seconds"
+	connectHelp      = "Latency of establishing a new connection to the dependency in seconds"
 )
 
 // Histogram buckets from the specification.
@@ -28,6 +32,10 @@ type MetricsExporter struct {
 	latency      *prometheus.HistogramVec
 	status       *prometheus.GaugeVec
 	statusDetail *prometheus.GaugeVec
+	tlsExpiry    *prometheus.GaugeVec
+	consumerLag  *prometheus.GaugeVec
+	replLag      *prometheus.GaugeVec
+	connect      *prometheus.HistogramVec
 
 	// instanceName is the application name (the "name" label).
 	instanceName string
@@ -47,7 +55,7 @@ type MetricsExporter struct {
 	labelCache map[string]prometheus.Labels
 
 	// prevStatus tracks the previous status category per endpoint key.
-	// Used to avoid updating all 8 status gauges when status hasn't changed.
+	// Used to avoid updating all 9 status gauges when status hasn't changed.
 	prevStatus map[string]StatusCategory
 
 	// prevDetails tracks the previous detail value per endpoint key.
@@ -140,7 +148,28 @@ func NewMetricsExporter(instanceName string, instanceGroup string, opts ...Metri
 		Help: statusDetailHelp,
 	}, detailLabels)
 
-	for _, collector := range []prometheus.Collector{health, latency, status, statusDetail} {
+	tlsExpiry := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "app_dependency_tls_cert_expiry_timestamp_seconds",
+		Help: tlsExpiryHelp,
+	}, allLabels)
+
+	consumerLag := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "app_dependency_consumer_lag",
+		Help: consumerLagHelp,
+	}, allLabels)
+
+	replLag := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "app_dependency_replication_lag_seconds",
+		Help: replLagHelp,
+	}, allLabels)
+
+	connect := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "app_dependency_connect_latency_seconds",
+		Help:    connectHelp,
+		Buckets: defaultLatencyBuckets,
+	}, allLabels)
+
+	for _, collector := range []prometheus.Collector{health, latency, status, statusDetail, tlsExpiry, consumerLag, replLag, connect} {
 		if err := cfg.registerer.Register(collector); err != nil {
 			return nil, err
 		}
@@ -151,6 +180,10 @@ func NewMetricsExporter(instanceName string, instanceGroup string, opts ...Metri
 		latency:       latency,
 		status:        status,
 		statusDetail:  statusDetail,
+		tlsExpiry:     tlsExpiry,
+		consumerLag:   consumerLag,
+		replLag:       replLag,
+		connect:       connect,
 		instanceName:  instanceName,
 		instanceGroup: instanceGroup,
 		allLabelNames: allLabels,
@@ -171,8 +204,14 @@ func (m *MetricsExporter) ObserveLatency(dep Dependency, ep Endpoint, duration t
 	m.latency.With(m.labels(dep, ep)).Observe(duration.Seconds())
 }
 
+// ObserveConnectLatency records the time spent establishing a new connection
+// in the app_dependency_connect_latency_seconds histogram.
+func (m *MetricsExporter) ObserveConnectLatency(dep Dependency, ep Endpoint, duration time.Duration) {
+	m.connect.With(m.labels(dep, ep)).Observe(duration.Seconds())
+}
+
 // SetStatus updates the app_dependency_status enum gauge.
-// On the first call for an endpoint, all 8 categories are initialized.
+// On the first call for an endpoint, all 9 categories are initialized.
 // On subsequent calls, only changed categories are updated (delta update).
 // If the status hasn't changed since the last call, no gauges are touched.
 func (m *MetricsExporter) SetStatus(dep Dependency, ep Endpoint, category StatusCategory) {
@@ -199,7 +238,7 @@ func (m *MetricsExporter) SetStatus(dep Dependency, ep Endpoint, category Status
 		newLabels["status"] = string(category)
 		m.status.With(newLabels).Set(1)
 	} else {
-		// First call: initialize all 8 status gauges.
+		// First call: initialize all 9 status gauges.
 		for _, s := range AllStatusCategories {
 			labels := copyLabels(base)
 			labels["status"] = string(s)
@@ -241,16 +280,50 @@ func (m *MetricsExporter) SetStatusDetail(dep Dependency, ep Endpoint, detail st
 	m.statusDetail.With(labels).Set(1)
 }
 
+// SetTLSCertExpiry updates the app_dependency_tls_cert_expiry_timestamp_seconds gauge.
+// The series exists only for endpoints whose checker reported a certificate.
+func (m *MetricsExporter) SetTLSCertExpiry(dep Dependency, ep Endpoint, notAfter time.Time) {
+	m.tlsExpiry.With(m.labels(dep, ep)).Set(float64(notAfter.Unix()))
+}
+
+// SetConsumerLag updates the app_dependency_consumer_lag gauge.
+// The series exists only for endpoints whose checker reported a lag.
+func (m *MetricsExporter) SetConsumerLag(dep Dependency, ep Endpoint, lag int64) {
+	m.consumerLag.With(m.labels(dep, ep)).Set(float64(lag))
+}
+
+// DeleteConsumerLag removes the app_dependency_consumer_lag series of an
+// endpoint whose last check did not report a lag.
+func (m *MetricsExporter) DeleteConsumerLag(dep Dependency, ep Endpoint) {
+	m.consumerLag.Delete(m.labels(dep, ep))
+}
+
+// SetReplicationLag updates the app_dependency_replication_lag_seconds gauge.
+// The series exists only for endpoints whose checker reported a lag.
+func (m *MetricsExporter) SetReplicationLag(dep Dependency, ep Endpoint, lag time.Duration) {
+	m.replLag.With(m.labels(dep, ep)).Set(lag.Seconds())
+}
+
+// DeleteReplicationLag removes the app_dependency_replication_lag_seconds
+// series of an endpoint that is no longer a replica.
+func (m *MetricsExporter) DeleteReplicationLag(dep Dependency, ep Endpoint) {
+	m.replLag.Delete(m.labels(dep, ep))
+}
+
 // DeleteMetrics removes metric series for the specified endpoint.
 // Used when dynamically removing a dependency.
 func (m *MetricsExporter) DeleteMetrics(dep Dependency, ep Endpoint) {
 	base := m.labels(dep, ep)
 	m.health.Delete(base)
 	m.latency.Delete(base)
+	m.tlsExpiry.Delete(base)
+	m.consumerLag.Delete(base)
+	m.replLag.Delete(base)
+	m.connect.Delete(base)
 
 	key := endpointKey(dep, ep)
 
-	// Delete all 8 status series.
+	// Delete all 9 status series.
 	for _, s := range AllStatusCategories {
 		labels := copyLabels(base)
 		labels["status"] = string(s)
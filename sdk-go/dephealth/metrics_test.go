@@ -233,6 +233,7 @@ func TestMetricsExporter_SetStatus(t *testing.T) {
 		# TYPE app_dependency_status gauge
 		app_dependency_status{critical="yes",dependency="postgres-main",group="test-group",host="pg.svc",name="test-app",port="5432",status="auth_error",type="postgres"} 0
 		app_dependency_status{critical="yes",dependency="postgres-main",group="test-group",host="pg.svc",name="test-app",port="5432",status="connection_error",type="postgres"} 0
+		app_dependency_status{critical="yes",dependency="postgres-main",group="test-group",host="pg.svc",name="test-app",port="5432",status="degraded",type="postgres"} 0
 		app_dependency_status{critical="yes",dependency="postgres-main",group="test-group",host="pg.svc",name="test-app",port="5432",status="dns_error",type="postgres"} 0
 		app_dependency_status{critical="yes",dependency="postgres-main",group="test-group",host="pg.svc",name="test-app",port="5432",status="error",type="postgres"} 0
 		app_dependency_status{critical="yes",dependency="postgres-main",group="test-group",host="pg.svc",name="test-app",port="5432",status="ok",type="postgres"} 1
@@ -251,7 +252,7 @@ func TestMetricsExporter_SetStatus_DeltaUpdate(t *testing.T) {
 	dep := Dependency{Name: "redis-cache", Type: TypeRedis, Critical: boolPtr(false)}
 	ep := Endpoint{Host: "redis.svc", Port: "6379"}
 
-	// First call — initializes all 8 gauges.
+	// First call — initializes all 9 gauges.
 	m.SetStatus(dep, ep, StatusOK)
 
 	// Second call with same status — should be a no-op (delta optimization).
@@ -266,6 +267,7 @@ func TestMetricsExporter_SetStatus_DeltaUpdate(t *testing.T) {
 		# TYPE app_dependency_status gauge
 		app_dependency_status{critical="no",dependency="redis-cache",group="test-group",host="redis.svc",name="test-app",port="6379",status="auth_error",type="redis"} 0
 		app_dependency_status{critical="no",dependency="redis-cache",group="test-group",host="redis.svc",name="test-app",port="6379",status="connection_error",type="redis"} 0
+		app_dependency_status{critical="no",dependency="redis-cache",group="test-group",host="redis.svc",name="test-app",port="6379",status="degraded",type="redis"} 0
 		app_dependency_status{critical="no",dependency="redis-cache",group="test-group",host="redis.svc",name="test-app",port="6379",status="dns_error",type="redis"} 0
 		app_dependency_status{critical="no",dependency="redis-cache",group="test-group",host="redis.svc",name="test-app",port="6379",status="error",type="redis"} 0
 		app_dependency_status{critical="no",dependency="redis-cache",group="test-group",host="redis.svc",name="test-app",port="6379",status="ok",type="redis"} 0
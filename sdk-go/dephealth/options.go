@@ -1,14 +1,22 @@
 package dephealth
 
 import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/jsonpath"
+	"github.com/BigKAA/topologymetrics/sdk-go/dephealth/internal/tlsutil"
 )
 
 // Option is a functional option for New().
@@ -21,6 +29,7 @@ type DependencyOption func(*DependencyConfig)
 type config struct {
 	interval   time.Duration
 	timeout    time.Duration
+	tlsWarning time.Duration
 	registerer prometheus.Registerer
 	logger     *slog.Logger
 	entries    []dependencyEntry
@@ -37,6 +46,20 @@ type DependencyConfig struct {
 	Timeout  time.Duration
 	Labels   map[string]string // Custom labels via WithLabel.
 
+	TLSExpiryWarning time.Duration
+	LatencySLO       time.Duration
+
+	// ErrorClassifiers map check errors of this dependency to a status;
+	// see WithErrorClassifier.
+	ErrorClassifiers []ErrorClassifier
+
+	// Shared TLS settings, honoured by every checker that can use TLS.
+	// Checker-specific CA and client certificate options take precedence.
+	TLSConfig   *tls.Config
+	TLSCAFile   string
+	TLSCertFile string
+	TLSKeyFile  string
+
 	// Checker-specific options.
 	HTTPHealthPath    string
 	HTTPTLS           *bool
@@ -46,8 +69,29 @@ type DependencyConfig struct {
 	HTTPBasicUser     string
 	HTTPBasicPass     string
 
+	// Credential providers, called on every check; they take precedence
+	// over the static token and password.
+	HTTPBearerTokenProvider CredentialProvider
+	HTTPBasicPassProvider   CredentialProvider
+
 	HTTPHostHeader string // overrides Host header (and TLS SNI when HTTPS)
 
+	HTTPPersistentConnections bool
+	HTTPIdleTimeout           time.Duration
+
+	HTTPMethod          string
+	HTTPBody            []byte
+	HTTPBodyContentType string
+	HTTPExpectedStatus  []int
+	HTTPMaxRedirects    *int
+	HTTPMaxBodySize     int64
+	HTTPBodyContains    string
+	HTTPBodyRegex       string
+	HTTPBodyJSONPath    string
+	HTTPBodyJSONValue   string
+	HTTPResponseFormat  string
+	HTTPTopologyDepth   int
+
 	GRPCServiceName   string
 	GRPCTLS           *bool
 	GRPCTLSSkipVerify *bool
@@ -57,22 +101,121 @@ type DependencyConfig struct {
 	GRPCBasicPass     string
 	GRPCAuthority     string // overrides :authority pseudo-header (and TLS SNI when TLS)
 
-	PostgresQuery string
-	MySQLQuery    string
-
-	RedisPassword string
-	RedisDB       *int
-
-	AMQPURL string
-
-	LDAPCheckMethod   string // "anonymous_bind", "simple_bind", "root_dse", "search"
-	LDAPBindDN        string
-	LDAPBindPassword  string
-	LDAPBaseDN        string
-	LDAPSearchFilter  string
-	LDAPSearchScope   string // "base", "one", "sub"
-	LDAPStartTLS      *bool
-	LDAPTLSSkipVerify *bool
+	GRPCBearerTokenProvider CredentialProvider
+	GRPCBasicPassProvider   CredentialProvider
+
+	GRPCPersistentConnections bool
+	GRPCIdleTimeout           time.Duration
+
+	PostgresQuery             string
+	PostgresRole              string // "primary" or "replica"
+	PostgresMaxReplicationLag time.Duration
+	PostgresUser              string
+	PostgresPassword          string
+	PostgresDatabase          string
+	PostgresSSLMode           string // libpq sslmode
+	PostgresTLSCAFile         string
+	PostgresApplicationName   string
+	MySQLQuery                string
+	MySQLRole                 string // "primary" or "replica"
+	MySQLMaxReplicationLag    time.Duration
+	MySQLUser                 string
+	MySQLPassword             string
+	MySQLDatabase             string
+	MySQLSSLMode              string // MySQL ssl-mode or libpq sslmode spelling
+	MySQLTLSCAFile            string
+	MySQLApplicationName      string
+
+	RedisUsername         string
+	RedisPassword         string
+	RedisPasswordProvider CredentialProvider
+	RedisDB               *int
+	RedisTLS              *bool
+	RedisTLSCAFile        string
+	RedisTLSCertFile      string
+	RedisTLSKeyFile       string
+	RedisTLSServerName    string
+	RedisTLSSkipVerify    *bool
+	RedisMode             string // "standalone" (default), "sentinel", "cluster"
+	RedisMasterName       string
+	RedisSentinelPassword string
+
+	AMQPURL           string
+	AMQPUsername      string
+	AMQPPassword      string
+	AMQPVHost         string
+	AMQPTLS           *bool
+	AMQPTLSCAFile     string
+	AMQPTLSCertFile   string
+	AMQPTLSKeyFile    string
+	AMQPTLSSkipVerify *bool
+	AMQPChannel       bool
+	AMQPQueues        []string
+	AMQPMaxQueueDepth int
+	AMQPMinConsumers  int
+
+	KafkaSASLMechanism string // "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512"
+	KafkaUsername      string
+	KafkaPassword      string
+	KafkaTLS           *bool
+	KafkaTLSCAFile     string
+	KafkaTLSCertFile   string
+	KafkaTLSKeyFile    string
+	KafkaTLSSkipVerify *bool
+	KafkaTopic         string
+	KafkaMinISR        int
+	KafkaConsumerGroup string
+	KafkaMaxLag        int64
+
+	LDAPCheckMethod          string // "anonymous_bind", "simple_bind", "root_dse", "search"
+	LDAPBindDN               string
+	LDAPBindPassword         string
+	LDAPBindPasswordProvider CredentialProvider
+	LDAPBaseDN               string
+	LDAPSearchFilter         string
+	LDAPSearchScope          string // "base", "one", "sub"
+	LDAPStartTLS             *bool
+	LDAPTLSSkipVerify        *bool
+
+	EtcdUsername      string
+	EtcdPassword      string
+	EtcdTLS           *bool
+	EtcdTLSCAFile     string
+	EtcdTLSCertFile   string
+	EtcdTLSKeyFile    string
+	EtcdTLSSkipVerify *bool
+
+	ConsulToken         string
+	ConsulAgentCheck    *bool
+	ConsulTLS           *bool
+	ConsulTLSCAFile     string
+	ConsulTLSCertFile   string
+	ConsulTLSKeyFile    string
+	ConsulTLSSkipVerify *bool
+
+	ClickHouseProtocol        string // "native" (default), "http"
+	ClickHouseUsername        string
+	ClickHousePassword        string
+	ClickHouseDatabase        string
+	ClickHouseMaxReplicaDelay time.Duration
+	ClickHouseTLS             *bool
+	ClickHouseTLSSkipVerify   *bool
+
+	CassandraUsername      string
+	CassandraPassword      string
+	CassandraConsistency   string // "ONE" (default), "LOCAL_ONE", "QUORUM", ...
+	CassandraTLS           *bool
+	CassandraTLSSkipVerify *bool
+
+	TLSServerName  string
+	TLSMinVersion  string   // "1.0", "1.1", "1.2" (default), "1.3"
+	TLSPinnedCerts []string // SHA-256 fingerprints of the leaf certificate (hex)
+	TLSSkipVerify  *bool
+
+	DNSName       string
+	DNSRecordType string // "A" (default), "AAAA", "CNAME", "SRV", "TXT"
+	DNSExpected   []string
+	DNSProtocol   string // "udp" (default), "tcp"
 }
 
 // dependencyEntry is a dependency with its checker, ready for registration.
@@ -116,6 +259,16 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// WithTLSExpiryWarning sets the global TLS certificate expiry warning window.
+// A successful check of a TLS dependency whose leaf certificate expires within
+// d reports detail "tls_expiring" (status stays "ok"). Zero disables the warning.
+func WithTLSExpiryWarning(d time.Duration) Option {
+	return func(c *config) error {
+		c.tlsWarning = d
+		return nil
+	}
+}
+
 // WithRegisterer sets a custom prometheus.Registerer for the public API.
 func WithRegisterer(r prometheus.Registerer) Option {
 	return func(c *config) error {
@@ -183,6 +336,36 @@ func Timeout(d time.Duration) DependencyOption {
 	}
 }
 
+// TLSExpiryWarning sets the TLS certificate expiry warning window for a specific dependency.
+func TLSExpiryWarning(d time.Duration) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.TLSExpiryWarning = d
+	}
+}
+
+// LatencySLO sets the latency SLO for a specific dependency. Successful
+// checks slower than d count as slow; after the failure threshold of
+// consecutive slow checks the status becomes "degraded" with detail
+// "latency_slo_exceeded" while app_dependency_health stays 1. It must be
+// less than the check timeout.
+func LatencySLO(d time.Duration) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.LatencySLO = d
+	}
+}
+
+// WithErrorClassifier adds an error classifier for a specific dependency.
+// It is consulted before the per-type and global classifiers
+// (RegisterTypeErrorClassifier, RegisterErrorClassifier). May be given
+// several times; a nil classifier is ignored.
+func WithErrorClassifier(c ErrorClassifier) DependencyOption {
+	return func(dc *DependencyConfig) {
+		if c != nil {
+			dc.ErrorClassifiers = append(dc.ErrorClassifiers, c)
+		}
+	}
+}
+
 // --- Checker wrappers (DependencyOption) ---
 
 // WithHTTPHealthPath sets the path for HTTP health checks.
@@ -223,168 +406,942 @@ func WithGRPCTLS(enabled bool) DependencyOption {
 // WithGRPCTLSSkipVerify disables TLS certificate verification for gRPC.
 func WithGRPCTLSSkipVerify(skip bool) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.GRPCTLSSkipVerify = &skip
+		dc.GRPCTLSSkipVerify = &skip
+	}
+}
+
+// WithHTTPHostHeader sets the HTTP Host header for health check requests.
+// Used when connecting by IP through ingress/gateway for Host-based routing.
+// When TLS is enabled, also sets TLS SNI (ServerName) to the same value.
+// Does NOT affect the "host" metric label (which always reflects the real endpoint address).
+func WithHTTPHostHeader(host string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPHostHeader = host
+	}
+}
+
+// WithHTTPHeaders sets custom HTTP headers for health check requests.
+func WithHTTPHeaders(headers map[string]string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPHeaders = headers
+	}
+}
+
+// WithHTTPBearerToken sets a Bearer token for HTTP health check requests.
+// Adds Authorization: Bearer <token> header.
+func WithHTTPBearerToken(token string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPBearerToken = token
+	}
+}
+
+// WithHTTPBasicAuth sets Basic Auth credentials for HTTP health check requests.
+// Adds Authorization: Basic <base64(username:password)> header.
+func WithHTTPBasicAuth(username, password string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPBasicUser = username
+		dc.HTTPBasicPass = password
+	}
+}
+
+// WithHTTPBearerTokenProvider sets a provider of the Bearer token, called
+// before every HTTP health check request, e.g. OAuth2ClientCredentials or
+// FileCredential.
+func WithHTTPBearerTokenProvider(p CredentialProvider) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPBearerTokenProvider = p
+	}
+}
+
+// WithHTTPBasicAuthProvider sets Basic Auth credentials whose password is
+// obtained from p before every HTTP health check request.
+func WithHTTPBasicAuthProvider(username string, password CredentialProvider) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPBasicUser = username
+		dc.HTTPBasicPassProvider = password
+	}
+}
+
+// WithGRPCAuthority sets the :authority pseudo-header for gRPC health check calls.
+// Used when connecting by IP through ingress/gateway for authority-based routing.
+// When TLS is enabled, also sets TLS SNI (ServerName) to the same value.
+// Does NOT affect the "host" metric label (which always reflects the real endpoint address).
+func WithGRPCAuthority(authority string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.GRPCAuthority = authority
+	}
+}
+
+// WithGRPCMetadata sets custom gRPC metadata for health check calls.
+func WithGRPCMetadata(metadata map[string]string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.GRPCMetadata = metadata
+	}
+}
+
+// WithGRPCBearerToken sets a Bearer token for gRPC health check calls.
+// Adds authorization: Bearer <token> metadata.
+func WithGRPCBearerToken(token string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.GRPCBearerToken = token
+	}
+}
+
+// WithGRPCBasicAuth sets Basic Auth credentials for gRPC health check calls.
+// Adds authorization: Basic <base64(username:password)> metadata.
+func WithGRPCBasicAuth(username, password string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.GRPCBasicUser = username
+		dc.GRPCBasicPass = password
+	}
+}
+
+// WithGRPCBearerTokenProvider sets a provider of the Bearer token, called
+// before every gRPC health check call.
+func WithGRPCBearerTokenProvider(p CredentialProvider) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.GRPCBearerTokenProvider = p
+	}
+}
+
+// WithGRPCBasicAuthProvider sets Basic Auth credentials whose password is
+// obtained from p before every gRPC health check call.
+func WithGRPCBasicAuthProvider(username string, password CredentialProvider) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.GRPCBasicUser = username
+		dc.GRPCBasicPassProvider = password
+	}
+}
+
+// WithHTTPPersistentConnections reuses one connection per endpoint across
+// checks instead of connecting for every check. Connections idle for longer
+// than idleTimeout (default 90s when zero) are closed; all are closed when
+// the endpoint is removed or the scheduler stops. The connect time of new
+// connections is exported as app_dependency_connect_latency_seconds.
+func WithHTTPPersistentConnections(idleTimeout time.Duration) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPPersistentConnections = true
+		dc.HTTPIdleTimeout = idleTimeout
+	}
+}
+
+// WithHTTPMethod sets the HTTP method of health check requests (default GET).
+func WithHTTPMethod(method string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPMethod = method
+	}
+}
+
+// WithHTTPRequestBody sets the request body and its Content-Type, typically
+// together with WithHTTPMethod("POST").
+func WithHTTPRequestBody(contentType string, body []byte) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPBodyContentType = contentType
+		dc.HTTPBody = body
+	}
+}
+
+// WithHTTPExpectedStatus sets the accepted response status codes, replacing
+// the default of any 2xx code.
+func WithHTTPExpectedStatus(codes ...int) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPExpectedStatus = codes
+	}
+}
+
+// WithHTTPMaxRedirects limits the number of redirects followed (default 10).
+// With 0 redirects are not followed and a 3xx response fails unless accepted
+// by WithHTTPExpectedStatus.
+func WithHTTPMaxRedirects(n int) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPMaxRedirects = &n
+	}
+}
+
+// WithHTTPMaxBodySize limits how many bytes of the response body are read
+// for body assertions (default 1 MiB).
+func WithHTTPMaxBodySize(n int64) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPMaxBodySize = n
+	}
+}
+
+// WithHTTPBodyContains requires the response body to contain substr.
+// A mismatch is reported as unhealthy with detail body_mismatch.
+func WithHTTPBodyContains(substr string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPBodyContains = substr
+	}
+}
+
+// WithHTTPBodyRegex requires the response body to match the regular
+// expression pattern (RE2 syntax).
+// A mismatch is reported as unhealthy with detail body_mismatch.
+func WithHTTPBodyRegex(pattern string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPBodyRegex = pattern
+	}
+}
+
+// WithHTTPResponseFormat interprets the response body as a health response:
+// "spring" (Spring Boot Actuator), "health+json" (IETF
+// application/health+json), "dephealth" (HealthDetails JSON of another
+// dephealth service) or "auto" to detect it. DOWN and OUT_OF_SERVICE fail the
+// check (details health_down, health_out_of_service), WARN keeps it healthy
+// with detail health_warn, and the sub-components of the response are
+// exposed in EndpointStatus.Components.
+func WithHTTPResponseFormat(format string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPResponseFormat = format
+	}
+}
+
+// WithHTTPTopologyDepth records the dependencies of a downstream dephealth
+// service as transitive edges in EndpointStatus.Dependencies, following
+// the edges that service itself recorded up to depth levels (1 = its direct
+// dependencies only). The response format defaults to "dephealth". Edges
+// that lead back to an endpoint already on the path are not expanded.
+func WithHTTPTopologyDepth(depth int) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPTopologyDepth = depth
+	}
+}
+
+// WithHTTPBodyJSONPath requires the JSON response body to have expected at
+// path, e.g. WithHTTPBodyJSONPath("$.status", "UP"). Non-string values are
+// compared in compact JSON form ("true", "42").
+// A mismatch is reported as unhealthy with detail body_mismatch.
+func WithHTTPBodyJSONPath(path, expected string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.HTTPBodyJSONPath = path
+		dc.HTTPBodyJSONValue = expected
+	}
+}
+
+// WithGRPCPersistentConnections keeps one gRPC ClientConn per endpoint
+// across checks. The connection goes idle after idleTimeout without checks
+// (default 30m when zero) and is closed when the endpoint is removed or the
+// scheduler stops. The connect time of new connections is exported as
+// app_dependency_connect_latency_seconds.
+func WithGRPCPersistentConnections(idleTimeout time.Duration) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.GRPCPersistentConnections = true
+		dc.GRPCIdleTimeout = idleTimeout
+	}
+}
+
+// WithPostgresQuery sets the SQL query for PostgreSQL health checks.
+func WithPostgresQuery(query string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.PostgresQuery = query
+	}
+}
+
+// WithPostgresRole requires the server to have the given role: "primary"
+// (pg_is_in_recovery() is false) or "replica" (detail wrong_role).
+func WithPostgresRole(role string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.PostgresRole = role
+	}
+}
+
+// WithPostgresMaxReplicationLag marks a replica unhealthy (detail
+// replication_lag) when its replay lag exceeds d. The measured lag is
+// exported as app_dependency_replication_lag_seconds.
+func WithPostgresMaxReplicationLag(d time.Duration) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.PostgresMaxReplicationLag = d
+	}
+}
+
+// WithPostgresCredentials sets the user and password for standalone checks.
+// They override the URL credentials; an empty value keeps the URL value.
+func WithPostgresCredentials(user, password string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.PostgresUser = user
+		dc.PostgresPassword = password
+	}
+}
+
+// WithPostgresDatabase sets the database for standalone checks
+// (default "postgres").
+func WithPostgresDatabase(database string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.PostgresDatabase = database
+	}
+}
+
+// WithPostgresSSLMode sets the libpq sslmode for standalone checks
+// (disable, allow, prefer, require, verify-ca, verify-full).
+func WithPostgresSSLMode(mode string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.PostgresSSLMode = mode
+	}
+}
+
+// WithPostgresTLSCA sets the PEM CA bundle (sslrootcert) for standalone
+// checks. Without WithPostgresSSLMode, it implies sslmode=verify-full.
+func WithPostgresTLSCA(caFile string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.PostgresTLSCAFile = caFile
+	}
+}
+
+// WithPostgresApplicationName sets application_name for standalone checks.
+func WithPostgresApplicationName(name string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.PostgresApplicationName = name
+	}
+}
+
+// WithMySQLQuery sets the SQL query for MySQL health checks.
+func WithMySQLQuery(query string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.MySQLQuery = query
+	}
+}
+
+// WithMySQLCredentials sets the user and password for standalone checks.
+// They override the URL credentials; an empty value keeps the URL value.
+func WithMySQLCredentials(user, password string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.MySQLUser = user
+		dc.MySQLPassword = password
+	}
+}
+
+// WithMySQLDatabase sets the default database for standalone checks.
+func WithMySQLDatabase(database string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.MySQLDatabase = database
+	}
+}
+
+// WithMySQLSSLMode sets the TLS mode for standalone checks: disabled,
+// preferred, required, verify_ca or verify_identity (libpq spellings such as
+// verify-full are accepted).
+func WithMySQLSSLMode(mode string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.MySQLSSLMode = mode
+	}
+}
+
+// WithMySQLTLSCA sets the PEM CA bundle for standalone checks. Without
+// WithMySQLSSLMode, it implies verify_identity.
+func WithMySQLTLSCA(caFile string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.MySQLTLSCAFile = caFile
+	}
+}
+
+// WithMySQLApplicationName sets the program_name connection attribute for
+// standalone checks.
+func WithMySQLApplicationName(name string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.MySQLApplicationName = name
+	}
+}
+
+// WithMySQLRole requires @@global.read_only to match the role: "primary"
+// (read_only off) or "replica" (read_only on, replication running). Failures
+// report wrong_role or replication_stopped.
+func WithMySQLRole(role string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.MySQLRole = role
+	}
+}
+
+// WithMySQLMaxReplicationLag marks a replica unhealthy (detail
+// replication_lag) when Seconds_Behind_Source exceeds d. The measured lag is
+// exported as app_dependency_replication_lag_seconds.
+func WithMySQLMaxReplicationLag(d time.Duration) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.MySQLMaxReplicationLag = d
+	}
+}
+
+// WithRedisUsername sets the ACL username for Redis 6+ (standalone mode).
+func WithRedisUsername(username string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.RedisUsername = username
+	}
+}
+
+// WithRedisPassword sets the password for Redis (standalone mode).
+func WithRedisPassword(password string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.RedisPassword = password
+	}
+}
+
+// WithRedisPasswordProvider sets a provider of the Redis password, called
+// before every check (standalone mode). It takes precedence over
+// WithRedisPassword.
+func WithRedisPasswordProvider(p CredentialProvider) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.RedisPasswordProvider = p
+	}
+}
+
+// WithRedisDB sets the Redis database number (standalone mode).
+func WithRedisDB(db int) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.RedisDB = &db
+	}
+}
+
+// WithRedisTLS enables TLS for Redis connections.
+// Enabled automatically for rediss:// URLs.
+func WithRedisTLS(enabled bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.RedisTLS = &enabled
+	}
+}
+
+// WithRedisTLSCA sets the PEM CA bundle used to verify Redis server certificates.
+// Implies TLS.
+func WithRedisTLSCA(caFile string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.RedisTLSCAFile = caFile
+	}
+}
+
+// WithRedisTLSClientCert sets the client certificate and key for Redis mTLS.
+// Implies TLS.
+func WithRedisTLSClientCert(certFile, keyFile string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.RedisTLSCertFile = certFile
+		dc.RedisTLSKeyFile = keyFile
+	}
+}
+
+// WithRedisTLSServerName overrides the SNI and the hostname verified against
+// the Redis server certificate (default: the host being dialed).
+func WithRedisTLSServerName(serverName string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.RedisTLSServerName = serverName
+	}
+}
+
+// WithRedisTLSSkipVerify disables TLS certificate verification for Redis.
+func WithRedisTLSSkipVerify(skip bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.RedisTLSSkipVerify = &skip
+	}
+}
+
+// WithRedisSentinel treats the endpoints as Redis Sentinel instances
+// monitoring the given master. Each check resolves the master through the
+// sentinel and pings it. Set automatically for redis-sentinel:// URLs, where
+// the master name is taken from the URL fragment.
+func WithRedisSentinel(masterName string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.RedisMode = "sentinel"
+		dc.RedisMasterName = masterName
+	}
+}
+
+// WithRedisSentinelPassword sets the password for Redis Sentinel connections
+// (the master uses WithRedisPassword).
+func WithRedisSentinelPassword(password string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.RedisSentinelPassword = password
+	}
+}
+
+// WithRedisCluster treats the endpoints as Redis Cluster nodes. Each check
+// runs CLUSTER INFO on the node and requires cluster_state:ok.
+func WithRedisCluster() DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.RedisMode = "cluster"
+	}
+}
+
+// WithAMQPURL sets the full AMQP URL for connections.
+// The URL is used verbatim for every endpoint.
+func WithAMQPURL(url string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.AMQPURL = url
+	}
+}
+
+// WithAMQPCredentials sets the AMQP username and password
+// (override the URL credentials, default guest/guest).
+func WithAMQPCredentials(username, password string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.AMQPUsername = username
+		dc.AMQPPassword = password
+	}
+}
+
+// WithAMQPVHost sets the AMQP virtual host (overrides the URL path, default "/").
+func WithAMQPVHost(vhost string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.AMQPVHost = vhost
+	}
+}
+
+// WithAMQPTLS enables TLS (amqps) for AMQP connections.
+// Enabled automatically for amqps:// URLs.
+func WithAMQPTLS(enabled bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.AMQPTLS = &enabled
+	}
+}
+
+// WithAMQPTLSCA sets the PEM CA bundle used to verify AMQP broker certificates.
+// Implies TLS.
+func WithAMQPTLSCA(caFile string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.AMQPTLSCAFile = caFile
+	}
+}
+
+// WithAMQPTLSClientCert sets the client certificate and key for AMQP mTLS.
+// Implies TLS.
+func WithAMQPTLSClientCert(certFile, keyFile string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.AMQPTLSCertFile = certFile
+		dc.AMQPTLSKeyFile = keyFile
+	}
+}
+
+// WithAMQPTLSSkipVerify disables TLS certificate verification for AMQP.
+func WithAMQPTLSSkipVerify(skip bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.AMQPTLSSkipVerify = &skip
+	}
+}
+
+// WithAMQPChannel opens a channel after connecting, so a broker that accepts
+// connections but refuses channels is reported unhealthy.
+func WithAMQPChannel() DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.AMQPChannel = true
+	}
+}
+
+// WithAMQPQueues requires the queues to exist in the virtual host; each is
+// declared passively (detail queue_missing). Implies WithAMQPChannel.
+func WithAMQPQueues(queues ...string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.AMQPQueues = append(dc.AMQPQueues, queues...)
+	}
+}
+
+// WithAMQPMaxQueueDepth marks the dependency unhealthy (detail queue_depth)
+// when a queue set by WithAMQPQueues holds more than n ready messages.
+func WithAMQPMaxQueueDepth(n int) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.AMQPMaxQueueDepth = n
+	}
+}
+
+// WithAMQPMinConsumers marks the dependency unhealthy (detail queue_consumers)
+// when a queue set by WithAMQPQueues has fewer than n consumers.
+func WithAMQPMinConsumers(n int) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.AMQPMinConsumers = n
+	}
+}
+
+// WithKafkaSASL enables SASL authentication for Kafka.
+// Supported mechanisms: "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512".
+func WithKafkaSASL(mechanism, username, password string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.KafkaSASLMechanism = mechanism
+		dc.KafkaUsername = username
+		dc.KafkaPassword = password
+	}
+}
+
+// WithKafkaTLS enables TLS for Kafka broker connections.
+func WithKafkaTLS(enabled bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.KafkaTLS = &enabled
+	}
+}
+
+// WithKafkaTLSCA sets the PEM CA bundle used to verify Kafka broker certificates.
+// Implies TLS.
+func WithKafkaTLSCA(caFile string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.KafkaTLSCAFile = caFile
+	}
+}
+
+// WithKafkaTLSClientCert sets the client certificate and key for Kafka mTLS.
+// Implies TLS.
+func WithKafkaTLSClientCert(certFile, keyFile string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.KafkaTLSCertFile = certFile
+		dc.KafkaTLSKeyFile = keyFile
+	}
+}
+
+// WithKafkaTLSSkipVerify disables TLS certificate verification for Kafka.
+func WithKafkaTLSSkipVerify(skip bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.KafkaTLSSkipVerify = &skip
+	}
+}
+
+// WithKafkaTopic requires the topic to exist and every partition to have a
+// leader (details topic_missing, no_leader).
+func WithKafkaTopic(topic string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.KafkaTopic = topic
+	}
+}
+
+// WithKafkaMinISR requires at least n in-sync replicas for every partition
+// of the topic set by WithKafkaTopic (detail under_replicated).
+func WithKafkaMinISR(n int) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.KafkaMinISR = n
+	}
+}
+
+// WithKafkaConsumerGroup reports the total lag of the consumer group
+// (app_dependency_consumer_lag). With maxLag > 0 the dependency is unhealthy
+// (detail consumer_lag) when the lag exceeds maxLag. The lag is limited to
+// the topic set by WithKafkaTopic, if any.
+func WithKafkaConsumerGroup(group string, maxLag int64) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.KafkaConsumerGroup = group
+		dc.KafkaMaxLag = maxLag
+	}
+}
+
+// WithLDAPCheckMethod sets the LDAP check method.
+// Valid values: "anonymous_bind", "simple_bind", "root_dse", "search".
+func WithLDAPCheckMethod(method string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.LDAPCheckMethod = method
+	}
+}
+
+// WithLDAPBindDN sets the DN for LDAP Simple Bind.
+func WithLDAPBindDN(dn string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.LDAPBindDN = dn
+	}
+}
+
+// WithLDAPBindPassword sets the password for LDAP Simple Bind.
+func WithLDAPBindPassword(password string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.LDAPBindPassword = password
+	}
+}
+
+// WithLDAPBindPasswordProvider sets a provider of the LDAP Simple Bind
+// password, called before every check. It takes precedence over
+// WithLDAPBindPassword.
+func WithLDAPBindPasswordProvider(p CredentialProvider) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.LDAPBindPasswordProvider = p
+	}
+}
+
+// WithLDAPBaseDN sets the base DN for LDAP search method.
+func WithLDAPBaseDN(baseDN string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.LDAPBaseDN = baseDN
+	}
+}
+
+// WithLDAPSearchFilter sets the LDAP search filter.
+func WithLDAPSearchFilter(filter string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.LDAPSearchFilter = filter
+	}
+}
+
+// WithLDAPSearchScope sets the LDAP search scope.
+// Valid values: "base", "one", "sub".
+func WithLDAPSearchScope(scope string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.LDAPSearchScope = scope
+	}
+}
+
+// WithLDAPStartTLS enables StartTLS for LDAP connections (only with ldap://).
+func WithLDAPStartTLS(enabled bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.LDAPStartTLS = &enabled
+	}
+}
+
+// WithLDAPTLSSkipVerify disables TLS certificate verification for LDAP.
+func WithLDAPTLSSkipVerify(skip bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.LDAPTLSSkipVerify = &skip
+	}
+}
+
+// WithEtcdAuth sets the username and password for etcd authentication.
+func WithEtcdAuth(username, password string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.EtcdUsername = username
+		dc.EtcdPassword = password
+	}
+}
+
+// WithEtcdTLS enables TLS for etcd connections.
+func WithEtcdTLS(enabled bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.EtcdTLS = &enabled
+	}
+}
+
+// WithEtcdTLSCA sets the PEM CA bundle used to verify etcd server certificates.
+// Implies TLS.
+func WithEtcdTLSCA(caFile string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.EtcdTLSCAFile = caFile
+	}
+}
+
+// WithEtcdTLSClientCert sets the client certificate and key for etcd mTLS.
+// Implies TLS.
+func WithEtcdTLSClientCert(certFile, keyFile string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.EtcdTLSCertFile = certFile
+		dc.EtcdTLSKeyFile = keyFile
+	}
+}
+
+// WithEtcdTLSSkipVerify disables TLS certificate verification for etcd.
+func WithEtcdTLSSkipVerify(skip bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.EtcdTLSSkipVerify = &skip
+	}
+}
+
+// WithConsulToken sets the ACL token for Consul requests (X-Consul-Token header).
+func WithConsulToken(token string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.ConsulToken = token
+	}
+}
+
+// WithConsulAgentCheck enables the additional /v1/agent/self check
+// of the local Consul agent after the leader check.
+func WithConsulAgentCheck(enabled bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.ConsulAgentCheck = &enabled
+	}
+}
+
+// WithConsulTLS enables HTTPS for Consul requests.
+func WithConsulTLS(enabled bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.ConsulTLS = &enabled
+	}
+}
+
+// WithConsulTLSCA sets the PEM CA bundle used to verify Consul server certificates.
+// Implies TLS.
+func WithConsulTLSCA(caFile string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.ConsulTLSCAFile = caFile
+	}
+}
+
+// WithConsulTLSClientCert sets the client certificate and key for Consul mTLS.
+// Implies TLS.
+func WithConsulTLSClientCert(certFile, keyFile string) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.ConsulTLSCertFile = certFile
+		dc.ConsulTLSKeyFile = keyFile
+	}
+}
+
+// WithConsulTLSSkipVerify disables TLS certificate verification for Consul.
+func WithConsulTLSSkipVerify(skip bool) DependencyOption {
+	return func(dc *DependencyConfig) {
+		dc.ConsulTLSSkipVerify = &skip
 	}
 }
 
-// WithHTTPHostHeader sets the HTTP Host header for health check requests.
-// Used when connecting by IP through ingress/gateway for Host-based routing.
-// When TLS is enabled, also sets TLS SNI (ServerName) to the same value.
-// Does NOT affect the "host" metric label (which always reflects the real endpoint address).
-func WithHTTPHostHeader(host string) DependencyOption {
+// WithClickHouseProtocol sets the ClickHouse interface: "native" (default) or "http".
+// The HTTP interface usually listens on port 8123, so the port must be set explicitly.
+func WithClickHouseProtocol(protocol string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.HTTPHostHeader = host
+		dc.ClickHouseProtocol = protocol
 	}
 }
 
-// WithHTTPHeaders sets custom HTTP headers for health check requests.
-func WithHTTPHeaders(headers map[string]string) DependencyOption {
+// WithClickHouseAuth sets the username and password for ClickHouse (standalone mode).
+func WithClickHouseAuth(username, password string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.HTTPHeaders = headers
+		dc.ClickHouseUsername = username
+		dc.ClickHousePassword = password
 	}
 }
 
-// WithHTTPBearerToken sets a Bearer token for HTTP health check requests.
-// Adds Authorization: Bearer <token> header.
-func WithHTTPBearerToken(token string) DependencyOption {
+// WithClickHouseDatabase sets the ClickHouse database (standalone mode).
+func WithClickHouseDatabase(database string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.HTTPBearerToken = token
+		dc.ClickHouseDatabase = database
 	}
 }
 
-// WithHTTPBasicAuth sets Basic Auth credentials for HTTP health check requests.
-// Adds Authorization: Basic <base64(username:password)> header.
-func WithHTTPBasicAuth(username, password string) DependencyOption {
+// WithClickHouseMaxReplicaDelay enables the replication check: the dependency
+// is reported unhealthy when the largest absolute_delay in system.replicas
+// exceeds the threshold. Zero (default) disables the check.
+func WithClickHouseMaxReplicaDelay(d time.Duration) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.HTTPBasicUser = username
-		dc.HTTPBasicPass = password
+		dc.ClickHouseMaxReplicaDelay = d
 	}
 }
 
-// WithGRPCAuthority sets the :authority pseudo-header for gRPC health check calls.
-// Used when connecting by IP through ingress/gateway for authority-based routing.
-// When TLS is enabled, also sets TLS SNI (ServerName) to the same value.
-// Does NOT affect the "host" metric label (which always reflects the real endpoint address).
-func WithGRPCAuthority(authority string) DependencyOption {
+// WithClickHouseTLS enables TLS for ClickHouse connections.
+func WithClickHouseTLS(enabled bool) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.GRPCAuthority = authority
+		dc.ClickHouseTLS = &enabled
 	}
 }
 
-// WithGRPCMetadata sets custom gRPC metadata for health check calls.
-func WithGRPCMetadata(metadata map[string]string) DependencyOption {
+// WithClickHouseTLSSkipVerify disables TLS certificate verification for ClickHouse.
+func WithClickHouseTLSSkipVerify(skip bool) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.GRPCMetadata = metadata
+		dc.ClickHouseTLSSkipVerify = &skip
 	}
 }
 
-// WithGRPCBearerToken sets a Bearer token for gRPC health check calls.
-// Adds authorization: Bearer <token> metadata.
-func WithGRPCBearerToken(token string) DependencyOption {
+// WithCassandraAuth sets the username and password for Cassandra (standalone mode).
+func WithCassandraAuth(username, password string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.GRPCBearerToken = token
+		dc.CassandraUsername = username
+		dc.CassandraPassword = password
 	}
 }
 
-// WithGRPCBasicAuth sets Basic Auth credentials for gRPC health check calls.
-// Adds authorization: Basic <base64(username:password)> metadata.
-func WithGRPCBasicAuth(username, password string) DependencyOption {
+// WithCassandraConsistency sets the consistency level of the health check query.
+// Valid values: ANY, ONE (default), TWO, THREE, QUORUM, ALL, LOCAL_QUORUM,
+// EACH_QUORUM, LOCAL_ONE.
+func WithCassandraConsistency(level string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.GRPCBasicUser = username
-		dc.GRPCBasicPass = password
+		dc.CassandraConsistency = level
 	}
 }
 
-// WithPostgresQuery sets the SQL query for PostgreSQL health checks.
-func WithPostgresQuery(query string) DependencyOption {
+// WithCassandraTLS enables TLS for Cassandra connections.
+func WithCassandraTLS(enabled bool) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.PostgresQuery = query
+		dc.CassandraTLS = &enabled
 	}
 }
 
-// WithMySQLQuery sets the SQL query for MySQL health checks.
-func WithMySQLQuery(query string) DependencyOption {
+// WithCassandraTLSSkipVerify disables TLS certificate verification for Cassandra.
+func WithCassandraTLSSkipVerify(skip bool) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.MySQLQuery = query
+		dc.CassandraTLSSkipVerify = &skip
 	}
 }
 
-// WithRedisPassword sets the password for Redis (standalone mode).
-func WithRedisPassword(password string) DependencyOption {
+// WithTLSServerName sets the SNI and the hostname verified against the
+// certificate of a TLS dependency (default: endpoint host).
+func WithTLSServerName(serverName string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.RedisPassword = password
+		dc.TLSServerName = serverName
 	}
 }
 
-// WithRedisDB sets the Redis database number (standalone mode).
-func WithRedisDB(db int) DependencyOption {
+// WithTLSMinVersion sets the minimum protocol version accepted from a TLS
+// dependency: "1.0", "1.1", "1.2" (default) or "1.3".
+func WithTLSMinVersion(version string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.RedisDB = &db
+		dc.TLSMinVersion = version
 	}
 }
 
-// WithAMQPURL sets the full AMQP URL for connections.
-func WithAMQPURL(url string) DependencyOption {
+// WithTLSPinnedCert adds an expected SHA-256 fingerprint of the leaf
+// certificate of a TLS dependency (hex, colons optional). The check fails
+// with detail "tls_pin_mismatch" when none of the pinned fingerprints match.
+// Can be called several times to allow certificate rotation.
+func WithTLSPinnedCert(sha256Fingerprint string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.AMQPURL = url
+		dc.TLSPinnedCerts = append(dc.TLSPinnedCerts, sha256Fingerprint)
 	}
 }
 
-// WithLDAPCheckMethod sets the LDAP check method.
-// Valid values: "anonymous_bind", "simple_bind", "root_dse", "search".
-func WithLDAPCheckMethod(method string) DependencyOption {
+// WithTLSConfig sets the base TLS configuration of the dependency, used by
+// every built-in checker that supports TLS and enabling TLS where it is
+// optional. The checker still sets SNI and its own skip-verify option on a
+// copy; files from WithTLSFiles are loaded on top of it.
+func WithTLSConfig(cfg *tls.Config) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.LDAPCheckMethod = method
+		dc.TLSConfig = cfg
 	}
 }
 
-// WithLDAPBindDN sets the DN for LDAP Simple Bind.
-func WithLDAPBindDN(dn string) DependencyOption {
+// WithTLSFiles sets the PEM CA bundle and the client certificate and key
+// (mTLS) of the dependency for every built-in checker that supports TLS,
+// enabling TLS where it is optional. Any of them may be empty; the
+// certificate and key go together. The files are read again when they
+// change on disk, so rotated certificates are picked up without a restart.
+func WithTLSFiles(caFile, certFile, keyFile string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.LDAPBindDN = dn
+		dc.TLSCAFile = caFile
+		dc.TLSCertFile = certFile
+		dc.TLSKeyFile = keyFile
 	}
 }
 
-// WithLDAPBindPassword sets the password for LDAP Simple Bind.
-func WithLDAPBindPassword(password string) DependencyOption {
+// WithTLSCA sets the PEM CA bundle used to verify the dependency (default:
+// system roots). Same as the CA file of WithTLSFiles.
+func WithTLSCA(caFile string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.LDAPBindPassword = password
+		dc.TLSCAFile = caFile
 	}
 }
 
-// WithLDAPBaseDN sets the base DN for LDAP search method.
-func WithLDAPBaseDN(baseDN string) DependencyOption {
+// WithTLSClientCert sets the client certificate and key presented to the
+// dependency (mTLS). Same as the certificate files of WithTLSFiles.
+func WithTLSClientCert(certFile, keyFile string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.LDAPBaseDN = baseDN
+		dc.TLSCertFile = certFile
+		dc.TLSKeyFile = keyFile
 	}
 }
 
-// WithLDAPSearchFilter sets the LDAP search filter.
-func WithLDAPSearchFilter(filter string) DependencyOption {
+// WithTLSSkipVerify disables chain and hostname verification for every
+// built-in checker that supports TLS; PostgreSQL and MySQL switch to
+// encryption without verification (require). Pinned fingerprints of a TLS
+// dependency are still enforced. Per-type skip-verify options take
+// precedence.
+func WithTLSSkipVerify(skip bool) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.LDAPSearchFilter = filter
+		dc.TLSSkipVerify = &skip
 	}
 }
 
-// WithLDAPSearchScope sets the LDAP search scope.
-// Valid values: "base", "one", "sub".
-func WithLDAPSearchScope(scope string) DependencyOption {
+// WithDNSQuery sets the name and record type resolved by a DNS dependency.
+// Supported record types: A, AAAA, CNAME, SRV, TXT.
+func WithDNSQuery(name, recordType string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.LDAPSearchScope = scope
+		dc.DNSName = name
+		dc.DNSRecordType = recordType
 	}
 }
 
-// WithLDAPStartTLS enables StartTLS for LDAP connections (only with ldap://).
-func WithLDAPStartTLS(enabled bool) DependencyOption {
+// WithDNSExpected sets values that must all be present in the DNS answer:
+// an IP address for A/AAAA, a name for CNAME, "target:port" for SRV and the
+// record text for TXT. Without expected values any non-empty answer passes.
+func WithDNSExpected(values ...string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.LDAPStartTLS = &enabled
+		dc.DNSExpected = append(dc.DNSExpected, values...)
 	}
 }
 
-// WithLDAPTLSSkipVerify disables TLS certificate verification for LDAP.
-func WithLDAPTLSSkipVerify(skip bool) DependencyOption {
+// WithDNSProtocol sets the DNS transport: "udp" (default, falls back to TCP
+// for truncated answers) or "tcp".
+func WithDNSProtocol(protocol string) DependencyOption {
 	return func(dc *DependencyConfig) {
-		dc.LDAPTLSSkipVerify = &skip
+		dc.DNSProtocol = protocol
 	}
 }
 
@@ -395,6 +1352,11 @@ func makeDepOption(name string, depType DependencyType, opts []DependencyOption)
 	return func(c *config) error {
 		dc := applyDepOpts(opts)
 
+		// Standalone database connection settings from env vars.
+		if depType == TypePostgres || depType == TypeMySQL {
+			applyDatabaseEnv(name, depType, dc)
+		}
+
 		// Automatically enable TLS for https:// URLs.
 		if depType == TypeHTTP && dc.URL != "" && strings.HasPrefix(strings.ToLower(dc.URL), "https://") {
 			if dc.HTTPTLS == nil {
@@ -403,7 +1365,30 @@ func makeDepOption(name string, depType DependencyType, opts []DependencyOption)
 			}
 		}
 
+		// Automatically enable TLS for rediss:// URLs.
+		if depType == TypeRedis && dc.URL != "" && strings.HasPrefix(strings.ToLower(dc.URL), "rediss://") {
+			if dc.RedisTLS == nil {
+				enabled := true
+				dc.RedisTLS = &enabled
+			}
+		}
+
+		// Sentinel mode for redis-sentinel:// URLs; the fragment names the master.
+		if depType == TypeRedis && dc.URL != "" && strings.HasPrefix(strings.ToLower(dc.URL), "redis-sentinel://") {
+			if dc.RedisMode == "" {
+				dc.RedisMode = "sentinel"
+			}
+			if dc.RedisMasterName == "" {
+				if u, err := url.Parse(dc.URL); err == nil {
+					dc.RedisMasterName = u.Fragment
+				}
+			}
+		}
+
 		// Validate configuration: auth conflicts and host/authority conflicts.
+		if err := validateSharedTLSConfig(depType, dc); err != nil {
+			return fmt.Errorf("dependency %q: %w", name, err)
+		}
 		if depType == TypeHTTP {
 			if err := validateHTTPAuthConfig(dc); err != nil {
 				return fmt.Errorf("dependency %q: %w", name, err)
@@ -411,6 +1396,9 @@ func makeDepOption(name string, depType DependencyType, opts []DependencyOption)
 			if err := validateHTTPHostHeaderConfig(dc); err != nil {
 				return fmt.Errorf("dependency %q: %w", name, err)
 			}
+			if err := validateHTTPAssertionConfig(dc); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
 		}
 		if depType == TypeGRPC {
 			if err := validateGRPCAuthConfig(dc); err != nil {
@@ -420,11 +1408,66 @@ func makeDepOption(name string, depType DependencyType, opts []DependencyOption)
 				return fmt.Errorf("dependency %q: %w", name, err)
 			}
 		}
+		if depType == TypePostgres {
+			if err := validatePostgresConfig(dc); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
+		if depType == TypeMySQL {
+			if err := validateMySQLConfig(dc); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
+		if depType == TypeRedis {
+			if err := validateRedisConfig(dc); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
+		if depType == TypeAMQP {
+			if err := validateAMQPConfig(dc); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
+		if depType == TypeKafka {
+			if err := validateKafkaConfig(dc); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
 		if depType == TypeLDAP {
 			if err := validateLDAPConfig(dc); err != nil {
 				return fmt.Errorf("dependency %q: %w", name, err)
 			}
 		}
+		if depType == TypeEtcd {
+			if err := (tlsutil.Options{CertFile: dc.EtcdTLSCertFile, KeyFile: dc.EtcdTLSKeyFile}).Validate(); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
+		if depType == TypeConsul {
+			if err := (tlsutil.Options{CertFile: dc.ConsulTLSCertFile, KeyFile: dc.ConsulTLSKeyFile}).Validate(); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
+		if depType == TypeClickHouse {
+			if err := validateClickHouseConfig(dc); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
+		if depType == TypeCassandra {
+			if err := validateCassandraConfig(dc); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
+		if depType == TypeTLS {
+			if err := validateTLSConfig(dc); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
+		if depType == TypeDNS {
+			if err := validateDNSConfig(dc); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
 
 		dep, err := buildDependency(name, depType, dc, c)
 		if err != nil {
@@ -491,6 +1534,37 @@ func LDAP(name string, opts ...DependencyOption) Option {
 	return makeDepOption(name, TypeLDAP, opts)
 }
 
+// Etcd registers an etcd dependency.
+func Etcd(name string, opts ...DependencyOption) Option {
+	return makeDepOption(name, TypeEtcd, opts)
+}
+
+// Consul registers a Consul dependency.
+func Consul(name string, opts ...DependencyOption) Option {
+	return makeDepOption(name, TypeConsul, opts)
+}
+
+// ClickHouse registers a ClickHouse dependency.
+func ClickHouse(name string, opts ...DependencyOption) Option {
+	return makeDepOption(name, TypeClickHouse, opts)
+}
+
+// Cassandra registers a Cassandra dependency.
+func Cassandra(name string, opts ...DependencyOption) Option {
+	return makeDepOption(name, TypeCassandra, opts)
+}
+
+// TLS registers a TLS-only dependency checked by a TLS handshake.
+func TLS(name string, opts ...DependencyOption) Option {
+	return makeDepOption(name, TypeTLS, opts)
+}
+
+// DNS registers a DNS resolver dependency. The endpoint is the resolver
+// address; the resolved name is set with WithDNSQuery.
+func DNS(name string, opts ...DependencyOption) Option {
+	return makeDepOption(name, TypeDNS, opts)
+}
+
 // --- Contrib helper ---
 
 // AddDependency creates an Option for registering an arbitrary dependency.
@@ -498,6 +1572,26 @@ func LDAP(name string, opts ...DependencyOption) Option {
 func AddDependency(name string, depType DependencyType, checker HealthChecker, opts ...DependencyOption) Option {
 	return func(c *config) error {
 		dc := applyDepOpts(opts)
+
+		// Pool-mode integrations (sqldb.FromDB, sqldb.FromMySQLDB,
+		// cqlsession.FromSession) honour these options, so they are
+		// validated as in standalone mode.
+		if depType == TypePostgres {
+			if err := validateReplicationConfig(dc.PostgresRole, dc.PostgresMaxReplicationLag); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
+		if depType == TypeMySQL {
+			if err := validateReplicationConfig(dc.MySQLRole, dc.MySQLMaxReplicationLag); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
+		if depType == TypeCassandra {
+			if err := validateCassandraConfig(dc); err != nil {
+				return fmt.Errorf("dependency %q: %w", name, err)
+			}
+		}
+
 		dep, err := buildDependency(name, depType, dc, c)
 		if err != nil {
 			return err
@@ -528,6 +1622,38 @@ func envDepName(name string) string {
 	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
 }
 
+// applyDatabaseEnv fills unset standalone connection settings of a
+// PostgreSQL or MySQL dependency from DEPHEALTH_<DEP>_USER, _PASSWORD,
+// _DATABASE, _SSLMODE, _TLS_CA and _APPLICATION_NAME. API options win.
+func applyDatabaseEnv(name string, depType DependencyType, dc *DependencyConfig) {
+	prefix := "DEPHEALTH_" + envDepName(name) + "_"
+	var fields map[string]*string
+	if depType == TypePostgres {
+		fields = map[string]*string{
+			"USER":             &dc.PostgresUser,
+			"PASSWORD":         &dc.PostgresPassword,
+			"DATABASE":         &dc.PostgresDatabase,
+			"SSLMODE":          &dc.PostgresSSLMode,
+			"TLS_CA":           &dc.PostgresTLSCAFile,
+			"APPLICATION_NAME": &dc.PostgresApplicationName,
+		}
+	} else {
+		fields = map[string]*string{
+			"USER":             &dc.MySQLUser,
+			"PASSWORD":         &dc.MySQLPassword,
+			"DATABASE":         &dc.MySQLDatabase,
+			"SSLMODE":          &dc.MySQLSSLMode,
+			"TLS_CA":           &dc.MySQLTLSCAFile,
+			"APPLICATION_NAME": &dc.MySQLApplicationName,
+		}
+	}
+	for key, field := range fields {
+		if *field == "" {
+			*field = os.Getenv(prefix + key)
+		}
+	}
+}
+
 // buildDependency assembles a Dependency from DependencyConfig and global config.
 func buildDependency(name string, depType DependencyType, dc *DependencyConfig, c *config) (Dependency, error) {
 	var endpoints []Endpoint
@@ -628,6 +1754,22 @@ func buildDependency(name string, depType DependencyType, dc *DependencyConfig,
 		timeout = dc.Timeout
 	}
 
+	// Determine TLS expiry warning: per-dependency > global.
+	tlsWarning := c.tlsWarning
+	if dc.TLSExpiryWarning != 0 {
+		tlsWarning = dc.TLSExpiryWarning
+	}
+	if tlsWarning < 0 {
+		return Dependency{}, fmt.Errorf("dependency %q: TLS expiry warning %s must not be negative", name, tlsWarning)
+	}
+
+	if dc.LatencySLO < 0 {
+		return Dependency{}, fmt.Errorf("dependency %q: latency SLO %s must not be negative", name, dc.LatencySLO)
+	}
+	if dc.LatencySLO > 0 && dc.LatencySLO >= timeout {
+		return Dependency{}, fmt.Errorf("dependency %q: latency SLO %s must be less than timeout %s", name, dc.LatencySLO, timeout)
+	}
+
 	dep := Dependency{
 		Name:      name,
 		Type:      depType,
@@ -639,6 +1781,9 @@ func buildDependency(name string, depType DependencyType, dc *DependencyConfig,
 			InitialDelay:     0,
 			FailureThreshold: DefaultFailureThreshold,
 			SuccessThreshold: DefaultSuccessThreshold,
+			TLSExpiryWarning: tlsWarning,
+			LatencySLO:       dc.LatencySLO,
+			ErrorClassifiers: dc.ErrorClassifiers,
 		},
 	}
 
@@ -658,13 +1803,57 @@ func validateHTTPHostHeaderConfig(dc *DependencyConfig) error {
 	return nil
 }
 
+// validateHTTPAssertionConfig checks the request method, status codes,
+// limits, body assertions, response format and topology depth.
+func validateHTTPAssertionConfig(dc *DependencyConfig) error {
+	if strings.ContainsAny(dc.HTTPMethod, " \t\r\n") {
+		return fmt.Errorf("invalid HTTP method %q", dc.HTTPMethod)
+	}
+	for _, code := range dc.HTTPExpectedStatus {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid expected HTTP status %d: must be 100-599", code)
+		}
+	}
+	if dc.HTTPMaxRedirects != nil && *dc.HTTPMaxRedirects < 0 {
+		return fmt.Errorf("HTTP max redirects must not be negative")
+	}
+	if dc.HTTPMaxBodySize < 0 {
+		return fmt.Errorf("HTTP max body size must not be negative")
+	}
+	if dc.HTTPBodyRegex != "" {
+		if _, err := regexp.Compile(dc.HTTPBodyRegex); err != nil {
+			return fmt.Errorf("invalid HTTP body regex: %w", err)
+		}
+	}
+	if dc.HTTPBodyJSONPath != "" {
+		if _, err := jsonpath.Parse(dc.HTTPBodyJSONPath); err != nil {
+			return err
+		}
+	}
+	switch dc.HTTPResponseFormat {
+	case "", "auto", "spring", "health+json", "dephealth":
+	default:
+		return fmt.Errorf("invalid HTTP response format %q: must be one of auto, spring, health+json, dephealth", dc.HTTPResponseFormat)
+	}
+	if dc.HTTPTopologyDepth < 0 {
+		return fmt.Errorf("HTTP topology depth must not be negative")
+	}
+	if dc.HTTPTopologyDepth > 0 && (dc.HTTPResponseFormat == "spring" || dc.HTTPResponseFormat == "health+json") {
+		return fmt.Errorf("HTTP topology depth requires the dephealth or auto response format, got %q", dc.HTTPResponseFormat)
+	}
+	return nil
+}
+
 // validateHTTPAuthConfig checks that at most one HTTP auth method is configured.
 func validateHTTPAuthConfig(dc *DependencyConfig) error {
 	methods := 0
 	if dc.HTTPBearerToken != "" {
 		methods++
 	}
-	if dc.HTTPBasicUser != "" {
+	if dc.HTTPBearerTokenProvider != nil {
+		methods++
+	}
+	if dc.HTTPBasicUser != "" || dc.HTTPBasicPassProvider != nil {
 		methods++
 	}
 	for k := range dc.HTTPHeaders {
@@ -679,6 +1868,103 @@ func validateHTTPAuthConfig(dc *DependencyConfig) error {
 	return nil
 }
 
+// validatePostgresConfig checks the sslmode, role and replication lag.
+func validatePostgresConfig(dc *DependencyConfig) error {
+	switch dc.PostgresSSLMode {
+	case "", "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+		// valid
+	default:
+		return fmt.Errorf("invalid postgres sslmode %q: must be one of disable, allow, prefer, require, verify-ca, verify-full", dc.PostgresSSLMode)
+	}
+	return validateReplicationConfig(dc.PostgresRole, dc.PostgresMaxReplicationLag)
+}
+
+// validateMySQLConfig checks the SSL mode, role and replication lag.
+func validateMySQLConfig(dc *DependencyConfig) error {
+	switch strings.ReplaceAll(strings.ToLower(dc.MySQLSSLMode), "-", "_") {
+	case "", "disabled", "disable", "preferred", "prefer", "required", "require",
+		"verify_ca", "verify_identity", "verify_full":
+		// valid
+	default:
+		return fmt.Errorf("invalid mysql SSL mode %q: must be one of disabled, preferred, required, verify_ca, verify_identity", dc.MySQLSSLMode)
+	}
+	return validateReplicationConfig(dc.MySQLRole, dc.MySQLMaxReplicationLag)
+}
+
+// validateReplicationConfig checks the expected database role and the
+// replication lag threshold.
+func validateReplicationConfig(role string, maxLag time.Duration) error {
+	switch role {
+	case "", "primary", "replica":
+		// valid
+	default:
+		return fmt.Errorf("invalid role %q: must be primary or replica", role)
+	}
+	if maxLag < 0 {
+		return fmt.Errorf("max replication lag must not be negative")
+	}
+	return nil
+}
+
+// validateRedisConfig checks the Redis mode, Sentinel master name and TLS client certificate.
+func validateRedisConfig(dc *DependencyConfig) error {
+	switch dc.RedisMode {
+	case "", "standalone", "cluster":
+		// valid
+	case "sentinel":
+		if dc.RedisMasterName == "" {
+			return fmt.Errorf("redis sentinel master name is required: use WithRedisSentinel or a redis-sentinel:// URL fragment")
+		}
+	default:
+		return fmt.Errorf("invalid redis mode %q: must be one of standalone, sentinel, cluster", dc.RedisMode)
+	}
+	return tlsutil.Options{CertFile: dc.RedisTLSCertFile, KeyFile: dc.RedisTLSKeyFile}.Validate()
+}
+
+// validateAMQPConfig checks the queue thresholds and TLS client certificate.
+func validateAMQPConfig(dc *DependencyConfig) error {
+	if dc.AMQPMaxQueueDepth < 0 || dc.AMQPMinConsumers < 0 {
+		return fmt.Errorf("amqp queue thresholds must not be negative")
+	}
+	if (dc.AMQPMaxQueueDepth > 0 || dc.AMQPMinConsumers > 0) && len(dc.AMQPQueues) == 0 {
+		return fmt.Errorf("amqp queue thresholds require queues: use WithAMQPQueues")
+	}
+	for _, q := range dc.AMQPQueues {
+		if q == "" {
+			return fmt.Errorf("amqp queue name must not be empty")
+		}
+	}
+	return tlsutil.Options{CertFile: dc.AMQPTLSCertFile, KeyFile: dc.AMQPTLSKeyFile}.Validate()
+}
+
+// validateKafkaConfig checks the SASL mechanism, topic and consumer group
+// assertions and TLS client certificate.
+func validateKafkaConfig(dc *DependencyConfig) error {
+	switch strings.ToUpper(dc.KafkaSASLMechanism) {
+	case "":
+		// SASL disabled
+	case "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512":
+		if dc.KafkaUsername == "" {
+			return fmt.Errorf("kafka SASL requires a username")
+		}
+	default:
+		return fmt.Errorf("invalid kafka SASL mechanism %q: must be one of PLAIN, SCRAM-SHA-256, SCRAM-SHA-512", dc.KafkaSASLMechanism)
+	}
+	if dc.KafkaMinISR < 0 {
+		return fmt.Errorf("kafka min ISR must not be negative")
+	}
+	if dc.KafkaMinISR > 0 && dc.KafkaTopic == "" {
+		return fmt.Errorf("kafka min ISR requires a topic: use WithKafkaTopic")
+	}
+	if dc.KafkaMaxLag < 0 {
+		return fmt.Errorf("kafka max lag must not be negative")
+	}
+	if dc.KafkaMaxLag > 0 && dc.KafkaConsumerGroup == "" {
+		return fmt.Errorf("kafka max lag requires a consumer group")
+	}
+	return tlsutil.Options{CertFile: dc.KafkaTLSCertFile, KeyFile: dc.KafkaTLSKeyFile}.Validate()
+}
+
 // validateLDAPConfig checks LDAP-specific configuration rules.
 func validateLDAPConfig(dc *DependencyConfig) error {
 	method := dc.LDAPCheckMethod
@@ -693,7 +1979,7 @@ func validateLDAPConfig(dc *DependencyConfig) error {
 		return fmt.Errorf("invalid LDAP check method %q: must be one of anonymous_bind, simple_bind, root_dse, search", method)
 	}
 
-	if method == "simple_bind" && (dc.LDAPBindDN == "" || dc.LDAPBindPassword == "") {
+	if method == "simple_bind" && (dc.LDAPBindDN == "" || (dc.LDAPBindPassword == "" && dc.LDAPBindPasswordProvider == nil)) {
 		return fmt.Errorf("simple_bind requires both bindDN and bindPassword")
 	}
 
@@ -720,6 +2006,81 @@ func validateLDAPConfig(dc *DependencyConfig) error {
 	return nil
 }
 
+// validateClickHouseConfig validates ClickHouse-specific configuration.
+func validateClickHouseConfig(dc *DependencyConfig) error {
+	switch dc.ClickHouseProtocol {
+	case "", "native", "http":
+		// valid
+	default:
+		return fmt.Errorf("invalid clickhouse protocol %q: must be one of native, http", dc.ClickHouseProtocol)
+	}
+	if dc.ClickHouseMaxReplicaDelay < 0 {
+		return fmt.Errorf("clickhouse max replica delay must not be negative")
+	}
+	return nil
+}
+
+// validateCassandraConfig validates Cassandra-specific configuration.
+func validateCassandraConfig(dc *DependencyConfig) error {
+	switch strings.ToUpper(dc.CassandraConsistency) {
+	case "", "ANY", "ONE", "TWO", "THREE", "QUORUM", "ALL", "LOCAL_QUORUM", "EACH_QUORUM", "LOCAL_ONE":
+		// valid
+	default:
+		return fmt.Errorf("invalid Cassandra consistency %q", dc.CassandraConsistency)
+	}
+	return nil
+}
+
+// validateTLSConfig validates configuration of the standalone TLS checker.
+func validateTLSConfig(dc *DependencyConfig) error {
+	switch dc.TLSMinVersion {
+	case "", "1.0", "1.1", "1.2", "1.3":
+		// valid
+	default:
+		return fmt.Errorf("invalid TLS min version %q: must be one of 1.0, 1.1, 1.2, 1.3", dc.TLSMinVersion)
+	}
+	for _, pin := range dc.TLSPinnedCerts {
+		fp := strings.ReplaceAll(strings.TrimPrefix(strings.ToLower(pin), "sha256:"), ":", "")
+		if b, err := hex.DecodeString(fp); err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("invalid TLS pinned certificate %q: expected a hex SHA-256 fingerprint", pin)
+		}
+	}
+	return nil
+}
+
+// validateDNSConfig validates DNS-specific configuration.
+func validateDNSConfig(dc *DependencyConfig) error {
+	if dc.DNSName == "" {
+		return fmt.Errorf("DNS name is required: use WithDNSQuery")
+	}
+	switch strings.ToUpper(dc.DNSRecordType) {
+	case "", "A", "AAAA", "CNAME", "SRV", "TXT":
+		// valid
+	default:
+		return fmt.Errorf("invalid DNS record type %q: must be one of A, AAAA, CNAME, SRV, TXT", dc.DNSRecordType)
+	}
+	switch strings.ToLower(dc.DNSProtocol) {
+	case "", "udp", "tcp":
+		// valid
+	default:
+		return fmt.Errorf("invalid DNS protocol %q: must be one of udp, tcp", dc.DNSProtocol)
+	}
+	return nil
+}
+
+// validateSharedTLSConfig rejects the shared TLS options (WithTLSConfig,
+// WithTLSFiles, WithTLSCA, WithTLSClientCert, WithTLSSkipVerify) for
+// dependency types without TLS, where they would be silently ignored, and
+// checks that the client certificate and key are set together.
+func validateSharedTLSConfig(depType DependencyType, dc *DependencyConfig) error {
+	if depType == TypeTCP || depType == TypeDNS {
+		if dc.TLSConfig != nil || dc.TLSCAFile != "" || dc.TLSCertFile != "" || dc.TLSKeyFile != "" || dc.TLSSkipVerify != nil {
+			return fmt.Errorf("TLS options are not supported for %s dependencies", depType)
+		}
+	}
+	return tlsutil.Options{CertFile: dc.TLSCertFile, KeyFile: dc.TLSKeyFile}.Validate()
+}
+
 // validateGRPCAuthorityConfig checks that grpcAuthority does not conflict with :authority in metadata.
 func validateGRPCAuthorityConfig(dc *DependencyConfig) error {
 	if dc.GRPCAuthority == "" {
@@ -739,7 +2100,10 @@ func validateGRPCAuthConfig(dc *DependencyConfig) error {
 	if dc.GRPCBearerToken != "" {
 		methods++
 	}
-	if dc.GRPCBasicUser != "" {
+	if dc.GRPCBearerTokenProvider != nil {
+		methods++
+	}
+	if dc.GRPCBasicUser != "" || dc.GRPCBasicPassProvider != nil {
 		methods++
 	}
 	for k := range dc.GRPCMetadata {
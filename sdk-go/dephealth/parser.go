@@ -10,36 +10,50 @@ import (
 
 // Default ports per dependency type (from spec/config-contract.md).
 var DefaultPorts = map[string]string{
-	"postgres":   "5432",
-	"postgresql": "5432",
-	"mysql":      "3306",
-	"redis":      "6379",
-	"rediss":     "6379",
-	"amqp":       "5672",
-	"amqps":      "5671",
-	"http":       "80",
-	"https":      "443",
-	"grpc":       "443",
-	"kafka":      "9092",
-	"ldap":       "389",
-	"ldaps":      "636",
+	"postgres":       "5432",
+	"postgresql":     "5432",
+	"mysql":          "3306",
+	"redis":          "6379",
+	"rediss":         "6379",
+	"redis-sentinel": "26379",
+	"amqp":           "5672",
+	"amqps":          "5671",
+	"http":           "80",
+	"https":          "443",
+	"grpc":           "443",
+	"kafka":          "9092",
+	"ldap":           "389",
+	"ldaps":          "636",
+	"etcd":           "2379",
+	"consul":         "8500",
+	"clickhouse":     "9000",
+	"cassandra":      "9042",
+	"tls":            "443",
+	"dns":            "53",
 }
 
 // schemeToType maps URL schemes to DependencyType.
 var schemeToType = map[string]DependencyType{
-	"postgres":   TypePostgres,
-	"postgresql": TypePostgres,
-	"mysql":      TypeMySQL,
-	"redis":      TypeRedis,
-	"rediss":     TypeRedis,
-	"amqp":       TypeAMQP,
-	"amqps":      TypeAMQP,
-	"http":       TypeHTTP,
-	"https":      TypeHTTP,
-	"grpc":       TypeGRPC,
-	"kafka":      TypeKafka,
-	"ldap":       TypeLDAP,
-	"ldaps":      TypeLDAP,
+	"postgres":       TypePostgres,
+	"postgresql":     TypePostgres,
+	"mysql":          TypeMySQL,
+	"redis":          TypeRedis,
+	"rediss":         TypeRedis,
+	"redis-sentinel": TypeRedis,
+	"amqp":           TypeAMQP,
+	"amqps":          TypeAMQP,
+	"http":           TypeHTTP,
+	"https":          TypeHTTP,
+	"grpc":           TypeGRPC,
+	"kafka":          TypeKafka,
+	"ldap":           TypeLDAP,
+	"ldaps":          TypeLDAP,
+	"etcd":           TypeEtcd,
+	"consul":         TypeConsul,
+	"clickhouse":     TypeClickHouse,
+	"cassandra":      TypeCassandra,
+	"tls":            TypeTLS,
+	"dns":            TypeDNS,
 }
 
 // jdbcSubprotocolToType maps JDBC subprotocols to DependencyType.
@@ -57,7 +71,8 @@ type ParsedConnection struct {
 
 // ParseURL parses a full URL and extracts host, port, and connection type.
 // Supports schemes: postgres://, postgresql://, mysql://, redis://, rediss://,
-// amqp://, amqps://, http://, https://, grpc://, kafka://.
+// redis-sentinel://, amqp://, amqps://, http://, https://, grpc://, kafka://, ldap://, ldaps://,
+// etcd://, consul://, clickhouse://, cassandra://, tls://, dns://.
 //
 // For URLs with multiple hosts (e.g. kafka://broker-0:9092,broker-1:9092),
 // returns multiple ParsedConnection entries.
@@ -69,12 +84,12 @@ func ParseURL(rawURL string) ([]ParsedConnection, error) {
 	// Handle kafka:// multi-host URLs: kafka://host1:port1,host2:port2
 	u, err := url.Parse(rawURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid URL %q: %w", rawURL, err)
+		return nil, fmt.Errorf("invalid URL %q: %w", Redact(rawURL), RedactError(err))
 	}
 
 	scheme := strings.ToLower(u.Scheme)
 	if scheme == "" {
-		return nil, fmt.Errorf("missing scheme in URL %q", rawURL)
+		return nil, fmt.Errorf("missing scheme in URL %q", Redact(rawURL))
 	}
 
 	connType, ok := schemeToType[scheme]
@@ -92,7 +107,7 @@ func ParseURL(rawURL string) ([]ParsedConnection, error) {
 
 	host, port, err := extractHostPort(hostPart, defaultPort)
 	if err != nil {
-		return nil, fmt.Errorf("invalid URL %q: %w", rawURL, err)
+		return nil, fmt.Errorf("invalid URL %q: %w", Redact(rawURL), RedactError(err))
 	}
 
 	return []ParsedConnection{{Host: host, Port: port, ConnType: connType}}, nil
@@ -239,7 +254,7 @@ func ParseJDBC(jdbcURL string) ([]ParsedConnection, error) {
 	}
 
 	if !strings.HasPrefix(strings.ToLower(jdbcURL), "jdbc:") {
-		return nil, fmt.Errorf("invalid JDBC URL %q: missing jdbc: prefix", jdbcURL)
+		return nil, fmt.Errorf("invalid JDBC URL %q: missing jdbc: prefix", Redact(jdbcURL))
 	}
 
 	// Remove "jdbc:" prefix and parse as regular URL
@@ -247,7 +262,7 @@ func ParseJDBC(jdbcURL string) ([]ParsedConnection, error) {
 
 	u, err := url.Parse(innerURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid JDBC URL %q: %w", jdbcURL, err)
+		return nil, fmt.Errorf("invalid JDBC URL %q: %w", Redact(jdbcURL), RedactError(err))
 	}
 
 	subprotocol := strings.ToLower(u.Scheme)
@@ -259,7 +274,7 @@ func ParseJDBC(jdbcURL string) ([]ParsedConnection, error) {
 	defaultPort := DefaultPorts[subprotocol]
 	host, port, err := extractHostPort(u.Host, defaultPort)
 	if err != nil {
-		return nil, fmt.Errorf("invalid JDBC URL %q: %w", jdbcURL, err)
+		return nil, fmt.Errorf("invalid JDBC URL %q: %w", Redact(jdbcURL), RedactError(err))
 	}
 
 	return []ParsedConnection{{Host: host, Port: port, ConnType: connType}}, nil
@@ -0,0 +1,230 @@
+package dephealth
+
+import (
+	"context"
+	"crypto/tls"
+	"maps"
+	"sync"
+	"time"
+)
+
+// checkReport collects optional observations made by a checker during a
+// single Check call (e.g. the peer certificate expiry). The scheduler attaches
+// an empty report to the check context and reads it after Check returns.
+type checkReport struct {
+	mu            sync.Mutex
+	tlsCertExpiry time.Time
+	consumerLag   int64
+	hasLag        bool
+	replLag       time.Duration
+	hasReplLag    bool
+	connect       time.Duration
+	hasConnect    bool
+	detail        string
+	components    map[string]ComponentStatus
+	dependencies  map[string]EndpointStatus
+}
+
+type checkReportKey struct{}
+
+// withCheckReport returns a child context carrying a new, empty checkReport.
+func withCheckReport(ctx context.Context) (context.Context, *checkReport) {
+	r := &checkReport{}
+	return context.WithValue(ctx, checkReportKey{}, r), r
+}
+
+// reportFromContext returns the checkReport attached to ctx, or nil.
+func reportFromContext(ctx context.Context) *checkReport {
+	r, _ := ctx.Value(checkReportKey{}).(*checkReport)
+	return r
+}
+
+// ReportTLSCertExpiry records the NotAfter time of the dependency's leaf
+// certificate for the current check. Checkers call it after a successful TLS
+// handshake. It is a no-op when ctx was not created by the scheduler, so
+// checkers can call it unconditionally.
+func ReportTLSCertExpiry(ctx context.Context, notAfter time.Time) {
+	r := reportFromContext(ctx)
+	if r == nil || notAfter.IsZero() {
+		return
+	}
+	r.mu.Lock()
+	r.tlsCertExpiry = notAfter
+	r.mu.Unlock()
+}
+
+// ReportTLSConnectionState records the leaf certificate expiry from a
+// completed TLS handshake. A nil state or a state without peer certificates
+// is ignored.
+func ReportTLSConnectionState(ctx context.Context, state *tls.ConnectionState) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return
+	}
+	ReportTLSCertExpiry(ctx, state.PeerCertificates[0].NotAfter)
+}
+
+// ReportConsumerLag records the total lag, in messages, of the consumer
+// group observed by the current check. It is a no-op when ctx was not created
+// by the scheduler.
+func ReportConsumerLag(ctx context.Context, lag int64) {
+	r := reportFromContext(ctx)
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.consumerLag = lag
+	r.hasLag = true
+	r.mu.Unlock()
+}
+
+// lag returns the reported consumer lag and whether one was reported.
+func (r *checkReport) lag() (int64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.consumerLag, r.hasLag
+}
+
+// ReportReplicationLag records the replication lag of a database replica
+// observed by the current check. A check that does not report a lag (not a
+// replica, replication stopped, check failed) removes the
+// app_dependency_replication_lag_seconds series. It is a no-op when ctx was
+// not created by the scheduler.
+func ReportReplicationLag(ctx context.Context, lag time.Duration) {
+	r := reportFromContext(ctx)
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.replLag = lag
+	r.hasReplLag = true
+	r.mu.Unlock()
+}
+
+// replicationLag returns the reported replication lag and whether one was
+// reported.
+func (r *checkReport) replicationLag() (time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.replLag, r.hasReplLag
+}
+
+// ReportConnectTime records the time spent establishing a new connection
+// (dial and handshakes) during the current check. Checkers that reuse
+// connections call it only when a connection was actually established, so
+// cold connects can be told apart from the check latency. It is a no-op when
+// ctx was not created by the scheduler.
+func ReportConnectTime(ctx context.Context, d time.Duration) {
+	r := reportFromContext(ctx)
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.connect = d
+	r.hasConnect = true
+	r.mu.Unlock()
+}
+
+// connectTime returns the reported connect time and whether one was reported.
+func (r *checkReport) connectTime() (time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.connect, r.hasConnect
+}
+
+// certExpiry returns the reported certificate expiry (zero if none).
+func (r *checkReport) certExpiry() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.tlsCertExpiry
+}
+
+// ReportDetail sets the detail of the current check when it succeeds; the
+// status stays "ok". Checkers use it for healthy-but-degraded conditions
+// such as a downstream health response with status WARN. A failed check
+// keeps the detail of its error. It is a no-op when ctx was not created by
+// the scheduler.
+func ReportDetail(ctx context.Context, detail string) {
+	r := reportFromContext(ctx)
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.detail = Redact(detail)
+	r.mu.Unlock()
+}
+
+// successDetail returns the reported detail for a successful check.
+func (r *checkReport) successDetail() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.detail
+}
+
+// ReportComponents records the sub-components reported by the dependency's
+// own health response. They are exposed in EndpointStatus.Components until
+// the next check; component details are redacted. It is a no-op when ctx was
+// not created by the scheduler.
+func ReportComponents(ctx context.Context, components map[string]ComponentStatus) {
+	r := reportFromContext(ctx)
+	if r == nil {
+		return
+	}
+	clean := redactComponents(components)
+	r.mu.Lock()
+	r.components = clean
+	r.mu.Unlock()
+}
+
+// redactComponents returns a copy of components with redacted details.
+func redactComponents(components map[string]ComponentStatus) map[string]ComponentStatus {
+	clean := maps.Clone(components)
+	for name, c := range clean {
+		c.Detail = Redact(c.Detail)
+		clean[name] = c
+	}
+	return clean
+}
+
+// reportedComponents returns the reported sub-components (nil if none).
+func (r *checkReport) reportedComponents() map[string]ComponentStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.components
+}
+
+// ReportDependencies records the transitive edges of the dependency, i.e.
+// the HealthDetails reported by a downstream dephealth service. They are
+// exposed in EndpointStatus.Dependencies until the next check; errors and
+// details of every edge, nested ones included, are redacted. It is a no-op
+// when ctx was not created by the scheduler.
+func ReportDependencies(ctx context.Context, deps map[string]EndpointStatus) {
+	r := reportFromContext(ctx)
+	if r == nil {
+		return
+	}
+	clean := redactDependencies(deps)
+	r.mu.Lock()
+	r.dependencies = clean
+	r.mu.Unlock()
+}
+
+// redactDependencies returns a copy of deps with the errors and details of
+// every edge redacted, including nested components and transitive edges.
+func redactDependencies(deps map[string]EndpointStatus) map[string]EndpointStatus {
+	clean := maps.Clone(deps)
+	for key, st := range clean {
+		st.Error = Redact(st.Error)
+		st.Detail = Redact(st.Detail)
+		st.Components = redactComponents(st.Components)
+		st.Dependencies = redactDependencies(st.Dependencies)
+		clean[key] = st
+	}
+	return clean
+}
+
+// reportedDependencies returns the reported transitive edges (nil if none).
+func (r *checkReport) reportedDependencies() map[string]EndpointStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dependencies
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
+	"reflect"
 	"sync"
 	"time"
 )
@@ -25,11 +28,23 @@ type endpointState struct {
 	consecutiveFailures  int
 	consecutiveSuccesses int
 
+	// Latency SLO state: successful checks in a row above and within the
+	// SLO, and whether the endpoint is degraded.
+	consecutiveSlow int
+	consecutiveFast int
+	degraded        bool
+
 	// Fields for HealthDetails() API.
 	lastStatus    StatusCategory
 	lastDetail    string
 	lastLatency   time.Duration
 	lastCheckedAt time.Time
+	lastError     string
+	lastSuccessAt time.Time
+	lastFailureAt time.Time
+	tlsCertExpiry time.Time
+	components    map[string]ComponentStatus
+	dependencies  map[string]EndpointStatus
 
 	// Static fields set at state creation time.
 	depName  string
@@ -38,9 +53,23 @@ type endpointState struct {
 	port     string
 	critical bool
 	labels   map[string]string
+	checker  HealthChecker
+	config   CheckConfig // reused when the endpoint is replaced
 
 	// Per-endpoint cancel function for dynamic removal.
 	cancel context.CancelFunc
+
+	// Lifecycle of the endpoint's checker; nil when it has no Init or Close.
+	lifecycle *checkerLifecycle
+}
+
+// checkerLifecycle tracks a checker instance that implements Initializer or
+// io.Closer across the endpoint goroutines sharing it.
+type checkerLifecycle struct {
+	refs int // guarded by Scheduler.mu
+
+	mu          sync.Mutex
+	initialized bool
 }
 
 // Scheduler manages periodic execution of health checks.
@@ -50,13 +79,14 @@ type Scheduler struct {
 	logger       *slog.Logger
 	globalConfig CheckConfig
 
-	states  map[string]*endpointState // key: "name:host:port"
-	ctx     context.Context
-	cancel  context.CancelFunc
-	wg      sync.WaitGroup
-	started bool
-	stopped bool
-	mu      sync.Mutex
+	states   map[string]*endpointState // key: "name:host:port"
+	checkers map[HealthChecker]*checkerLifecycle
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	started  bool
+	stopped  bool
+	mu       sync.Mutex
 }
 
 // scheduledDep contains a dependency with its associated checker.
@@ -73,7 +103,9 @@ type schedulerConfig struct {
 	globalConfig *CheckConfig
 }
 
-// WithSchedulerLogger sets the logger for the scheduler.
+// WithSchedulerLogger sets the logger for the scheduler. Secrets in log
+// messages and attributes are redacted (see Redact) before they reach the
+// logger's handler.
 func WithSchedulerLogger(l *slog.Logger) SchedulerOption {
 	return func(c *schedulerConfig) {
 		c.logger = l
@@ -104,7 +136,7 @@ func NewScheduler(metrics *MetricsExporter, opts ...SchedulerOption) *Scheduler
 
 	return &Scheduler{
 		metrics:      metrics,
-		logger:       cfg.logger,
+		logger:       newRedactLogger(cfg.logger),
 		globalConfig: globalCfg,
 	}
 }
@@ -123,6 +155,7 @@ func (s *Scheduler) Start(ctx context.Context) error {
 
 	ctx, s.cancel = context.WithCancel(ctx)
 	s.ctx = ctx
+	s.checkers = make(map[HealthChecker]*checkerLifecycle)
 
 	// Launch a check goroutine per endpoint, keyed as "name:host:port".
 	s.states = make(map[string]*endpointState)
@@ -144,7 +177,10 @@ func (s *Scheduler) Start(ctx context.Context) error {
 				port:       ep.Port,
 				critical:   critical,
 				labels:     labels,
+				checker:    sd.checker,
+				config:     sd.dep.Config,
 				cancel:     epCancel,
+				lifecycle:  s.acquireChecker(sd.checker),
 			}
 			s.states[key] = st
 			s.wg.Add(1)
@@ -220,6 +256,15 @@ func (s *Scheduler) HealthDetails() map[string]EndpointStatus {
 			Critical:      st.critical,
 			LastCheckedAt: st.lastCheckedAt,
 			Labels:        copyStringMap(st.labels),
+			TLSCertExpiry: st.tlsCertExpiry,
+			Components:    maps.Clone(st.components),
+			Dependencies:  maps.Clone(st.dependencies),
+
+			Error:                st.lastError,
+			ConsecutiveFailures:  st.consecutiveFailures,
+			ConsecutiveSuccesses: st.consecutiveSuccesses,
+			LastSuccessAt:        st.lastSuccessAt,
+			LastFailureAt:        st.lastFailureAt,
 		}
 		st.mu.Unlock()
 		result[key] = es
@@ -251,6 +296,10 @@ func copyStringMap(m map[string]string) map[string]string {
 // runEndpointLoop is the main check loop for a single endpoint.
 func (s *Scheduler) runEndpointLoop(ctx context.Context, dep Dependency, ep Endpoint, checker HealthChecker, state *endpointState) {
 	defer s.wg.Done()
+	if state.lifecycle != nil {
+		defer s.releaseChecker(checker, state.lifecycle)
+	}
+	defer s.closeEndpoint(checker, ep, state)
 
 	logAttrs := []slog.Attr{
 		slog.String("dependency", dep.Name),
@@ -303,27 +352,85 @@ func (s *Scheduler) executeCheck(
 	// Create a context with timeout for the check.
 	checkCtx, checkCancel := context.WithTimeout(ctx, dep.Config.Timeout)
 	defer checkCancel()
+	checkCtx, report := withCheckReport(checkCtx)
 
 	start := time.Now()
-	checkErr := s.safeCheck(checkCtx, checker, ep)
+	checkErr := s.safeCheck(checkCtx, checker, ep, state.lifecycle)
 	duration := time.Since(start)
 
 	// Record latency always (both on success and failure).
 	s.metrics.ObserveLatency(dep, ep, duration)
+	if connect, ok := report.connectTime(); ok {
+		s.metrics.ObserveConnectLatency(dep, ep, connect)
+	}
 
 	// Classify the check result for status metrics.
-	result := classifyError(checkErr)
-	s.metrics.SetStatus(dep, ep, result.Category)
-	s.metrics.SetStatusDetail(dep, ep, result.Detail)
+	result := s.safeClassify(checkErr, dep, ep)
+	if detail := report.successDetail(); checkErr == nil && detail != "" {
+		result.Detail = detail
+	}
+
+	// A healthy TLS dependency whose certificate is about to expire keeps
+	// status "ok" but reports detail "tls_expiring".
+	certExpiry := report.certExpiry()
+	if !certExpiry.IsZero() {
+		s.metrics.SetTLSCertExpiry(dep, ep, certExpiry)
+		if checkErr == nil && dep.Config.TLSExpiryWarning > 0 &&
+			time.Until(certExpiry) < dep.Config.TLSExpiryWarning {
+			result.Detail = "tls_expiring"
+		}
+	}
+
+	// A check that did not measure the lag (group missing, offset fetch
+	// failed, broker down, not a replica) drops the series instead of
+	// keeping a stale value.
+	if lag, ok := report.lag(); ok {
+		s.metrics.SetConsumerLag(dep, ep, lag)
+	} else {
+		s.metrics.DeleteConsumerLag(dep, ep)
+	}
+	if lag, ok := report.replicationLag(); ok {
+		s.metrics.SetReplicationLag(dep, ep, lag)
+	} else {
+		s.metrics.DeleteReplicationLag(dep, ep)
+	}
 
 	state.mu.Lock()
 	defer state.mu.Unlock()
 
+	// A successful check of a degraded endpoint reports status "degraded"
+	// with detail "latency_slo_exceeded"; app_dependency_health is not
+	// affected.
+	if s.updateDegraded(ctx, dep, state, checkErr, duration, logAttrs, isFirst) && result.Detail == "ok" {
+		result = CheckResult{Category: StatusDegraded, Detail: "latency_slo_exceeded"}
+	}
+
+	s.metrics.SetStatus(dep, ep, result.Category)
+	s.metrics.SetStatusDetail(dep, ep, result.Detail)
+
+	if result.Detail == "tls_expiring" && state.lastDetail != "tls_expiring" {
+		s.logger.LogAttrs(ctx, slog.LevelWarn, "dephealth: TLS certificate expires soon",
+			appendAttr(logAttrs, slog.Time("not_after", certExpiry))...)
+	}
+
 	// Store classification results for HealthDetails() API.
+	if !certExpiry.IsZero() {
+		state.tlsCertExpiry = certExpiry
+	}
 	state.lastStatus = result.Category
 	state.lastDetail = result.Detail
+	state.components = report.reportedComponents()
+	state.dependencies = report.reportedDependencies()
 	state.lastLatency = duration
 	state.lastCheckedAt = time.Now()
+	if checkErr != nil {
+		// safeCheck has already redacted the error.
+		state.lastError = truncateError(checkErr.Error())
+		state.lastFailureAt = state.lastCheckedAt
+	} else {
+		state.lastError = ""
+		state.lastSuccessAt = state.lastCheckedAt
+	}
 
 	if isFirst {
 		// First check: set state immediately without threshold logic.
@@ -387,8 +494,53 @@ func (s *Scheduler) executeCheck(
 	}
 }
 
+// updateDegraded applies the dependency's latency SLO to a finished check
+// and reports whether the endpoint is degraded. Slow successful checks are
+// counted against the failure threshold and fast ones against the success
+// threshold; a failed check resets the state. Must be called with state.mu held.
+func (s *Scheduler) updateDegraded(
+	ctx context.Context,
+	dep Dependency,
+	state *endpointState,
+	checkErr error,
+	duration time.Duration,
+	logAttrs []slog.Attr,
+	isFirst bool,
+) bool {
+	slo := dep.Config.LatencySLO
+	if slo <= 0 || checkErr != nil {
+		state.consecutiveSlow = 0
+		state.consecutiveFast = 0
+		state.degraded = false
+		return false
+	}
+
+	if duration > slo {
+		state.consecutiveFast = 0
+		state.consecutiveSlow++
+		if !state.degraded && (isFirst || state.consecutiveSlow >= dep.Config.FailureThreshold) {
+			state.degraded = true
+			s.logger.LogAttrs(ctx, slog.LevelWarn, "dephealth: dependency degraded",
+				appendAttr(logAttrs,
+					slog.Duration("latency", duration),
+					slog.Duration("latency_slo", slo),
+					slog.Int("consecutive_slow", state.consecutiveSlow))...)
+		}
+	} else {
+		state.consecutiveSlow = 0
+		state.consecutiveFast++
+		if state.degraded && state.consecutiveFast >= dep.Config.SuccessThreshold {
+			state.degraded = false
+			s.logger.LogAttrs(ctx, slog.LevelInfo, "dephealth: dependency latency recovered", logAttrs...)
+		}
+	}
+	return state.degraded
+}
+
 // AddEndpoint dynamically adds a new endpoint to the running scheduler.
-// It creates a new health check goroutine using the global check config.
+// It creates a new health check goroutine using the check config of the
+// dependency registered under depName and depType, or the global check
+// config when there is none.
 // If the endpoint already exists (same depName:host:port), the call is a no-op (idempotent).
 // Returns ErrNotStarted if the scheduler has not been started or has been stopped.
 func (s *Scheduler) AddEndpoint(depName string, depType DependencyType, critical bool, ep Endpoint, checker HealthChecker) error {
@@ -409,7 +561,7 @@ func (s *Scheduler) AddEndpoint(depName string, depType DependencyType, critical
 		Type:      depType,
 		Critical:  &critical,
 		Endpoints: []Endpoint{ep},
-		Config:    s.globalConfig,
+		Config:    s.depConfig(depName, depType),
 	}
 
 	labels := make(map[string]string, len(ep.Labels))
@@ -427,7 +579,10 @@ func (s *Scheduler) AddEndpoint(depName string, depType DependencyType, critical
 		port:       ep.Port,
 		critical:   critical,
 		labels:     labels,
+		checker:    checker,
+		config:     dep.Config,
 		cancel:     epCancel,
+		lifecycle:  s.acquireChecker(checker),
 	}
 
 	s.states[key] = st
@@ -476,7 +631,8 @@ func (s *Scheduler) RemoveEndpoint(depName, host, port string) error {
 
 // UpdateEndpoint atomically replaces an endpoint with a new one.
 // The old endpoint's goroutine is cancelled and its metrics are deleted.
-// A new goroutine is started for the new endpoint.
+// A new goroutine is started for the new endpoint with the old endpoint's
+// check config.
 // Returns ErrEndpointNotFound if the old endpoint does not exist.
 // Returns ErrNotStarted if the scheduler has not been started or has been stopped.
 func (s *Scheduler) UpdateEndpoint(depName, oldHost, oldPort string, newEp Endpoint, checker HealthChecker) error {
@@ -518,7 +674,7 @@ func (s *Scheduler) UpdateEndpoint(depName, oldHost, oldPort string, newEp Endpo
 		Type:      depType,
 		Critical:  &critical,
 		Endpoints: []Endpoint{newEp},
-		Config:    s.globalConfig,
+		Config:    oldSt.config,
 	}
 
 	labels := make(map[string]string, len(newEp.Labels))
@@ -536,7 +692,10 @@ func (s *Scheduler) UpdateEndpoint(depName, oldHost, oldPort string, newEp Endpo
 		port:       newEp.Port,
 		critical:   critical,
 		labels:     labels,
+		checker:    checker,
+		config:     dep.Config,
 		cancel:     epCancel,
+		lifecycle:  s.acquireChecker(checker),
 	}
 
 	newKey := depName + ":" + newEp.Host + ":" + newEp.Port
@@ -547,6 +706,51 @@ func (s *Scheduler) UpdateEndpoint(depName, oldHost, oldPort string, newEp Endpo
 	return nil
 }
 
+// depConfig returns the check config of the dependency registered under
+// name and depType, or the global check config when there is none.
+func (s *Scheduler) depConfig(name string, depType DependencyType) CheckConfig {
+	for _, sd := range s.deps {
+		if sd.dep.Name == name && sd.dep.Type == depType {
+			return sd.dep.Config
+		}
+	}
+	return s.globalConfig
+}
+
+// closeEndpoint calls CloseEndpoint on the checker after the last check of
+// state. It is skipped while another endpoint goroutine checks the same
+// address with the same checker instance (e.g. after UpdateEndpoint to the
+// same host and port), so the replaced goroutine, which exits
+// asynchronously, cannot close the connection its successor uses. s.mu is
+// held during the call so that no such goroutine is registered meanwhile.
+func (s *Scheduler) closeEndpoint(checker HealthChecker, ep Endpoint, state *endpointState) {
+	closer, ok := checker.(EndpointCloser)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopped && s.endpointInUse(checker, ep, state) {
+		return
+	}
+	closer.CloseEndpoint(ep)
+}
+
+// endpointInUse reports whether an endpoint state other than exclude checks
+// ep with checker. Must be called with s.mu held.
+func (s *Scheduler) endpointInUse(checker HealthChecker, ep Endpoint, exclude *endpointState) bool {
+	// Only comparable checkers can be identified as the same instance.
+	if !reflect.TypeOf(checker).Comparable() {
+		return false
+	}
+	for _, st := range s.states {
+		if st != exclude && st.host == ep.Host && st.port == ep.Port && st.checker == checker {
+			return true
+		}
+	}
+	return false
+}
+
 // appendAttr returns a new slice with extra appended, without mutating the original.
 // This prevents data races when logAttrs is shared across calls.
 func appendAttr(base []slog.Attr, extra ...slog.Attr) []slog.Attr {
@@ -556,8 +760,78 @@ func appendAttr(base []slog.Attr, extra ...slog.Attr) []slog.Attr {
 	return result
 }
 
-// safeCheck calls checker.Check with panic recovery.
-func (s *Scheduler) safeCheck(ctx context.Context, checker HealthChecker, ep Endpoint) (err error) {
+// acquireChecker registers an endpoint goroutine as a user of checker and
+// returns its lifecycle, or nil when the checker implements neither
+// Initializer nor io.Closer. Must be called with s.mu held.
+func (s *Scheduler) acquireChecker(checker HealthChecker) *checkerLifecycle {
+	_, isInit := checker.(Initializer)
+	_, isCloser := checker.(io.Closer)
+	if !isInit && !isCloser {
+		return nil
+	}
+	// Instances are tracked as map keys, which requires a comparable type.
+	if !reflect.TypeOf(checker).Comparable() {
+		s.logger.Warn("dephealth: checker type is not comparable, lifecycle hooks are not called",
+			"type", checker.Type(),
+		)
+		return nil
+	}
+
+	lc, ok := s.checkers[checker]
+	if !ok {
+		lc = &checkerLifecycle{}
+		s.checkers[checker] = lc
+	}
+	lc.refs++
+	return lc
+}
+
+// releaseChecker drops an endpoint goroutine's reference to checker and
+// closes the checker when it was the last one.
+func (s *Scheduler) releaseChecker(checker HealthChecker, lc *checkerLifecycle) {
+	s.mu.Lock()
+	lc.refs--
+	last := lc.refs == 0
+	if last {
+		delete(s.checkers, checker)
+	}
+	s.mu.Unlock()
+
+	if !last {
+		return
+	}
+	if closer, ok := checker.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			s.logger.Warn("dephealth: failed to close checker",
+				"type", checker.Type(),
+				"error", err,
+			)
+		}
+	}
+}
+
+// initChecker calls Init on the checker once; a failed Init is retried on
+// the next check.
+func initChecker(ctx context.Context, checker HealthChecker, lc *checkerLifecycle) error {
+	initializer, ok := checker.(Initializer)
+	if !ok || lc == nil {
+		return nil
+	}
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if lc.initialized {
+		return nil
+	}
+	if err := initializer.Init(ctx); err != nil {
+		return err
+	}
+	lc.initialized = true
+	return nil
+}
+
+// safeCheck initializes the checker if needed and calls checker.Check with
+// panic recovery. The returned error is redacted.
+func (s *Scheduler) safeCheck(ctx context.Context, checker HealthChecker, ep Endpoint, lc *checkerLifecycle) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("panic in health checker: %v", r)
@@ -566,6 +840,26 @@ func (s *Scheduler) safeCheck(ctx context.Context, checker HealthChecker, ep End
 				"panic", r,
 			)
 		}
+		err = RedactError(err)
 	}()
+	if err := initChecker(ctx, checker, lc); err != nil {
+		return err
+	}
 	return checker.Check(ctx, ep)
 }
+
+// safeClassify classifies err with the error classifiers of dep, with
+// panic recovery: if a classifier panics, the built-in chain alone
+// classifies the error.
+func (s *Scheduler) safeClassify(err error, dep Dependency, ep Endpoint) (result CheckResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("dephealth: panic in error classifier",
+				"endpoint", ep.Host+":"+ep.Port,
+				"panic", r,
+			)
+			result = classifyError(err)
+		}
+	}()
+	return classifyError(err, errorClassifiersFor(dep)...)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/goleak"
 )
 
 // mockChecker is a mock checker for scheduler tests.
@@ -30,6 +31,110 @@ func (m *mockChecker) Check(ctx context.Context, ep Endpoint) error {
 
 func (m *mockChecker) Type() string { return "mock" }
 
+// closingChecker records the endpoints released through CloseEndpoint.
+type closingChecker struct {
+	mockChecker
+	mu     sync.Mutex
+	closed []string
+}
+
+func (c *closingChecker) CloseEndpoint(ep Endpoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = append(c.closed, ep.Host+":"+ep.Port)
+}
+
+func (c *closingChecker) closedEndpoints() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.closed...)
+}
+
+// connChecker shares one connection per address between checks, like the
+// persistent modes of httpcheck and grpccheck. Checks block on gate until it
+// is closed and fail when their connection was closed meanwhile.
+type connChecker struct {
+	gate    chan struct{}
+	started chan struct{}
+
+	mu     sync.Mutex
+	conns  map[string]*atomic.Bool // addr -> closed
+	closes int
+}
+
+func (c *connChecker) Check(_ context.Context, ep Endpoint) error {
+	addr := ep.Host + ":" + ep.Port
+	c.mu.Lock()
+	closed, ok := c.conns[addr]
+	if !ok {
+		closed = &atomic.Bool{}
+		c.conns[addr] = closed
+	}
+	c.mu.Unlock()
+
+	select {
+	case c.started <- struct{}{}:
+	default:
+	}
+	<-c.gate
+	if closed.Load() {
+		return errors.New("client connection is closing")
+	}
+	return nil
+}
+
+func (c *connChecker) Type() string { return "mock" }
+
+func (c *connChecker) CloseEndpoint(ep Endpoint) {
+	addr := ep.Host + ":" + ep.Port
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closes++
+	if closed, ok := c.conns[addr]; ok {
+		closed.Store(true)
+		delete(c.conns, addr)
+	}
+}
+
+func (c *connChecker) closeCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closes
+}
+
+// lifecycleChecker runs a background goroutine between Init and Close.
+type lifecycleChecker struct {
+	mockChecker
+	initErr    atomic.Pointer[error]
+	initCount  atomic.Int64
+	closeCount atomic.Int64
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+func (c *lifecycleChecker) Init(_ context.Context) error {
+	c.initCount.Add(1)
+	if err := c.initErr.Load(); err != nil {
+		return *err
+	}
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		<-c.stop
+	}()
+	return nil
+}
+
+func (c *lifecycleChecker) Close() error {
+	c.closeCount.Add(1)
+	if c.stop != nil {
+		close(c.stop)
+		<-c.done
+	}
+	return nil
+}
+
 // panicChecker is a checker that panics.
 type panicChecker struct{}
 
@@ -209,6 +314,281 @@ func TestScheduler_UnhealthyMetric(t *testing.T) {
 	}
 }
 
+func TestScheduler_ErrorClassifier(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	checker := &mockChecker{
+		checkFunc: func(_ context.Context, _ Endpoint) error {
+			return fmt.Errorf("query: %w", &driverError{code: "28P01"})
+		},
+	}
+	dep := testDep("test-dep", 100*time.Millisecond, 50*time.Millisecond, 0)
+	dep.Config.ErrorClassifiers = []ErrorClassifier{
+		driverCodeClassifier("28P01", CheckResult{Category: StatusAuthError, Detail: "auth_error"}),
+	}
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+
+	time.Sleep(50 * time.Millisecond)
+	sched.Stop()
+
+	es := sched.HealthDetails()["test-dep:127.0.0.1:1234"]
+	if es.Status != StatusAuthError || es.Detail != "auth_error" {
+		t.Errorf("status = %s/%s, expected auth_error/auth_error", es.Status, es.Detail)
+	}
+}
+
+func TestScheduler_ErrorClassifier_Panic(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	checker := &mockChecker{
+		checkFunc: func(_ context.Context, _ Endpoint) error {
+			return fmt.Errorf("query: %w", ErrConnectionRefused)
+		},
+	}
+	dep := testDep("test-dep", 100*time.Millisecond, 50*time.Millisecond, 0)
+	dep.Config.ErrorClassifiers = []ErrorClassifier{
+		func(error) (CheckResult, bool) { panic("classifier panic") },
+	}
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+
+	time.Sleep(50 * time.Millisecond)
+	sched.Stop()
+
+	// The built-in chain classifies the error instead.
+	es := sched.HealthDetails()["test-dep:127.0.0.1:1234"]
+	if es.Status != StatusConnectionError || es.Detail != "connection_refused" {
+		t.Errorf("status = %s/%s, expected connection_error/connection_refused", es.Status, es.Detail)
+	}
+	if es.LastCheckedAt.IsZero() {
+		t.Error("expected the check to be recorded after the classifier panic")
+	}
+}
+
+func TestScheduler_ErrorClassifier_AfterUpdateEndpoint(t *testing.T) {
+	sched, _ := newTestSchedulerFast(t)
+
+	checker := &mockChecker{
+		checkFunc: func(_ context.Context, _ Endpoint) error {
+			return fmt.Errorf("query: %w", &driverError{code: "28P01"})
+		},
+	}
+	dep := testDep("test-dep", 100*time.Millisecond, 50*time.Millisecond, 0)
+	dep.Config.ErrorClassifiers = []ErrorClassifier{
+		driverCodeClassifier("28P01", CheckResult{Category: StatusAuthError, Detail: "auth_error"}),
+	}
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+	newEp := Endpoint{Host: "127.0.0.2", Port: "1234"}
+	if err := sched.UpdateEndpoint("test-dep", "127.0.0.1", "1234", newEp, checker); err != nil {
+		t.Fatalf("UpdateEndpoint error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	es := sched.HealthDetails()["test-dep:127.0.0.2:1234"]
+	if es.Status != StatusAuthError || es.Detail != "auth_error" {
+		t.Errorf("status after update = %s/%s, expected auth_error/auth_error", es.Status, es.Detail)
+	}
+}
+
+func TestScheduler_AddEndpoint_InheritsDependencyConfig(t *testing.T) {
+	sched, _ := newTestSchedulerFast(t)
+
+	checker := &mockChecker{
+		checkFunc: func(_ context.Context, _ Endpoint) error {
+			return fmt.Errorf("query: %w", &driverError{code: "28P01"})
+		},
+	}
+	dep := testDep("test-dep", 100*time.Millisecond, 50*time.Millisecond, 0)
+	dep.Config.ErrorClassifiers = []ErrorClassifier{
+		driverCodeClassifier("28P01", CheckResult{Category: StatusAuthError, Detail: "auth_error"}),
+	}
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	_ = sched.RemoveEndpoint("test-dep", "127.0.0.1", "1234")
+	_ = sched.AddEndpoint("test-dep", TypeTCP, false, Endpoint{Host: "127.0.0.1", Port: "1234"}, checker)
+	_ = sched.AddEndpoint("other-dep", TypeTCP, false, Endpoint{Host: "127.0.0.1", Port: "1234"}, checker)
+	time.Sleep(50 * time.Millisecond)
+
+	details := sched.HealthDetails()
+	if es := details["test-dep:127.0.0.1:1234"]; es.Status != StatusAuthError {
+		t.Errorf("re-added endpoint status = %s, expected auth_error from the dependency classifier", es.Status)
+	}
+	if es := details["other-dep:127.0.0.1:1234"]; es.Status != StatusError {
+		t.Errorf("unregistered dependency status = %s, expected error", es.Status)
+	}
+}
+
+func TestScheduler_UpdateDegraded(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+	dep := testDepWithThresholds("test-dep", 2, 2)
+	dep.Config.LatencySLO = 10 * time.Millisecond
+
+	slow, fast := 20*time.Millisecond, 5*time.Millisecond
+	fail := errors.New("fail")
+	steps := []struct {
+		name     string
+		err      error
+		duration time.Duration
+		want     bool
+	}{
+		{"first slow check", nil, slow, true},
+		{"one fast check", nil, fast, true},
+		{"success threshold reached", nil, fast, false},
+		{"one slow check", nil, slow, false},
+		{"failure threshold reached", nil, slow, true},
+		{"failed check resets", fail, slow, false},
+		{"slow after failure", nil, slow, false},
+		{"degraded again", nil, slow, true},
+	}
+	state := &endpointState{}
+	for i, step := range steps {
+		got := sched.updateDegraded(context.Background(), dep, state, step.err, step.duration, nil, i == 0)
+		if got != step.want {
+			t.Fatalf("step %d (%s): degraded = %v, expected %v", i, step.name, got, step.want)
+		}
+	}
+
+	dep.Config.LatencySLO = 0
+	if sched.updateDegraded(context.Background(), dep, state, nil, time.Hour, nil, false) {
+		t.Error("a dependency without latency SLO should never be degraded")
+	}
+}
+
+func TestScheduler_LatencySLO_Degraded(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	checker := &mockChecker{
+		checkFunc: func(_ context.Context, _ Endpoint) error {
+			time.Sleep(15 * time.Millisecond)
+			return nil
+		},
+	}
+	dep := testDep("test-dep", 100*time.Millisecond, 50*time.Millisecond, 0)
+	dep.Config.LatencySLO = 5 * time.Millisecond
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+
+	time.Sleep(50 * time.Millisecond)
+	sched.Stop()
+
+	es := sched.HealthDetails()["test-dep:127.0.0.1:1234"]
+	if es.Status != StatusDegraded || es.Detail != "latency_slo_exceeded" {
+		t.Errorf("status = %s/%s, expected degraded/latency_slo_exceeded", es.Status, es.Detail)
+	}
+	if es.Healthy == nil || !*es.Healthy {
+		t.Error("a degraded dependency should stay healthy")
+	}
+
+	expected := `
+		# HELP app_dependency_health Health status of a dependency (1 = healthy, 0 = unhealthy)
+		# TYPE app_dependency_health gauge
+		app_dependency_health{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp"} 1
+	`
+	if err := testutil.CollectAndCompare(sched.metrics.health, strings.NewReader(expected)); err != nil {
+		t.Errorf("health metric mismatch: %v", err)
+	}
+	if n := testutil.CollectAndCount(sched.metrics.status); n != len(AllStatusCategories) {
+		t.Errorf("expected %d status series, got %d", len(AllStatusCategories), n)
+	}
+	if v := statusGauge(sched, dep, StatusDegraded); v != 1 {
+		t.Errorf("status degraded = %v, expected 1", v)
+	}
+	expected = `
+		# HELP app_dependency_status_detail Detailed reason of the last check result
+		# TYPE app_dependency_status_detail gauge
+		app_dependency_status_detail{critical="no",dependency="test-dep",detail="latency_slo_exceeded",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp"} 1
+	`
+	if err := testutil.CollectAndCompare(sched.metrics.statusDetail, strings.NewReader(expected)); err != nil {
+		t.Errorf("status detail metric mismatch: %v", err)
+	}
+}
+
+// statusGauge returns the app_dependency_status value of category for the
+// first endpoint of dep.
+func statusGauge(s *Scheduler, dep Dependency, category StatusCategory) float64 {
+	labels := copyLabels(s.metrics.labels(dep, dep.Endpoints[0]))
+	labels["status"] = string(category)
+	return testutil.ToFloat64(s.metrics.status.With(labels))
+}
+
+func TestScheduler_LatencySLO_AfterUpdateEndpoint(t *testing.T) {
+	sched, _ := newTestSchedulerFast(t)
+
+	checker := &mockChecker{
+		checkFunc: func(_ context.Context, _ Endpoint) error {
+			time.Sleep(15 * time.Millisecond)
+			return nil
+		},
+	}
+	dep := testDep("test-dep", 100*time.Millisecond, 50*time.Millisecond, 0)
+	dep.Config.LatencySLO = 5 * time.Millisecond
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+	newEp := Endpoint{Host: "127.0.0.2", Port: "1234"}
+	if err := sched.UpdateEndpoint("test-dep", "127.0.0.1", "1234", newEp, checker); err != nil {
+		t.Fatalf("UpdateEndpoint error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	es := sched.HealthDetails()["test-dep:127.0.0.2:1234"]
+	if es.Status != StatusDegraded || es.Detail != "latency_slo_exceeded" {
+		t.Errorf("status after update = %s/%s, expected degraded/latency_slo_exceeded", es.Status, es.Detail)
+	}
+}
+
+func TestScheduler_LatencySLO_RecoversToOK(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	var slow atomic.Bool
+	slow.Store(true)
+	checker := &mockChecker{
+		checkFunc: func(_ context.Context, _ Endpoint) error {
+			if slow.Load() {
+				time.Sleep(15 * time.Millisecond)
+			}
+			return nil
+		},
+	}
+	dep := testDep("test-dep", 20*time.Millisecond, 50*time.Millisecond, 0)
+	dep.Config.LatencySLO = 5 * time.Millisecond
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	time.Sleep(30 * time.Millisecond)
+	if es := sched.HealthDetails()["test-dep:127.0.0.1:1234"]; es.Status != StatusDegraded {
+		t.Fatalf("status = %s/%s, expected degraded", es.Status, es.Detail)
+	}
+
+	// Latency falls below the SLO: after the success threshold of fast
+	// checks the endpoint is "ok" again.
+	slow.Store(false)
+	deadline := time.Now().Add(time.Second)
+	for {
+		es := sched.HealthDetails()["test-dep:127.0.0.1:1234"]
+		if es.Status == StatusOK && es.Detail == "ok" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("status = %s/%s, expected ok/ok after latency recovered", es.Status, es.Detail)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if v := statusGauge(sched, dep, StatusDegraded); v != 0 {
+		t.Errorf("status degraded = %v, expected 0 after recovery", v)
+	}
+}
+
 func TestScheduler_FailureThreshold(t *testing.T) {
 	reg := prometheus.NewRegistry()
 	metrics, _ := NewMetricsExporter("test-app", "test-group", WithMetricsRegisterer(reg))
@@ -617,6 +997,171 @@ func TestScheduler_RemoveEndpoint(t *testing.T) {
 	}
 }
 
+func TestScheduler_CloseEndpoint(t *testing.T) {
+	sched, _ := newTestSchedulerFast(t)
+
+	checker := &closingChecker{}
+	_ = sched.Start(context.Background())
+
+	_ = sched.AddEndpoint("grpc-dynamic", TypeGRPC, false, Endpoint{Host: "10.0.0.1", Port: "9090"}, checker)
+	_ = sched.AddEndpoint("grpc-dynamic", TypeGRPC, false, Endpoint{Host: "10.0.0.2", Port: "9090"}, checker)
+	time.Sleep(100 * time.Millisecond)
+
+	_ = sched.RemoveEndpoint("grpc-dynamic", "10.0.0.1", "9090")
+	deadline := time.Now().Add(time.Second)
+	for len(checker.closedEndpoints()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := checker.closedEndpoints(); len(got) != 1 || got[0] != "10.0.0.1:9090" {
+		t.Fatalf("closed after RemoveEndpoint = %v, expected [10.0.0.1:9090]", got)
+	}
+
+	sched.Stop()
+	if got := checker.closedEndpoints(); len(got) != 2 || got[1] != "10.0.0.2:9090" {
+		t.Errorf("closed after Stop = %v, expected [10.0.0.1:9090 10.0.0.2:9090]", got)
+	}
+}
+
+func TestScheduler_CloseEndpoint_UpdateToSameAddress(t *testing.T) {
+	sched, _ := newTestSchedulerFast(t)
+
+	checker := &connChecker{
+		gate:    make(chan struct{}),
+		started: make(chan struct{}),
+		conns:   make(map[string]*atomic.Bool),
+	}
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	ep := Endpoint{Host: "10.0.0.1", Port: "9090"}
+	_ = sched.AddEndpoint("grpc-dynamic", TypeGRPC, false, ep, checker)
+	<-checker.started
+
+	// A label change replaces the endpoint at the same address; the new
+	// goroutine's check shares the connection with the old in-flight one.
+	newEp := Endpoint{Host: "10.0.0.1", Port: "9090", Labels: map[string]string{"zone": "b"}}
+	if err := sched.UpdateEndpoint("grpc-dynamic", "10.0.0.1", "9090", newEp, checker); err != nil {
+		t.Fatalf("UpdateEndpoint error: %v", err)
+	}
+	<-checker.started
+
+	// Release both checks; the old goroutine exits while the new check runs.
+	close(checker.gate)
+	key := "grpc-dynamic:10.0.0.1:9090"
+	deadline := time.Now().Add(time.Second)
+	for sched.HealthDetails()[key].LastCheckedAt.IsZero() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	es := sched.HealthDetails()[key]
+	if es.Healthy == nil || !*es.Healthy || es.Error != "" {
+		t.Errorf("new endpoint healthy = %v, error = %q, expected healthy", es.Healthy, es.Error)
+	}
+	if got := checker.closeCount(); got != 0 {
+		t.Errorf("CloseEndpoint calls after update to the same address = %d, expected 0", got)
+	}
+
+	_ = sched.RemoveEndpoint("grpc-dynamic", "10.0.0.1", "9090")
+	deadline = time.Now().Add(time.Second)
+	for checker.closeCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := checker.closeCount(); got != 1 {
+		t.Errorf("CloseEndpoint calls after RemoveEndpoint = %d, expected 1", got)
+	}
+}
+
+func TestScheduler_InitAndClose(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	sched, _ := newTestScheduler(t)
+	checker := &lifecycleChecker{}
+	dep := testDep("test-dep", 50*time.Millisecond, 50*time.Millisecond, 0)
+	dep.Endpoints = append(dep.Endpoints, Endpoint{Host: "127.0.0.2", Port: "1234"})
+	addTestDep(sched, dep, checker)
+
+	if err := sched.Start(context.Background()); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	time.Sleep(150 * time.Millisecond)
+
+	if got := checker.initCount.Load(); got != 1 {
+		t.Errorf("Init calls = %d, expected 1 for a checker shared by two endpoints", got)
+	}
+	if got := checker.closeCount.Load(); got != 0 {
+		t.Errorf("Close calls before Stop = %d, expected 0", got)
+	}
+
+	sched.Stop()
+	if got := checker.closeCount.Load(); got != 1 {
+		t.Errorf("Close calls after Stop = %d, expected 1", got)
+	}
+}
+
+func TestScheduler_InitFailureRetried(t *testing.T) {
+	sched, _ := newTestSchedulerFast(t)
+	checker := &lifecycleChecker{}
+	initErr := errors.New("pool unavailable")
+	checker.initErr.Store(&initErr)
+
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+	_ = sched.AddEndpoint("lifecycle", TypeTCP, false, Endpoint{Host: "10.0.0.1", Port: "80"}, checker)
+
+	time.Sleep(150 * time.Millisecond)
+	if healthy, ok := sched.Health()["lifecycle:10.0.0.1:80"]; !ok || healthy {
+		t.Fatalf("health with failing Init = %v (present %v), expected unhealthy", healthy, ok)
+	}
+	if got := checker.callCount.Load(); got != 0 {
+		t.Errorf("Check calls with failing Init = %d, expected 0", got)
+	}
+
+	checker.initErr.Store(nil)
+	deadline := time.Now().Add(time.Second)
+	for !sched.Health()["lifecycle:10.0.0.1:80"] && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !sched.Health()["lifecycle:10.0.0.1:80"] {
+		t.Fatal("expected the endpoint to become healthy after Init succeeds")
+	}
+	if got := checker.initCount.Load(); got < 2 {
+		t.Errorf("Init calls = %d, expected the failed Init to be retried", got)
+	}
+}
+
+func TestScheduler_CloseAfterLastEndpoint(t *testing.T) {
+	sched, _ := newTestSchedulerFast(t)
+	checker := &lifecycleChecker{}
+
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	_ = sched.AddEndpoint("lifecycle", TypeTCP, false, Endpoint{Host: "10.0.0.1", Port: "80"}, checker)
+	_ = sched.AddEndpoint("lifecycle", TypeTCP, false, Endpoint{Host: "10.0.0.2", Port: "80"}, checker)
+	time.Sleep(50 * time.Millisecond)
+
+	// Replacing an endpoint with the same checker keeps it open.
+	_ = sched.UpdateEndpoint("lifecycle", "10.0.0.1", "80", Endpoint{Host: "10.0.0.3", Port: "80"}, checker)
+	_ = sched.RemoveEndpoint("lifecycle", "10.0.0.2", "80")
+	time.Sleep(50 * time.Millisecond)
+	if got := checker.closeCount.Load(); got != 0 {
+		t.Fatalf("Close calls while an endpoint remains = %d, expected 0", got)
+	}
+
+	_ = sched.RemoveEndpoint("lifecycle", "10.0.0.3", "80")
+	deadline := time.Now().Add(time.Second)
+	for checker.closeCount.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := checker.closeCount.Load(); got != 1 {
+		t.Errorf("Close calls after the last endpoint is removed = %d, expected 1", got)
+	}
+	if got := checker.initCount.Load(); got != 1 {
+		t.Errorf("Init calls = %d, expected 1", got)
+	}
+}
+
 func TestScheduler_RemoveEndpoint_Idempotent(t *testing.T) {
 	sched, _ := newTestSchedulerFast(t)
 
@@ -954,3 +1499,323 @@ func TestScheduler_RemoveEndpoint_AfterStop(t *testing.T) {
 		t.Errorf("expected ErrNotStarted after Stop, got: %v", err)
 	}
 }
+
+func TestScheduler_TLSCertExpiry(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	notAfter := time.Unix(1893456000, 0) // 2030-01-01T00:00:00Z
+	checker := &mockChecker{checkFunc: func(ctx context.Context, _ Endpoint) error {
+		ReportTLSCertExpiry(ctx, notAfter)
+		return nil
+	}}
+	dep := testDep("test-dep", 100*time.Millisecond, 50*time.Millisecond, 0)
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+
+	time.Sleep(150 * time.Millisecond)
+	details := sched.HealthDetails()
+	sched.Stop()
+
+	expected := `
+		# HELP app_dependency_tls_cert_expiry_timestamp_seconds Expiry time of the dependency TLS leaf certificate as a Unix timestamp
+		# TYPE app_dependency_tls_cert_expiry_timestamp_seconds gauge
+		app_dependency_tls_cert_expiry_timestamp_seconds{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp"} 1.893456e+09
+	`
+	if err := testutil.CollectAndCompare(sched.metrics.tlsExpiry, strings.NewReader(expected)); err != nil {
+		t.Errorf("tls expiry metric mismatch: %v", err)
+	}
+
+	es := details["test-dep:127.0.0.1:1234"]
+	if !es.TLSCertExpiry.Equal(notAfter) {
+		t.Errorf("TLSCertExpiry = %v, expected %v", es.TLSCertExpiry, notAfter)
+	}
+	if es.Detail != "ok" {
+		t.Errorf("Detail = %q, expected %q (no warning window configured)", es.Detail, "ok")
+	}
+}
+
+func TestScheduler_ConsumerLag(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	checker := &mockChecker{checkFunc: func(ctx context.Context, _ Endpoint) error {
+		ReportConsumerLag(ctx, 1500)
+		return nil
+	}}
+	dep := testDep("test-dep", 100*time.Millisecond, 50*time.Millisecond, 0)
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+
+	time.Sleep(150 * time.Millisecond)
+	sched.Stop()
+
+	expected := `
+		# HELP app_dependency_consumer_lag Total lag in messages of the Kafka consumer group checked by the dependency
+		# TYPE app_dependency_consumer_lag gauge
+		app_dependency_consumer_lag{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp"} 1500
+	`
+	if err := testutil.CollectAndCompare(sched.metrics.consumerLag, strings.NewReader(expected)); err != nil {
+		t.Errorf("consumer lag metric mismatch: %v", err)
+	}
+
+	sched.metrics.DeleteMetrics(dep, dep.Endpoints[0])
+	if n := testutil.CollectAndCount(sched.metrics.consumerLag); n != 0 {
+		t.Errorf("expected consumer lag series deleted, got %d", n)
+	}
+}
+
+func TestScheduler_ConsumerLag_DeletedOnFailure(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	var missing atomic.Bool
+	checker := &mockChecker{checkFunc: func(ctx context.Context, _ Endpoint) error {
+		if missing.Load() {
+			return &ClassifiedCheckError{Category: StatusUnhealthy, Detail: "consumer_group_missing"}
+		}
+		ReportConsumerLag(ctx, 1500)
+		return nil
+	}}
+	dep := testDep("test-dep", 50*time.Millisecond, 50*time.Millisecond, 0)
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	time.Sleep(80 * time.Millisecond)
+	if n := testutil.CollectAndCount(sched.metrics.consumerLag); n != 1 {
+		t.Fatalf("expected consumer lag series, got %d", n)
+	}
+
+	missing.Store(true)
+	time.Sleep(120 * time.Millisecond)
+	if n := testutil.CollectAndCount(sched.metrics.consumerLag); n != 0 {
+		t.Errorf("expected consumer lag series deleted after failed check, got %d", n)
+	}
+}
+
+func TestScheduler_ReplicationLag(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	checker := &mockChecker{checkFunc: func(ctx context.Context, _ Endpoint) error {
+		ReportReplicationLag(ctx, 2500*time.Millisecond)
+		return nil
+	}}
+	dep := testDep("test-dep", 100*time.Millisecond, 50*time.Millisecond, 0)
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+
+	time.Sleep(150 * time.Millisecond)
+	sched.Stop()
+
+	expected := `
+		# HELP app_dependency_replication_lag_seconds Replication lag of the database replica in seconds
+		# TYPE app_dependency_replication_lag_seconds gauge
+		app_dependency_replication_lag_seconds{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp"} 2.5
+	`
+	if err := testutil.CollectAndCompare(sched.metrics.replLag, strings.NewReader(expected)); err != nil {
+		t.Errorf("replication lag metric mismatch: %v", err)
+	}
+}
+
+func TestScheduler_ReplicationLag_DeletedWhenNotReplica(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	var promoted atomic.Bool
+	checker := &mockChecker{checkFunc: func(ctx context.Context, _ Endpoint) error {
+		if !promoted.Load() {
+			ReportReplicationLag(ctx, 2500*time.Millisecond)
+		}
+		return nil
+	}}
+	dep := testDep("test-dep", 50*time.Millisecond, 50*time.Millisecond, 0)
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	time.Sleep(80 * time.Millisecond)
+	if n := testutil.CollectAndCount(sched.metrics.replLag); n != 1 {
+		t.Fatalf("expected replication lag series, got %d", n)
+	}
+
+	promoted.Store(true)
+	time.Sleep(120 * time.Millisecond)
+	if n := testutil.CollectAndCount(sched.metrics.replLag); n != 0 {
+		t.Errorf("expected replication lag series deleted after promotion, got %d", n)
+	}
+}
+
+func TestScheduler_ReplicationLag_DeletedWhenCheckFails(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	var down atomic.Bool
+	checker := &mockChecker{checkFunc: func(ctx context.Context, _ Endpoint) error {
+		if down.Load() {
+			return errors.New("connection refused")
+		}
+		ReportReplicationLag(ctx, 2500*time.Millisecond)
+		return nil
+	}}
+	dep := testDep("test-dep", 50*time.Millisecond, 50*time.Millisecond, 0)
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	time.Sleep(80 * time.Millisecond)
+	if n := testutil.CollectAndCount(sched.metrics.replLag); n != 1 {
+		t.Fatalf("expected replication lag series, got %d", n)
+	}
+
+	down.Store(true)
+	time.Sleep(120 * time.Millisecond)
+	if n := testutil.CollectAndCount(sched.metrics.replLag); n != 0 {
+		t.Errorf("expected replication lag series deleted after a failed check, got %d", n)
+	}
+}
+
+func TestScheduler_ReportComponentsAndDetail(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	var down atomic.Bool
+	checker := &mockChecker{checkFunc: func(ctx context.Context, _ Endpoint) error {
+		ReportComponents(ctx, map[string]ComponentStatus{"db": {Status: "UP", Healthy: true}})
+		if down.Load() {
+			ReportDetail(ctx, "health_warn") // ignored for a failed check
+			return &ClassifiedCheckError{Category: StatusUnhealthy, Detail: "health_down"}
+		}
+		ReportDetail(ctx, "health_warn")
+		return nil
+	}}
+	dep := testDep("test-dep", 50*time.Millisecond, 50*time.Millisecond, 0)
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	time.Sleep(80 * time.Millisecond)
+	es := sched.HealthDetails()["test-dep:127.0.0.1:1234"]
+	if es.Status != StatusOK || es.Detail != "health_warn" {
+		t.Errorf("status/detail = %s/%s, expected ok/health_warn", es.Status, es.Detail)
+	}
+	if es.Components["db"].Status != "UP" {
+		t.Errorf("components = %v, expected db UP", es.Components)
+	}
+	if got := testutil.ToFloat64(sched.metrics.statusDetail.WithLabelValues(
+		"test-app", "test-group", "test-dep", "tcp", "127.0.0.1", "1234", "no", "health_warn")); got != 1 {
+		t.Errorf("status_detail{detail=health_warn} = %v, expected 1", got)
+	}
+
+	down.Store(true)
+	time.Sleep(80 * time.Millisecond)
+	es = sched.HealthDetails()["test-dep:127.0.0.1:1234"]
+	if es.Detail != "health_down" {
+		t.Errorf("detail after failure = %s, expected health_down", es.Detail)
+	}
+
+	// The returned map is a copy.
+	es.Components["db"] = ComponentStatus{Status: "DOWN"}
+	if sched.HealthDetails()["test-dep:127.0.0.1:1234"].Components["db"].Status != "UP" {
+		t.Error("HealthDetails components share state with the scheduler")
+	}
+}
+
+func TestScheduler_ReportDependencies(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	var fail atomic.Bool
+	checker := &mockChecker{checkFunc: func(ctx context.Context, _ Endpoint) error {
+		if fail.Load() {
+			return errors.New("connection refused")
+		}
+		ReportDependencies(ctx, map[string]EndpointStatus{
+			"postgres:pg.svc:5432": {Status: StatusOK, Name: "postgres", Critical: true},
+		})
+		return nil
+	}}
+	dep := testDep("test-dep", 50*time.Millisecond, 50*time.Millisecond, 0)
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+	defer sched.Stop()
+
+	time.Sleep(80 * time.Millisecond)
+	es := sched.HealthDetails()["test-dep:127.0.0.1:1234"]
+	if es.Dependencies["postgres:pg.svc:5432"].Name != "postgres" {
+		t.Errorf("dependencies = %v, expected postgres", es.Dependencies)
+	}
+
+	// Edges are only kept while the dependency reports them.
+	fail.Store(true)
+	time.Sleep(80 * time.Millisecond)
+	if deps := sched.HealthDetails()["test-dep:127.0.0.1:1234"].Dependencies; deps != nil {
+		t.Errorf("dependencies after failure = %v, expected nil", deps)
+	}
+}
+
+func TestScheduler_ConnectLatency(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	var calls atomic.Int64
+	checker := &mockChecker{checkFunc: func(ctx context.Context, _ Endpoint) error {
+		// Only the first check opens a new connection.
+		if calls.Add(1) == 1 {
+			ReportConnectTime(ctx, 30*time.Millisecond)
+		}
+		return nil
+	}}
+	dep := testDep("test-dep", 50*time.Millisecond, 40*time.Millisecond, 0)
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+
+	time.Sleep(180 * time.Millisecond)
+	sched.Stop()
+
+	if calls.Load() < 2 {
+		t.Fatalf("expected at least 2 checks, got %d", calls.Load())
+	}
+	expected := `
+		# HELP app_dependency_connect_latency_seconds Latency of establishing a new connection to the dependency in seconds
+		# TYPE app_dependency_connect_latency_seconds histogram
+		app_dependency_connect_latency_seconds_bucket{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp",le="0.001"} 0
+		app_dependency_connect_latency_seconds_bucket{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp",le="0.005"} 0
+		app_dependency_connect_latency_seconds_bucket{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp",le="0.01"} 0
+		app_dependency_connect_latency_seconds_bucket{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp",le="0.05"} 1
+		app_dependency_connect_latency_seconds_bucket{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp",le="0.1"} 1
+		app_dependency_connect_latency_seconds_bucket{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp",le="0.5"} 1
+		app_dependency_connect_latency_seconds_bucket{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp",le="1"} 1
+		app_dependency_connect_latency_seconds_bucket{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp",le="5"} 1
+		app_dependency_connect_latency_seconds_bucket{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp",le="+Inf"} 1
+		app_dependency_connect_latency_seconds_sum{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp"} 0.03
+		app_dependency_connect_latency_seconds_count{critical="no",dependency="test-dep",group="test-group",host="127.0.0.1",name="test-app",port="1234",type="tcp"} 1
+	`
+	if err := testutil.CollectAndCompare(sched.metrics.connect, strings.NewReader(expected)); err != nil {
+		t.Errorf("connect latency metric mismatch: %v", err)
+	}
+}
+
+func TestScheduler_TLSCertExpiring(t *testing.T) {
+	sched, _ := newTestScheduler(t)
+
+	checker := &mockChecker{checkFunc: func(ctx context.Context, _ Endpoint) error {
+		ReportTLSCertExpiry(ctx, time.Now().Add(24*time.Hour))
+		return nil
+	}}
+	dep := testDep("test-dep", 100*time.Millisecond, 50*time.Millisecond, 0)
+	dep.Config.TLSExpiryWarning = 7 * 24 * time.Hour
+	addTestDep(sched, dep, checker)
+	_ = sched.Start(context.Background())
+
+	time.Sleep(150 * time.Millisecond)
+	details := sched.HealthDetails()
+	sched.Stop()
+
+	es := details["test-dep:127.0.0.1:1234"]
+	if es.Status != StatusOK {
+		t.Errorf("Status = %q, expected %q", es.Status, StatusOK)
+	}
+	if es.Detail != "tls_expiring" {
+		t.Errorf("Detail = %q, expected %q", es.Detail, "tls_expiring")
+	}
+	if es.Healthy == nil || !*es.Healthy {
+		t.Error("expected endpoint to stay healthy while the certificate is expiring")
+	}
+	if got := testutil.ToFloat64(sched.metrics.statusDetail.WithLabelValues(
+		"test-app", "test-group", "test-dep", "tcp", "127.0.0.1", "1234", "no", "tls_expiring")); got != 1 {
+		t.Errorf("status_detail{detail=tls_expiring} = %v, expected 1", got)
+	}
+}